@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxAnnotationValueBytes is the soft limit this controller enforces on any single
+// annotation value it writes. Kubernetes caps the total size of all annotations on
+// an object at 256KiB, but a single release tag can accumulate several large
+// annotations (verify, publish, etc), so we keep well under that to leave room for
+// the others.
+const maxAnnotationValueBytes = 48 * 1024
+
+var (
+	annotationOverflowTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "release_controller_annotation_overflow_total",
+		Help: "Number of times an annotation value was truncated because it exceeded the configured size guardrail.",
+	}, []string{"annotation"})
+)
+
+func init() {
+	prometheus.MustRegister(annotationOverflowTotal)
+}
+
+// retrySummary is the truncated form an overflowing VerificationStatusMap entry is
+// rewritten into. It keeps each step's State and URL, and SummarizedAt records
+// when the entry was summarized, so callers can tell something was dropped. The
+// step's Message, Fingerprint, TimedOut and per-source results are discarded.
+// summarizeVerificationStatus does not fill in Retries: a VerificationStatus
+// carries no retry history to count.
+type retrySummary struct {
+	State        string `json:"state"`
+	URL          string `json:"url"`
+	Retries      int    `json:"retries,omitempty"`
+	SummarizedAt string `json:"summarizedAt,omitempty"`
+}
+
+// limitAnnotationSize returns value unchanged if it is within maxAnnotationValueBytes.
+// Otherwise, if value is a JSON encoded VerificationStatusMap, it collapses each
+// entry into a retrySummary holding only its State and URL, which is usually
+// enough to bring a verify blob back under the limit. If it still doesn't fit (or
+// isn't a VerificationStatusMap at all), value is hard-truncated and a warning is
+// logged so the overflow is visible in the controller logs and metrics.
+func limitAnnotationSize(annotation, value string) string {
+	if len(value) <= maxAnnotationValueBytes {
+		return value
+	}
+	annotationOverflowTotal.WithLabelValues(annotation).Inc()
+
+	if summarized, ok := summarizeVerificationStatus(value); ok && len(summarized) <= maxAnnotationValueBytes {
+		glog.V(2).Infof("Annotation %s was %d bytes, summarized retry history to %d bytes", annotation, len(value), len(summarized))
+		return summarized
+	}
+
+	glog.Warningf("Annotation %s is %d bytes, exceeding the %d byte guardrail; truncating", annotation, len(value), maxAnnotationValueBytes)
+	return value[:maxAnnotationValueBytes]
+}
+
+// summarizeVerificationStatus attempts to decode value as a VerificationStatusMap
+// and re-encode it with retry detail collapsed into retrySummary entries. It returns
+// false if value isn't a recognizable verification status blob.
+func summarizeVerificationStatus(value string) (string, bool) {
+	var status VerificationStatusMap
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return "", false
+	}
+	summarized := make(map[string]retrySummary, len(status))
+	now := time.Now().UTC().Format(time.RFC3339)
+	for name, s := range status {
+		if s == nil {
+			continue
+		}
+		summarized[name] = retrySummary{
+			State:        s.State,
+			URL:          s.URL,
+			SummarizedAt: now,
+		}
+	}
+	out, err := json.Marshal(summarized)
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxBuildLogFetchBytes bounds how much of a build log httpBuildLogTail and
+// renderVerifyLinks will download before giving up, so a very large or
+// malformed log can't consume unbounded memory or time.
+const maxBuildLogFetchBytes = 4 * 1024 * 1024 // 4MiB
+
+// defaultBuildLogTailLines is how many trailing lines of a failed job's build
+// log are rendered inline on the tag page and returned by httpBuildLogTail
+// when the caller does not specify a line count.
+const defaultBuildLogTailLines = 100
+
+// maxBuildLogTailLines bounds how many trailing lines may be requested, so a
+// large request can't force the proxy to hold an unreasonable amount of text
+// in memory or render an unreasonably large page.
+const maxBuildLogTailLines = 1000
+
+// inlineBuildLogTailLines is how many trailing lines are embedded directly on
+// the tag page under a failed verification step. It is deliberately smaller
+// than defaultBuildLogTailLines, since the goal here is showing the obvious
+// error, not the full API tail, on a page that may list several failed jobs.
+const inlineBuildLogTailLines = 20
+
+var buildLogHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// buildLogURLFromSpyglassURL converts a Prow spyglass "view" URL (as recorded
+// in a ProwJob's status.url) into the direct HTTPS URL for that job's raw
+// build-log.txt in the GCS artifact bucket it was uploaded to. It returns
+// false if viewURL isn't a GCS-backed spyglass URL, e.g. a job with no
+// artifacts, or one uploaded to a storage backend this proxy doesn't know how
+// to translate.
+func buildLogURLFromSpyglassURL(viewURL string) (string, bool) {
+	u, err := url.Parse(viewURL)
+	if err != nil {
+		return "", false
+	}
+	for _, prefix := range []string{"/view/gcs/", "/view/gs/"} {
+		if strings.HasPrefix(u.Path, prefix) {
+			rest := strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+			if len(rest) == 0 {
+				return "", false
+			}
+			return fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", rest), true
+		}
+	}
+	return "", false
+}
+
+// tailLines reads up to maxBuildLogFetchBytes from r and returns the last n
+// lines seen.
+func tailLines(r io.Reader, n int) ([]string, error) {
+	scanner := bufio.NewScanner(io.LimitReader(r, maxBuildLogFetchBytes))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// fetchBuildLogTail fetches the last n (at most maxBuildLogTailLines) lines of
+// the build log at logURL, consulting and populating buildLogTailCache so
+// repeated requests for the same job - e.g. reloading the tag page, or the
+// page render and the API both asking for a tail - don't refetch the log.
+func (c *Controller) fetchBuildLogTail(logURL string, n int) ([]string, error) {
+	if n > maxBuildLogTailLines {
+		n = maxBuildLogTailLines
+	}
+	if cached, ok := c.buildLogTailCache.Get(logURL); ok {
+		lines := cached.([]string)
+		if len(lines) > n {
+			return lines[len(lines)-n:], nil
+		}
+		return lines, nil
+	}
+
+	resp, err := buildLogHTTPClient.Get(logURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("build log fetch failed with status %s", resp.Status)
+	}
+
+	lines, err := tailLines(resp.Body, maxBuildLogTailLines)
+	if err != nil {
+		return nil, err
+	}
+	c.buildLogTailCache.Add(logURL, lines)
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// renderBuildLogTail writes the last inlineBuildLogTailLines lines of the
+// build log behind a failed verification step's spyglass URL to w, so a user
+// can see the obvious error without navigating away. It writes nothing if
+// spyglassURL isn't a build log this proxy can resolve, or the fetch fails -
+// the link to the full job is always rendered regardless, so this is a
+// best-effort addition, not a requirement for the page to be useful.
+func (c *Controller) renderBuildLogTail(w io.Writer, spyglassURL string) {
+	logURL, ok := buildLogURLFromSpyglassURL(spyglassURL)
+	if !ok {
+		return
+	}
+	tail, err := c.fetchBuildLogTail(logURL, inlineBuildLogTailLines)
+	if err != nil || len(tail) == 0 {
+		return
+	}
+	fmt.Fprintf(w, `<pre class="small" style="max-height:20em;overflow:auto">%s</pre>`, template.HTMLEscapeString(strings.Join(tail, "\n")))
+}
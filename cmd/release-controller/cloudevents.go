@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// cloudEventSource identifies this controller as the source of every event it
+// emits, per the CloudEvents spec's source attribute.
+const cloudEventSource = "urn:release-controller"
+
+// CloudEvent is a CloudEvents v1.0 envelope in structured content mode
+// (https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md).
+// There is no vendored CloudEvents SDK or Kafka client in this tree, so emission is
+// scoped to POSTing this envelope to a single configured HTTP sink; a Kafka
+// producer can be layered on by a downstream bridge service subscribing to that
+// sink if needed.
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	Type            string      `json:"type"`
+	Source          string      `json:"source"`
+	ID              string      `json:"id"`
+	Time            string      `json:"time"`
+	Subject         string      `json:"subject"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// cloudEvent type values, namespaced under io.openshift.release per CloudEvents
+// convention for reverse-DNS type names.
+const (
+	cloudEventTagCreated        = "io.openshift.release.tag.created"
+	cloudEventTagPhase          = "io.openshift.release.tag.phase"
+	cloudEventVerificationState = "io.openshift.release.tag.verification"
+	cloudEventPublished         = "io.openshift.release.tag.published"
+)
+
+var cloudEventsClient = &http.Client{Timeout: 10 * time.Second}
+
+// emitCloudEvent POSTs a CloudEvent to cloudEventsSink, if one is configured. It
+// is best-effort: delivery happens on its own goroutine so a slow or unreachable
+// sink never blocks the sync loop, and failures are logged rather than
+// propagated, since no lifecycle transition should be rolled back because a
+// downstream notification didn't arrive.
+func (c *Controller) emitCloudEvent(eventType, subject string, data interface{}) {
+	if len(c.cloudEventsSink) == 0 {
+		return
+	}
+	event := CloudEvent{
+		SpecVersion:     "1.0",
+		Type:            eventType,
+		Source:          cloudEventSource,
+		ID:              fmt.Sprintf("%s-%d", subject, time.Now().UnixNano()),
+		Time:            time.Now().UTC().Format(time.RFC3339),
+		Subject:         subject,
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		glog.Errorf("Unable to encode CloudEvent %s for %s: %v", eventType, subject, err)
+		return
+	}
+	go func() {
+		resp, err := cloudEventsClient.Post(c.cloudEventsSink, "application/cloudevents+json", bytes.NewReader(body))
+		if err != nil {
+			glog.V(2).Infof("Unable to deliver CloudEvent %s for %s: %v", eventType, subject, err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			glog.V(2).Infof("CloudEvent sink rejected %s for %s with status %s", eventType, subject, resp.Status)
+		}
+	}()
+}
+
+// publishEventTracker remembers the last tag a given publish step successfully
+// published, so emitPublishedCloudEvent can fire once per actual change instead
+// of once per sync tick for an already-published, idempotent publish step.
+type publishEventTracker struct {
+	lock      sync.Mutex
+	published map[string]string
+}
+
+func newPublishEventTracker() *publishEventTracker {
+	return &publishEventTracker{published: make(map[string]string)}
+}
+
+// emitPublishedCloudEvent emits cloudEventPublished for step/tag unless that
+// step was already recorded as having published that exact tag.
+func (c *Controller) emitPublishedCloudEvent(release *Release, step, tag string) {
+	key := release.Config.Name + "/" + step
+	c.publishEvents.lock.Lock()
+	alreadyPublished := c.publishEvents.published[key] == tag
+	c.publishEvents.published[key] = tag
+	c.publishEvents.lock.Unlock()
+	if alreadyPublished {
+		return
+	}
+	c.emitCloudEvent(cloudEventPublished, tag, map[string]string{
+		"release": release.Config.Name,
+		"tag":     tag,
+		"publish": step,
+	})
+}
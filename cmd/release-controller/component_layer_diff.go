@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// ComponentLayerDiff summarizes how much a single payload component's image
+// changed between two release tags. Components whose digest didn't change
+// between the two tags are omitted from the result entirely, rather than
+// being reported with zero churn, since most components don't change in any
+// given release and there is no value in querying their (unchanged) layers.
+type ComponentLayerDiff struct {
+	// Component is the payload tag name (e.g. "machine-config-operator").
+	Component string `json:"component"`
+	// LayersChanged is the number of layers present in the new image but not
+	// the old one, by digest.
+	LayersChanged int `json:"layersChanged"`
+	// LayersTotal is the number of layers in the new image.
+	LayersTotal int `json:"layersTotal"`
+	// BytesChanged is the sum of the sizes of the changed layers.
+	BytesChanged int64 `json:"bytesChanged"`
+}
+
+// componentLayerDiffs reports, for every payload component whose image
+// changed between previousTag and releaseTag, how many layers and bytes of
+// that component's image are new. It is best-effort per component: a
+// component whose new image's layer manifests can't be fetched (e.g. a
+// transient registry error) is omitted rather than failing the whole
+// comparison, since one slow or unavailable component shouldn't prevent
+// reporting churn for the rest. If only the previous image's layers can't be
+// fetched, every layer of the new image is counted as changed.
+func (c *Controller) componentLayerDiffs(release *Release, previousRelease *Release, previousTag, releaseTag *imagev1.TagReference) ([]ComponentLayerDiff, error) {
+	current, err := c.componentDigests(release, releaseTag.Name)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := c.componentDigests(previousRelease, previousTag.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []string
+	for component, digest := range current {
+		if previous[component] != digest {
+			components = append(components, component)
+		}
+	}
+	sort.Strings(components)
+
+	var diffs []ComponentLayerDiff
+	for _, component := range components {
+		currentLayers, err := c.releaseInfo.ImageLayers(current[component])
+		if err != nil {
+			glog.V(4).Infof("Unable to fetch image layers for %s component %s (%s): %v", release.Config.Name, component, current[component], err)
+			continue
+		}
+		var previousLayers []ImageLayer
+		if oldDigest, ok := previous[component]; ok {
+			if layers, err := c.releaseInfo.ImageLayers(oldDigest); err == nil {
+				previousLayers = layers
+			} else {
+				glog.V(4).Infof("Unable to fetch image layers for %s component %s (%s): %v", release.Config.Name, component, oldDigest, err)
+			}
+		}
+
+		previousDigests := make(map[string]struct{}, len(previousLayers))
+		for _, layer := range previousLayers {
+			previousDigests[layer.Digest] = struct{}{}
+		}
+
+		diff := ComponentLayerDiff{Component: component, LayersTotal: len(currentLayers)}
+		for _, layer := range currentLayers {
+			if _, ok := previousDigests[layer.Digest]; !ok {
+				diff.LayersChanged++
+				diff.BytesChanged += layer.Size
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// httpComponentLayerDiff serves the per-component layer/byte churn between a
+// release tag and its predecessor. See componentLayerDiffs.
+func (c *Controller) httpComponentLayerDiff(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	release := vars["release"]
+	tag := vars["tag"]
+
+	tags, ok := c.findReleaseStreamTags(true, tag)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unable to find release tag %s, it may have been deleted", tag), http.StatusNotFound)
+		return
+	}
+	info := tags[tag]
+	if len(release) > 0 && info.Release.Config.Name != release {
+		http.Error(w, fmt.Sprintf("Release tag %s does not belong to release %s", tag, release), http.StatusNotFound)
+		return
+	}
+	if info.Previous == nil {
+		http.Error(w, "tag has no previous release to compare against", http.StatusNotFound)
+		return
+	}
+
+	diffs, err := c.componentLayerDiffs(info.Release, info.PreviousRelease, info.Previous, info.Tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to compute component layer diff: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(diffs, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
@@ -20,6 +20,7 @@ import (
 	batchclient "k8s.io/client-go/kubernetes/typed/batch/v1"
 	kv1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	batchlisters "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -61,15 +62,112 @@ import (
 //
 type Controller struct {
 	eventRecorder record.EventRecorder
+	// eventHistory retains events the controller emits about each release stream
+	// beyond the TTL of the underlying Kubernetes Events, for the event history API.
+	eventHistory *eventHistory
 
 	imageClient       imageclient.ImageV1Interface
 	imageStreamLister *multiImageStreamLister
-	jobClient         batchclient.JobsGetter
-	jobLister         batchlisters.JobLister
+	// clusterConfig, if set via SetClusterConfig, is used to build impersonated
+	// clients for publish steps that specify a PublishStreamReference.ServiceAccount.
+	clusterConfig *rest.Config
+	jobClient     batchclient.JobsGetter
+	jobLister     batchlisters.JobLister
 
 	podClient kv1core.PodsGetter
 
+	// configMapClient, if set via SetConfigMapClient, is used to look up ConfigMaps
+	// that report results for ReleaseVerification.External verification steps.
+	configMapClient kv1core.ConfigMapsGetter
+	// configMapRetention holds the per-kind ConfigMapRetentionPolicy registered by
+	// RegisterConfigMapRetention, for subsystems whose owned ConfigMaps the
+	// janitor (see planConfigMapGarbageCollection) should clean up over time.
+	configMapRetention map[string]ConfigMapRetentionPolicy
+	// secretClient, if set via SetSecretClient, is used to look up Secrets
+	// referenced by ReleasePublish.Webhook.SecretName.
+	secretClient kv1core.SecretsGetter
+	// externalVerifySecret, if set via SetExternalVerifySecret, validates the
+	// signature on incoming ImportExternalVerificationResult webhook requests.
+	externalVerifySecret string
+	// kioskSecret, if set via SetKioskSecret, validates kiosk tokens presented to
+	// httpKioskDashboard, granting read-only access to a single release stream's
+	// dashboard without the rest of the instance's auth protection.
+	kioskSecret string
+	// externalPayloadSecret, if set via SetExternalPayloadSecret, validates the
+	// signature on incoming httpRegisterExternalPayload requests.
+	externalPayloadSecret string
+	// authProxyUserHeader, if set via SetAuthProxyUserHeader, is the name of the
+	// request header a trusted, authenticating reverse proxy in front of this
+	// service (e.g. an oauth-proxy sidecar terminating OIDC) sets to the logged
+	// in user's identity. This controller has no OIDC client of its own; per-user
+	// features (see http_user_prefs.go) are disabled unless this is set and the
+	// request actually carries the header.
+	authProxyUserHeader string
+	// cloudEventsSink, if set via SetCloudEventsSink, is the URL emitCloudEvent
+	// POSTs lifecycle notifications to. If empty, emission is a no-op.
+	cloudEventsSink string
+	// digestSink, if set via SetDigestSink, is the URL digestLoop POSTs
+	// aggregated NotificationDigests to. If empty, digestLoop is a no-op.
+	digestSink string
+	// digestInterval, if set via SetDigestInterval, overrides
+	// defaultDigestInterval for digestLoop.
+	digestInterval time.Duration
+	// requireClientCertForMutations, if set via
+	// SetRequireClientCertForMutations, makes userInterfaceHandler reject
+	// mutating (POST/PUT/PATCH/DELETE) requests that did not present a
+	// client certificate verified against the TLS listener's configured
+	// client CA. Only meaningful when the listener is actually serving TLS
+	// with a client CA configured; main.go is responsible for keeping the
+	// two in sync.
+	requireClientCertForMutations bool
+	// publishEvents dedupes cloudEventPublished emissions; see emitPublishedCloudEvent.
+	publishEvents *publishEventTracker
+	// testBudget tracks recent ProwJob verification runtime and rejection
+	// attribution per stream, backing the budget utilization suggestions served
+	// at /api/v1/releasestream/{release}/budget.
+	testBudget *testBudgetTracker
+	// tagIndex caches each release stream's sorted tag name order, backing
+	// cursor pagination at /api/v1/releasestream/{release}/tags.
+	tagIndex *tagIndexCache
+	// canaries tracks registered canary consumers and open feedback windows for
+	// PublishTagReference steps that opt into CanaryConfig.
+	canaries *canaryTracker
+	// rollouts tracks in-progress admin-triggered ConfigRollouts started via
+	// httpStartConfigRollout.
+	rollouts *rolloutTracker
+	// prowJobWatchdog tracks how long a Pending verification step's ProwJob has
+	// been missing from the prow namespace, for prowJobWatchdogLoop.
+	prowJobWatchdog *prowJobWatchdogTracker
+	// circuitBreaker tracks pending operator resume requests for streams whose
+	// AcceptanceCircuitBreaker has tripped. See checkAcceptanceCircuitBreaker.
+	circuitBreaker *circuitBreakerTracker
+	// graphRebuild tracks the progress of an admin-triggered rebuildGraphFromProwJobs
+	// run started via httpAdminGraphRebuild.
+	graphRebuild *graphRebuildTracker
+	// publishHistory records when each publish step ran, its outcome, retry
+	// count, and destination, backing /publish-history.
+	publishHistory *publishHistory
+
+	// buildLogTailCache caches the tailed build log text fetched by
+	// httpBuildLogTail, keyed by the build log URL resolved from the ProwJob's
+	// spyglass URL (see buildLogURLFromSpyglassURL), to avoid refetching
+	// and re-tailing a large build log on every tag page view.
+	buildLogTailCache *lru.Cache
+
 	performGC bool
+	// gcDryRun, if set, causes garbageCollectSync to log what it would delete
+	// instead of deleting it. See SetGCDryRun.
+	gcDryRun bool
+	// dryRun, if set, causes the sync loop to log the release tags it would
+	// create, the prow jobs it would launch, and the publish steps it would
+	// perform, instead of performing them. See SetDryRun.
+	dryRun bool
+
+	// leaderElection, if set via SetLeaderElection, gates GC and publish steps so
+	// that only the replica currently holding the leader lease performs them. The
+	// isLeader field it reports through is only ever read or written through the
+	// leaderElection wrapper, never directly.
+	leaderElection *controllerLeaderElection
 
 	// syncs are the items that must return true before the queue can be processed
 	syncs []cache.InformerSynced
@@ -113,6 +211,13 @@ type Controller struct {
 	prowClient       dynamic.ResourceInterface
 	prowLister       cache.Indexer
 
+	// releaseConfigLister indexes ReleaseConfig custom resources (see
+	// release_config_crd.go) by "<namespace>/<name>", if AddReleaseConfigInformer
+	// was called. It is nil when no such informer is configured, in which case
+	// releaseDefinition falls back to the release.openshift.io/config annotation
+	// for every stream.
+	releaseConfigLister cache.Indexer
+
 	// onlySources if set controls which image stream names can be synced
 	onlySources sets.String
 
@@ -120,9 +225,39 @@ type Controller struct {
 
 	graph *UpgradeGraph
 
-	// parsedReleaseConfigCache caches the parsed release config object for any release
-	// config serialized json.
+	// parsedReleaseConfigCache caches the parsed release config object, keyed by
+	// the raw release config annotation JSON. Keying on content rather than the
+	// owning image stream's UID/generation means an annotation edit is a cache
+	// miss automatically (no separate invalidation path is needed), and the
+	// several release streams that share an identical config (e.g. forks of the
+	// same template) share one cache entry. See parseReleaseConfigTotal for
+	// cache hit/miss metrics.
 	parsedReleaseConfigCache *lru.Cache
+
+	// overviewCache caches the rendered /api/v1/overview response for overviewCacheTTL.
+	overviewCache overviewCache
+
+	// htmlPageCache caches the rendered body of the heavier dashboard and
+	// release tag HTML pages, keyed by the resourceVersion of the image
+	// stream(s) each page depends on, so rendering isn't repeated for every
+	// request against an unchanged stream. See htmlPageCache for details.
+	htmlPageCache *htmlPageCache
+
+	// resyncIntervals controls how often resyncClassesLoop requeues streams of each class.
+	resyncIntervals *resyncIntervals
+
+	// storageReports caches the most recently computed per-stream storage usage,
+	// refreshed periodically by storageReportLoop and served by the storage API.
+	storageReports *storageReportCache
+
+	// upgradeMatrixBudget tracks how many synthetic upgrade jobs upgradeMatrixLoop
+	// has scheduled for each stream today.
+	upgradeMatrixBudget *upgradeMatrixBudget
+
+	// serializeGroups holds the lock for each ReleaseVerification.SerializeGroup
+	// in use, so that at most one ProwJob sharing a group is started at a time
+	// across all tags and streams.
+	serializeGroups *serializeGroupTracker
 }
 
 // NewController instantiates a Controller to manage release objects.
@@ -147,14 +282,33 @@ func NewController(
 	broadcaster.StartRecordingToSink(&kv1core.EventSinkImpl{Interface: eventsClient.Events("")})
 	recorder := broadcaster.NewRecorder(imagescheme.Scheme, corev1.EventSource{Component: "release-controller"})
 
-	// we cache parsed release configs to avoid the deserialization cost
-	parsedReleaseConfigCache, err := lru.New(50)
+	eventHistory := newEventHistory()
+	broadcaster.StartEventWatcher(eventHistory.record)
+
+	// we cache parsed release configs to avoid the deserialization cost; sized
+	// well above a typical installation's distinct release configs (as opposed
+	// to its stream count, which can run into the hundreds) since streams
+	// commonly share identical config text.
+	parsedReleaseConfigCache, err := lru.New(512)
+	if err != nil {
+		panic(err)
+	}
+
+	// cache tailed build logs so repeated tag page views don't refetch a large
+	// log from the artifact bucket on every request
+	buildLogTailCache, err := lru.New(100)
+	if err != nil {
+		panic(err)
+	}
+
+	htmlPageCache, err := newHTMLPageCache()
 	if err != nil {
 		panic(err)
 	}
 
 	c := &Controller{
 		eventRecorder: recorder,
+		eventHistory:  eventHistory,
 		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "releases"),
 		gcQueue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "gc"),
 
@@ -190,8 +344,39 @@ func NewController(
 		graph: graph,
 
 		parsedReleaseConfigCache: parsedReleaseConfigCache,
+
+		resyncIntervals: defaultResyncIntervals(),
+
+		storageReports: newStorageReportCache(),
+
+		upgradeMatrixBudget: newUpgradeMatrixBudget(),
+
+		serializeGroups: newSerializeGroupTracker(),
+
+		publishEvents: newPublishEventTracker(),
+
+		testBudget: newTestBudgetTracker(),
+
+		tagIndex:        newTagIndexCache(),
+		canaries:        newCanaryTracker(),
+		rollouts:        newRolloutTracker(),
+		prowJobWatchdog: newProwJobWatchdogTracker(),
+		circuitBreaker:  newCircuitBreakerTracker(),
+
+		graphRebuild: newGraphRebuildTracker(),
+
+		publishHistory: newPublishHistory(),
+
+		buildLogTailCache: buildLogTailCache,
+
+		htmlPageCache: htmlPageCache,
 	}
 
+	// persisted changelogs are one per accepted release tag, which can run into
+	// the thousands over a stream's lifetime; retain a bounded recent window
+	// rather than every changelog a stream has ever produced.
+	c.RegisterConfigMapRetention(changelogConfigMapKind, ConfigMapRetentionPolicy{MaxCount: 200})
+
 	c.auditTracker = NewAuditTracker(c.auditQueue)
 
 	// handle job changes
@@ -208,6 +393,123 @@ func (c *Controller) LimitSources(names ...string) {
 	c.onlySources = sets.NewString(names...)
 }
 
+// SetGCDryRun configures whether garbage collection reports what it would delete
+// instead of deleting it. It does not affect the rest of the sync loop.
+func (c *Controller) SetGCDryRun(dryRun bool) {
+	c.gcDryRun = dryRun
+}
+
+// SetDryRun configures whether the sync loop logs the mutations it would make --
+// release tags created, prow jobs launched, and publish steps performed -- instead
+// of making them. Verification bookkeeping that doesn't itself cause an external
+// effect (phase/annotation updates on a tag already in flight, job cancellation)
+// is out of scope: a dry run never creates the tag or prow job those would apply
+// to in the first place, so the sync loop naturally has nothing further to do
+// with a given release until it is re-run for real. It does not affect garbage
+// collection, which is governed separately by SetGCDryRun.
+func (c *Controller) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// SetClusterConfig records the rest.Config the controller was started with so that
+// publish steps which target another namespace can build an impersonated client for
+// a PublishStreamReference.ServiceAccount instead of reusing the controller's own
+// (broad) identity. If never called, those publish steps fall back to the
+// controller's own client.
+func (c *Controller) SetClusterConfig(config *rest.Config) {
+	c.clusterConfig = config
+}
+
+// SetConfigMapClient configures the client used to look up ConfigMaps reporting
+// results for ReleaseVerification.External verification steps. If never called,
+// ConfigMap-based external verification reporting is disabled.
+func (c *Controller) SetConfigMapClient(client kv1core.ConfigMapsGetter) {
+	c.configMapClient = client
+}
+
+// RegisterConfigMapRetention declares the retention policy for ConfigMaps
+// labelled configMapJanitorKindLabel=kind in the job namespace, so the janitor
+// started by garbageCollectSync starts cleaning them up. A subsystem that emits
+// a growing set of its own ConfigMaps (e.g. one per input snapshot, history
+// entry, or overflowed status record) should label every ConfigMap it creates
+// with configMapJanitorKindLabel=kind and call this once, during controller
+// construction, with the policy those ConfigMaps should be retained under.
+func (c *Controller) RegisterConfigMapRetention(kind string, policy ConfigMapRetentionPolicy) {
+	if c.configMapRetention == nil {
+		c.configMapRetention = make(map[string]ConfigMapRetentionPolicy)
+	}
+	c.configMapRetention[kind] = policy
+}
+
+// SetSecretClient configures the client used to look up Secrets referenced by
+// ReleasePublish.Webhook.SecretName. If never called, a webhook publish step
+// that sets SecretName fails rather than sending an unsigned payload.
+func (c *Controller) SetSecretClient(client kv1core.SecretsGetter) {
+	c.secretClient = client
+}
+
+// SetExternalVerifySecret configures the shared secret used to validate the
+// signature on incoming ImportExternalVerificationResult webhook requests. If
+// empty, the webhook endpoint rejects all requests.
+func (c *Controller) SetExternalVerifySecret(secret string) {
+	c.externalVerifySecret = secret
+}
+
+// SetKioskSecret configures the shared secret used to mint and validate kiosk
+// tokens. If empty, the kiosk dashboard endpoint is disabled.
+func (c *Controller) SetKioskSecret(secret string) {
+	c.kioskSecret = secret
+}
+
+// SetAuthProxyUserHeader configures the trusted header a fronting auth proxy
+// sets to the authenticated user's identity. If empty, per-user features are
+// disabled.
+func (c *Controller) SetAuthProxyUserHeader(header string) {
+	c.authProxyUserHeader = header
+}
+
+// SetExternalPayloadSecret configures the shared secret used to validate the
+// signature on incoming httpRegisterExternalPayload requests. If empty, the
+// endpoint rejects all requests.
+func (c *Controller) SetExternalPayloadSecret(secret string) {
+	c.externalPayloadSecret = secret
+}
+
+// SetCloudEventsSink sets the URL that lifecycle events are POSTed to. See
+// emitCloudEvent. An empty sink disables emission.
+func (c *Controller) SetCloudEventsSink(sink string) {
+	c.cloudEventsSink = sink
+}
+
+// SetDigestSink sets the URL that aggregated notification digests are
+// POSTed to. See digestLoop. An empty sink disables digest generation.
+func (c *Controller) SetDigestSink(sink string) {
+	c.digestSink = sink
+}
+
+// SetDigestInterval overrides defaultDigestInterval for digestLoop.
+func (c *Controller) SetDigestInterval(interval time.Duration) {
+	c.digestInterval = interval
+}
+
+// SetRequireClientCertForMutations enables the userInterfaceHandler
+// middleware that rejects mutating requests without a verified client
+// certificate. Callers must only set this when the HTTP listener is
+// actually serving TLS with a client CA configured, or every mutating
+// request will be rejected.
+func (c *Controller) SetRequireClientCertForMutations(require bool) {
+	c.requireClientCertForMutations = require
+}
+
+// SetResyncInterval overrides the safety-net requeue interval used for the given
+// stream class. See resyncClassesLoop.
+func (c *Controller) SetResyncInterval(class streamClass, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	c.resyncIntervals.Set(class, interval)
+}
+
 type ProwConfigLoader interface {
 	Config() *prowapiv1.Config
 }
@@ -263,6 +565,21 @@ func (c *Controller) AddProwInformer(ns string, informer cache.SharedIndexInform
 	c.prowLister = informer.GetIndexer()
 }
 
+// AddReleaseConfigInformer sets the controller up to watch for changes to
+// ReleaseConfig custom resources and prefer them over the
+// release.openshift.io/config imagestream annotation when both exist. See
+// release_config_crd.go.
+func (c *Controller) AddReleaseConfigInformer(informer cache.SharedIndexInformer) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.processReleaseConfig,
+		DeleteFunc: c.processReleaseConfig,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.processReleaseConfig(newObj)
+		},
+	})
+	c.releaseConfigLister = informer.GetIndexer()
+}
+
 type queueKey struct {
 	namespace string
 	name      string
@@ -326,6 +643,18 @@ func (c *Controller) processProwJob(obj interface{}) {
 	}
 }
 
+// processReleaseConfig queues the imagestream a ReleaseConfig custom resource
+// configures - its namespace and name, by convention (see
+// releaseConfigFromCRD) - whenever that resource changes.
+func (c *Controller) processReleaseConfig(obj interface{}) {
+	switch t := obj.(type) {
+	case *unstructured.Unstructured:
+		c.addQueueKey(queueKey{namespace: t.GetNamespace(), name: t.GetName()})
+	default:
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %T", obj))
+	}
+}
+
 func (c *Controller) processImageStream(obj interface{}) {
 	switch t := obj.(type) {
 	case *imagev1.ImageStream:
@@ -390,6 +719,16 @@ func (c *Controller) run(workers int, stopCh <-chan struct{}) {
 		go wait.Until(c.auditWorker, time.Second, stopCh)
 	}
 
+	go c.resyncClassesLoop(stopCh)
+	go c.externalImportLoop(stopCh)
+	go c.stuckReleaseLoop(stopCh)
+	go c.storageReportLoop(stopCh)
+	go c.upgradeMatrixLoop(stopCh)
+	go c.canaryLoop(stopCh)
+	go c.rolloutLoop(stopCh)
+	go c.prowJobWatchdogLoop(stopCh)
+	go c.digestLoop(stopCh)
+
 	<-stopCh
 	glog.Infof("Shutting down controller")
 }
@@ -422,7 +761,9 @@ func (c *Controller) processNext() bool {
 	}
 
 	glog.V(5).Infof("processing %v begin", key)
+	start := time.Now()
 	err := c.syncFn(key)
+	syncDurationSeconds.WithLabelValues(key.name).Observe(time.Since(start).Seconds())
 	c.handleNamespaceErr(c.queue, err, key)
 	glog.V(5).Infof("processing %v end", key)
 
@@ -456,6 +797,12 @@ func (c *Controller) processNextGC() bool {
 	}
 	defer c.gcQueue.Done(key)
 
+	if !c.IsLeader() {
+		glog.V(5).Infof("not the leader, skipping GC until leadership is acquired")
+		c.gcQueue.AddAfter(key, 10*time.Second)
+		return true
+	}
+
 	glog.V(5).Infof("processing %v begin", key)
 	err := c.garbageCollectSync()
 	c.handleNamespaceErr(c.gcQueue, err, key)
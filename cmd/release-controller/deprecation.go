@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// DeprecationNotice marks an Accepted tag as unsafe to consume without
+// removing it, e.g. after a shipped nightly is found to carry a harmful
+// regression. A deprecated tag remains in the stream so existing references
+// to it keep working, but "latest"/candidate selection skips it, its page
+// shows a warning banner, and JSON responses referencing it carry the
+// X-Release-Deprecated header. Stored as the JSON-serialized
+// releaseAnnotationDeprecated annotation.
+type DeprecationNotice struct {
+	// Reason explains why the tag was deprecated.
+	Reason string `json:"reason"`
+	// Replacement, if set, names the tag consumers should use instead.
+	Replacement string `json:"replacement,omitempty"`
+	// At is when the tag was deprecated, RFC3339.
+	At string `json:"at"`
+}
+
+// deprecationNoticeForTag returns the deprecation notice recorded on tag, if
+// any.
+func deprecationNoticeForTag(tag *imagev1.TagReference) *DeprecationNotice {
+	if tag == nil {
+		return nil
+	}
+	raw := tag.Annotations[releaseAnnotationDeprecated]
+	if len(raw) == 0 {
+		return nil
+	}
+	var notice DeprecationNotice
+	if err := json.Unmarshal([]byte(raw), &notice); err != nil {
+		return nil
+	}
+	return &notice
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// DuplicatePayloadTag identifies one release stream's tag that carries an
+// identical payload image hash to another stream's tag. See
+// (*Controller).findDuplicatePayloads.
+type DuplicatePayloadTag struct {
+	Release string `json:"release"`
+	Tag     string `json:"tag"`
+}
+
+// findDuplicatePayloads scans every release stream's tags and groups, by
+// releaseAnnotationImageHash, the tags that share a hash with at least one
+// tag from a different release stream - the common case when a ci and
+// nightly stream produce the same payload during a merge freeze. Tags within
+// the same stream that happen to share a hash are not reported, since that's
+// just the normal case of a stream's input not changing between two tags.
+func (c *Controller) findDuplicatePayloads() (map[string][]DuplicatePayloadTag, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[string][]DuplicatePayloadTag)
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		for _, tag := range r.Target.Spec.Tags {
+			hash := tag.Annotations[releaseAnnotationImageHash]
+			if len(hash) == 0 {
+				continue
+			}
+			byHash[hash] = append(byHash[hash], DuplicatePayloadTag{Release: r.Config.Name, Tag: tag.Name})
+		}
+	}
+
+	duplicates := make(map[string][]DuplicatePayloadTag)
+	for hash, tags := range byHash {
+		if !spansMultipleReleases(tags) {
+			continue
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			if tags[i].Release != tags[j].Release {
+				return tags[i].Release < tags[j].Release
+			}
+			return tags[i].Tag < tags[j].Tag
+		})
+		duplicates[hash] = tags
+	}
+	return duplicates, nil
+}
+
+func spansMultipleReleases(tags []DuplicatePayloadTag) bool {
+	for _, t := range tags[1:] {
+		if t.Release != tags[0].Release {
+			return true
+		}
+	}
+	return false
+}
+
+// verificationStatusFromDuplicate looks for a tag reported by
+// findDuplicatePayloads that has already recorded a terminal (Succeeded or
+// Failed) result for verifyName under release.Config.ShareVerificationAcrossDuplicates,
+// and returns it so ensureVerificationJobs can reuse it instead of running a
+// redundant ProwJob against an identical payload. It only reuses a result
+// from a stream whose verify step of the same name resolves to the exact same
+// ProwJob, since two streams can give the same step name different meanings.
+func (c *Controller) verificationStatusFromDuplicate(release *Release, releaseTag *imagev1.TagReference, verifyName string, verifyType ReleaseVerification) (*VerificationStatus, bool) {
+	if !release.Config.ShareVerificationAcrossDuplicates || verifyType.ProwJob == nil {
+		return nil, false
+	}
+	hash := releaseTag.Annotations[releaseAnnotationImageHash]
+	if len(hash) == 0 {
+		return nil, false
+	}
+	duplicates, err := c.findDuplicatePayloads()
+	if err != nil {
+		return nil, false
+	}
+	for _, dup := range duplicates[hash] {
+		if dup.Release == release.Config.Name && dup.Tag == releaseTag.Name {
+			continue
+		}
+		other, ok := c.findReleaseByName(true, dup.Release)
+		if !ok || other[dup.Release] == nil {
+			continue
+		}
+		otherRelease := other[dup.Release].Release
+		if !otherRelease.Config.ShareVerificationAcrossDuplicates {
+			continue
+		}
+		otherVerify, ok := otherRelease.Config.Verify[verifyName]
+		if !ok || otherVerify.ProwJob == nil || otherVerify.ProwJob.Name != verifyType.ProwJob.Name {
+			continue
+		}
+		otherTag := findTagReference(otherRelease.Target, dup.Tag)
+		if otherTag == nil {
+			continue
+		}
+		data := otherTag.Annotations[releaseAnnotationVerify]
+		if len(data) == 0 {
+			continue
+		}
+		var otherStatus VerificationStatusMap
+		if err := json.Unmarshal([]byte(data), &otherStatus); err != nil {
+			continue
+		}
+		status, ok := otherStatus[verifyName]
+		if !ok {
+			continue
+		}
+		switch status.State {
+		case releaseVerificationStateSucceeded, releaseVerificationStateFailed:
+			return status, true
+		}
+	}
+	return nil, false
+}
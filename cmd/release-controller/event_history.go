@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxEventsPerStream bounds how many events eventHistory retains per stream.
+// Kubernetes Events already expire after about an hour; this trades a small,
+// fixed amount of memory for retaining the history well beyond that window.
+// Once the cap is hit the oldest events are dropped to make room for new ones.
+const maxEventsPerStream = 500
+
+// ReleaseEvent is a single recorded event about a release stream, retained past
+// the TTL of the underlying Kubernetes Event that produced it.
+type ReleaseEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+}
+
+// eventHistory retains a bounded, in-memory history of events the controller has
+// emitted about each release stream, keyed by the name of the involved object
+// (the release stream's source image stream). It is fed by an
+// record.EventBroadcaster event watcher registered in NewController, so it sees
+// every event the controller records regardless of whether the Kubernetes Event
+// it produced has since expired.
+type eventHistory struct {
+	lock     sync.Mutex
+	byStream map[string][]ReleaseEvent
+}
+
+func newEventHistory() *eventHistory {
+	return &eventHistory{byStream: make(map[string][]ReleaseEvent)}
+}
+
+// record appends evt to the history for its involved object. It is intended to be
+// passed directly as the eventHandler to record.EventBroadcaster.StartEventWatcher.
+func (h *eventHistory) record(evt *corev1.Event) {
+	stream := evt.InvolvedObject.Name
+	if len(stream) == 0 {
+		return
+	}
+	re := ReleaseEvent{
+		Time:    evt.LastTimestamp.Time,
+		Type:    evt.Type,
+		Reason:  evt.Reason,
+		Message: evt.Message,
+	}
+	if re.Time.IsZero() {
+		re.Time = evt.EventTime.Time
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	events := append(h.byStream[stream], re)
+	if len(events) > maxEventsPerStream {
+		events = events[len(events)-maxEventsPerStream:]
+	}
+	h.byStream[stream] = events
+}
+
+// list returns the recorded events for stream, newest first, optionally filtered
+// by event type (Normal or Warning) and to a [since, until] time range, both
+// ends inclusive. A zero
+// since or until leaves that side of the range unbounded.
+func (h *eventHistory) list(stream, eventType string, since, until time.Time) []ReleaseEvent {
+	h.lock.Lock()
+	events := append([]ReleaseEvent(nil), h.byStream[stream]...)
+	h.lock.Unlock()
+
+	var filtered []ReleaseEvent
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if len(eventType) > 0 && e.Type != eventType {
+			continue
+		}
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && e.Time.After(until) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// failureFingerprintMaxLines bounds how many distinct failure lines are
+// folded into a fingerprint, so a log with hundreds of failures doesn't
+// dominate over a log with a handful - what matters for clustering is which
+// failures occurred, not how many times each was reported.
+const failureFingerprintMaxLines = 5
+
+// failureLinePattern recognizes the common ways a failing e2e test shows up
+// in Go test / ginkgo output.
+var failureLinePattern = regexp.MustCompile(`(?i)(--- FAIL:|^FAIL\b|• Failure|^\s*\* \[FAILED\]|panic:)`)
+
+// failureLineNoisePattern strips the parts of a failure line that vary run to
+// run without changing what actually failed - addresses, durations, and
+// other hex/decimal runs - so two runs of the same underlying failure
+// normalize to the same text instead of each getting a unique fingerprint.
+var failureLineNoisePattern = regexp.MustCompile(`0x[0-9a-fA-F]+|[0-9a-fA-F]{8,}|\d+(\.\d+)?`)
+
+// computeFailureFingerprint derives a short, stable signature for a failed
+// job's build log at spyglassURL from the normalized set of its recognizable
+// failure lines. It returns false if the log can't be fetched or doesn't
+// contain anything failureLinePattern recognizes, in which case no
+// fingerprint should be recorded; a missing fingerprint is treated the same
+// as a unique one, never as a match.
+func (c *Controller) computeFailureFingerprint(spyglassURL string) (string, bool) {
+	logURL, ok := buildLogURLFromSpyglassURL(spyglassURL)
+	if !ok {
+		return "", false
+	}
+	lines, err := c.fetchBuildLogTail(logURL, maxBuildLogTailLines)
+	if err != nil {
+		return "", false
+	}
+
+	seen := make(map[string]struct{})
+	var signature []string
+	for _, line := range lines {
+		if !failureLinePattern.MatchString(line) {
+			continue
+		}
+		normalized := strings.TrimSpace(failureLineNoisePattern.ReplaceAllString(line, "#"))
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		signature = append(signature, normalized)
+		if len(signature) == failureFingerprintMaxLines {
+			break
+		}
+	}
+	if len(signature) == 0 {
+		return "", false
+	}
+	sort.Strings(signature)
+	sum := sha256.Sum256([]byte(strings.Join(signature, "\n")))
+	return hex.EncodeToString(sum[:])[:12], true
+}
+
+// consecutiveMatchingFailureFingerprints counts how many of olderTags, taken
+// in the newest-first order findReleaseStreamTags returns them in, failed
+// verifyName with fingerprint before hitting one that didn't - i.e. how long
+// an unbroken streak of this exact failure goes back. This powers a "same
+// failure as the previous N tags" indicator, so a flake that recurs without
+// ever actually regressing anything isn't mistaken for a persistent, ongoing
+// regression, and vice versa.
+func consecutiveMatchingFailureFingerprints(olderTags []*imagev1.TagReference, verifyName, fingerprint string) int {
+	if len(fingerprint) == 0 {
+		return 0
+	}
+	count := 0
+	for _, t := range olderTags {
+		status, err := decodeVerificationStatus(t)
+		if err != nil {
+			break
+		}
+		s, ok := status[verifyName]
+		if !ok || s.State != releaseVerificationStateFailed || s.Fingerprint != fingerprint {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// FailureFingerprint is the response shape for httpVerificationFingerprint.
+type FailureFingerprint struct {
+	Verification string `json:"verification"`
+	Fingerprint  string `json:"fingerprint,omitempty"`
+	// SameAsPrevious is how many consecutive, immediately older tags in this
+	// release failed Verification with the same Fingerprint. See
+	// consecutiveMatchingFailureFingerprints.
+	SameAsPrevious int `json:"sameAsPrevious"`
+}
+
+// httpVerificationFingerprint serves the recorded failure fingerprint for one
+// verification step on one release tag, along with how many older tags in a
+// row failed it the same way, to power flake clustering.
+func (c *Controller) httpVerificationFingerprint(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	release := vars["release"]
+	tag := vars["tag"]
+	verification := vars["verification"]
+
+	tags, ok := c.findReleaseStreamTags(true, tag)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unable to find release tag %s, it may have been deleted", tag), http.StatusNotFound)
+		return
+	}
+	info := tags[tag]
+	if len(release) > 0 && info.Release.Config.Name != release {
+		http.Error(w, fmt.Sprintf("Release tag %s does not belong to release %s", tag, release), http.StatusNotFound)
+		return
+	}
+
+	status, err := decodeVerificationStatus(info.Tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to load verification status: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s, ok := status[verification]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s has no verification step named %s", tag, verification), http.StatusNotFound)
+		return
+	}
+
+	result := FailureFingerprint{Verification: verification, Fingerprint: s.Fingerprint}
+	if s.State == releaseVerificationStateFailed {
+		result.SameAsPrevious = consecutiveMatchingFailureFingerprints(info.Older, verification, s.Fingerprint)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// graphRebuildStatus reports the progress of a single rebuildGraphFromProwJobs
+// run, returned by httpAdminGraphRebuild.
+type graphRebuildStatus struct {
+	Running   bool      `json:"running"`
+	Since     string    `json:"since,omitempty"`
+	Scanned   int       `json:"scanned"`
+	Added     int       `json:"added"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// graphRebuildTracker serializes and reports on rebuildGraphFromProwJobs runs.
+// Like canaryTracker and testBudgetTracker, this state is in-memory only and
+// advisory: losing it on restart just means a caller re-requests progress,
+// not that any upgrade history is lost (the graph itself is persisted
+// separately via syncGraphToSecret).
+type graphRebuildTracker struct {
+	lock   sync.Mutex
+	status graphRebuildStatus
+}
+
+func newGraphRebuildTracker() *graphRebuildTracker {
+	return &graphRebuildTracker{}
+}
+
+// start marks a rebuild as running, or returns false if one is already in
+// progress.
+func (t *graphRebuildTracker) start(since string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.status.Running {
+		return false
+	}
+	t.status = graphRebuildStatus{Running: true, Since: since, StartedAt: time.Now()}
+	return true
+}
+
+// progress updates the running count of jobs scanned and edges added so far.
+func (t *graphRebuildTracker) progress(scanned, added int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.status.Scanned = scanned
+	t.status.Added = added
+}
+
+// finish marks the current rebuild as complete, recording err if it failed.
+func (t *graphRebuildTracker) finish(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.status.Running = false
+	t.status.EndedAt = time.Now()
+	if err != nil {
+		t.status.Error = err.Error()
+	}
+}
+
+func (t *graphRebuildTracker) snapshot() graphRebuildStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.status
+}
+
+// parseSinceDuration parses a duration string, additionally accepting a
+// trailing "d" for days (e.g. "90d"), since time.ParseDuration does not
+// support units coarser than hours.
+func parseSinceDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid since duration %q: %v", s, err)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// rebuildGraphFromProwJobs re-scans every completed verification ProwJob
+// currently held in the prow informer's cache and re-adds its upgrade edge to
+// graph, the same way the periodic scan started in main.go does. Jobs whose
+// completion time is older than since (if since is non-zero) are skipped.
+//
+// This only sees ProwJobs the controller's own cache currently holds - there
+// is no separate "historical" or external artifact store of past prow runs
+// in this tree to re-scan beyond that, so a rebuild cannot recover edges for
+// jobs that have already been garbage collected from the cluster. It runs to
+// completion in the calling goroutine; callers that want it to run without
+// blocking syncs (e.g. httpAdminGraphRebuild) should invoke it via `go`.
+func rebuildGraphFromProwJobs(prowLister cache.Indexer, graph *UpgradeGraph, since time.Duration, tracker *graphRebuildTracker) error {
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-since)
+	}
+	scanned, added := 0, 0
+	for _, item := range prowLister.List() {
+		job, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		scanned++
+		if !cutoff.IsZero() {
+			completionStr, _, _ := unstructured.NestedString(job.Object, "status", "completionTime")
+			if len(completionStr) == 0 {
+				continue
+			}
+			completion, err := time.Parse(time.RFC3339, completionStr)
+			if err != nil || completion.Before(cutoff) {
+				continue
+			}
+		}
+		from, ok := job.GetAnnotations()[releaseAnnotationFromTag]
+		if !ok {
+			continue
+		}
+		to, ok := job.GetAnnotations()[releaseAnnotationToTag]
+		if !ok {
+			continue
+		}
+		status, ok := prowJobVerificationStatus(job)
+		if !ok {
+			continue
+		}
+		graph.Add(from, to, UpgradeResult{State: status.State, URL: status.URL})
+		added++
+		if tracker != nil && scanned%50 == 0 {
+			tracker.progress(scanned, added)
+		}
+	}
+	if tracker != nil {
+		tracker.progress(scanned, added)
+	}
+	glog.V(2).Infof("Rebuilt upgrade graph from %d cached prow jobs, adding %d edges", scanned, added)
+	return nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"net/url"
@@ -113,6 +115,7 @@ td.upgrade-track {
 {{ range .Streams }}
 		<h2 id="{{ .Release.Config.Name }}" title="From image stream {{ .Release.Source.Namespace }}/{{ .Release.Source.Name }}">{{ .Release.Config.Name }}</h2>
 		{{ publishDescription . }}
+		{{ renderOwners . }}
 		{{ alerts . }}
 		{{ $upgrades := .Upgrades }}
 		<table class="table text-nowrap">
@@ -183,7 +186,7 @@ func (c *Controller) findReleaseStreamTags(includeStableTags bool, tags ...strin
 				stable.Releases = append(stable.Releases, StableRelease{
 					Release:  r,
 					Version:  version,
-					Versions: NewSemanticVersions(releaseTags),
+					Versions: NewSemanticVersions(r.Config.VersionScheme, releaseTags),
 				})
 			}
 		}
@@ -229,22 +232,124 @@ func semverParseTolerant(v string) (semver.Version, error) {
 
 func (c *Controller) userInterfaceHandler() http.Handler {
 	mux := mux.NewRouter()
+	if c.requireClientCertForMutations {
+		mux.Use(requireClientCertForMutations)
+	}
 	mux.HandleFunc("/graph", c.graphHandler)
 	mux.HandleFunc("/changelog", c.httpReleaseChangelog)
 	mux.HandleFunc("/archive/graph", c.httpGraphSave)
-	mux.HandleFunc("/api/v1/releasestream/{release}/latest", c.apiReleaseLatest)
+	mux.HandleFunc("/api/v1/releasestream/{release}/latest", deprecatedV1(c.apiReleaseLatest))
 	mux.HandleFunc("/releasetag/{tag}", c.httpReleaseInfo)
+	mux.HandleFunc("/releasestream/{release}", c.httpReleaseStreamTags)
 	mux.HandleFunc("/releasestream/{release}/release/{tag}", c.httpReleaseInfo)
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/upgrades", deprecatedV1(c.httpReleaseTagUpgrades))
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/layerdiff", c.httpComponentLayerDiff)
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/risk", c.httpReleaseTagRisk)
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/verify/{verification}/fingerprint", c.httpVerificationFingerprint)
 	mux.HandleFunc("/releasestream/{release}/release/{tag}/download", c.httpReleaseInfoDownload)
+	mux.HandleFunc("/releasestream/{release}/release/{tag}/changelog", c.httpReleaseStoredChangelog)
 	mux.HandleFunc("/releasestream/{release}/latest", c.httpReleaseLatest)
 	mux.HandleFunc("/releasestream/{release}/latest/download", c.httpReleaseLatestDownload)
-	mux.HandleFunc("/api/v1/releasestream/{release}/candidate", c.apiReleaseCandidate)
+	mux.HandleFunc("/api/v1/releasestream/{release}/candidate", deprecatedV1(c.apiReleaseCandidate))
 	mux.HandleFunc("/releasestream/{release}/candidates", c.httpReleaseCandidateList)
+	mux.HandleFunc("/releasestream/{release}/config/diff", c.httpConfigDiff)
+	mux.HandleFunc("/releasestream/{release}/badge.svg", c.httpBadge)
+	mux.HandleFunc("/api/v1/releasestream/{release}/events", c.httpReleaseEvents)
+	mux.HandleFunc("/api/v1/releasestream/{release}/storage", c.httpReleaseStorage)
+	mux.HandleFunc("/api/v1/releasestream/{release}/budget", c.httpReleaseBudget)
+	mux.HandleFunc("/api/v1/releasestream/{release}/tags", c.httpReleaseTagsPage)
+	mux.HandleFunc("/api/v1/search/commit/{sha}", deprecatedV1(c.httpSearchCommit))
+	mux.HandleFunc("/api/v1/overview", deprecatedV1(c.apiOverview))
+	mux.HandleFunc("/api/v1/stuck", deprecatedV1(c.httpStuck))
+	mux.HandleFunc("/api/v1/prowjobwatchdog", c.httpProwJobWatchdog)
+	mux.HandleFunc("/api/v1/compare/verify", c.httpCompareVerify)
+	mux.HandleFunc("/api/v1/upgrade-test", c.httpUpgradeTest).Methods("POST")
+	mux.HandleFunc("/api/v2/overview", c.apiOverviewV2)
+	mux.HandleFunc("/api/v1/releasestream/{release}/simulate/{tag}", deprecatedV1(c.httpSimulateAcceptance)).Methods("POST")
+	mux.HandleFunc("/api/v1/gc/preview", deprecatedV1(c.httpGCPreview))
+	mux.HandleFunc("/api/v1/admin/janitor", deprecatedV1(c.httpJanitorReport))
+	mux.HandleFunc("/api/v1/admin/freeze", c.httpListFreezes).Methods("GET")
+	mux.HandleFunc("/api/v1/admin/freeze/{minor}", c.httpSetFreeze).Methods("POST")
+	mux.HandleFunc("/api/v1/admin/freeze/{minor}", c.httpClearFreeze).Methods("DELETE")
+	mux.HandleFunc("/api/v1/releasestream/{release}/lineage/{tag}", deprecatedV1(c.httpLineage))
+	mux.HandleFunc("/api/v1/releasestream/{release}/verify/{tag}/{verification}", c.httpImportExternalVerificationResult).Methods("POST")
+	mux.HandleFunc("/kiosk/{token}", c.httpKioskDashboard)
+	mux.HandleFunc("/api/v1/releasestream/{release}/externalpayload", c.httpRegisterExternalPayload).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/certification/{tag}/{name}", c.httpUpdateCertification).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/deprecate/{tag}", c.httpDeprecateTag).Methods("POST", "DELETE")
+	mux.HandleFunc("/api/v1/releasestream/{release}/tags:batch", c.httpTagsBatch).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/archive", c.httpStreamArchive).Methods("GET")
+	mux.HandleFunc("/api/v1/releasestream/{release}/archive", c.httpStreamArchiveImport).Methods("POST")
+	mux.HandleFunc("/api/v1/me/stars", c.httpMeStars).Methods("GET", "POST")
+	mux.HandleFunc("/api/v1/me/overview", c.httpMeOverview).Methods("GET")
+	mux.HandleFunc("/api/v1/releasestream/{release}/canary/{consumer}", c.httpRegisterCanary).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/canary/{consumer}/feedback/{publish}", c.httpCanaryFeedback).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/canary/status/{publish}", c.httpCanaryStatus)
+	mux.HandleFunc("/api/v1/releasestream/{release}/verify/{tag}/{verification}/inputs", c.httpVerificationJobInputs)
+	mux.HandleFunc("/api/v1/releasestream/{release}/verify/{tag}/{verification}/log", c.httpBuildLogTail)
+	mux.HandleFunc("/api/v1/releasestream/{release}/verify/{tag}/{verification}/passrate", c.httpVerificationPassRate)
+	mux.HandleFunc("/api/v1/channels/{channel}/latest", c.httpChannelLatest)
+	mux.HandleFunc("/api/v1/export", deprecatedV1(c.httpExport))
+	mux.HandleFunc("/api/v1/mirrors", deprecatedV1(c.httpMirrors))
+	mux.HandleFunc("/admin/graph/rebuild", c.httpAdminGraphRebuild).Methods("GET", "POST")
+	mux.HandleFunc("/admin/configrollout/{prefix}", c.httpStartConfigRollout).Methods("POST")
+	mux.HandleFunc("/admin/configrollout/{prefix}", c.httpConfigRolloutStatus).Methods("GET")
+	mux.HandleFunc("/admin/configrollout/{prefix}", c.httpCancelConfigRollout).Methods("DELETE")
+	mux.HandleFunc("/api/v1/releasestream/{release}/publish-history", c.httpPublishHistory)
+	mux.HandleFunc("/api/v1/releasestream/{release}/publish-history/{tag}", c.httpPublishHistory)
+	mux.HandleFunc("/api/v1/releasestream/{release}/duplicates/{tag}", c.httpDuplicatePayloads)
+	mux.HandleFunc("/api/v1/releasestream/{release}/circuitbreaker/resume", c.httpResumeCircuitBreaker).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/accept", c.httpAcceptReleaseTag).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/reject", c.httpRejectReleaseTag).Methods("POST")
+	mux.HandleFunc("/api/v1/releasestream/{release}/release/{tag}/retry", c.httpRetryReleaseTag).Methods("POST")
+	mux.HandleFunc("/family/{family}", c.httpFamily)
 	mux.HandleFunc("/", c.httpReleases)
 	return mux
 }
 
-func (c *Controller) urlForArtifacts(tagName string) (string, bool) {
+// defaultReleaseArchitecture is substituted for ReleaseConfig.Architecture's
+// {arch} token when a stream doesn't set Architecture explicitly.
+const defaultReleaseArchitecture = "amd64"
+
+// resolveDownloadURLTemplate substitutes tmpl's {tag}, {version}, and {arch}
+// tokens and validates the result is an absolute http(s) URL.
+func resolveDownloadURLTemplate(tmpl, tagName, arch string) (string, error) {
+	version := tagName
+	if v, err := semver.Parse(tagName); err == nil {
+		version = v.String()
+	}
+	resolved := strings.NewReplacer(
+		"{tag}", url.PathEscape(tagName),
+		"{version}", url.PathEscape(version),
+		"{arch}", url.PathEscape(arch),
+	).Replace(tmpl)
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return "", fmt.Errorf("downloadURLTemplate did not resolve to a valid URL: %v", err)
+	}
+	if !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("downloadURLTemplate must resolve to an absolute http(s) URL, got %q", resolved)
+	}
+	return resolved, nil
+}
+
+// urlForArtifacts returns the download link for tagName in release, preferring
+// release.Config.DownloadURLTemplate when set and falling back to the
+// process-wide --artifacts-host pattern otherwise.
+func (c *Controller) urlForArtifacts(release *Release, tagName string) (string, bool) {
+	if tmpl := release.Config.DownloadURLTemplate; len(tmpl) > 0 {
+		arch := release.Config.Architecture
+		if len(arch) == 0 {
+			arch = defaultReleaseArchitecture
+		}
+		if u, err := resolveDownloadURLTemplate(tmpl, tagName, arch); err == nil {
+			return u, true
+		}
+		// parseReleaseConfig already rejects templates that don't resolve to a
+		// valid URL with a placeholder tag, so a failure here would mean tagName
+		// itself produced something unexpected; fall through to the
+		// process-wide default rather than producing no download link at all.
+	}
 	if len(c.artifactsHost) == 0 {
 		return "", false
 	}
@@ -298,11 +403,12 @@ func (c *Controller) apiReleaseLatest(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	downloadURL, _ := c.urlForArtifacts(latest.Name)
+	downloadURL, _ := c.urlForArtifacts(r, latest.Name)
 	resp := LatestAccepted{
 		Name:        latest.Name,
 		PullSpec:    findPublicImagePullSpec(r.Target, latest.Name),
 		DownloadURL: downloadURL,
+		KnownIssues: knownIssuesForTag(latest),
 	}
 
 	switch req.URL.Query().Get("format") {
@@ -393,6 +499,14 @@ func (c *Controller) httpReleaseChangelog(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
+	// The changelog itself is produced by an external oc adm release info
+	// --changelog subprocess (see info.go) that has no notion of this
+	// controller's certification tracking, so rather than modifying that
+	// tool we prepend our own markdown section ahead of its output.
+	if section := certificationChangelogSection(tags[to].Tag, tags[to].Release); len(section) > 0 {
+		out = section + out
+	}
+
 	if isHtml {
 		result := blackfriday.Run([]byte(out))
 		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
@@ -406,6 +520,49 @@ func (c *Controller) httpReleaseChangelog(w http.ResponseWriter, req *http.Reque
 	fmt.Fprintln(w, out)
 }
 
+// httpReleaseStoredChangelog serves the changelog persisted by
+// ensureReleaseChangelog when the tag was Accepted, as opposed to
+// httpReleaseChangelog's on-demand comparison of an arbitrary from/to pair.
+// Unlike /changelog, the release this tag belongs to need not still exist:
+// the persisted ConfigMap is independent of the stream and is kept until the
+// janitor's retention policy for changelogs removes it.
+func (c *Controller) httpReleaseStoredChangelog(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	tag := vars["tag"]
+
+	var isHtml bool
+	switch req.URL.Query().Get("format") {
+	case "html":
+		isHtml = true
+	case "markdown", "":
+	default:
+		http.Error(w, fmt.Sprintf("unrecognized format= string: html, markdown, empty accepted"), http.StatusBadRequest)
+		return
+	}
+
+	_, out, ok, err := c.releaseChangelog(tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal error\n%v", err), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("no changelog has been generated for %s, either it has not been accepted yet or it was the first accepted release in its stream", tag), http.StatusNotFound)
+		return
+	}
+
+	if isHtml {
+		result := blackfriday.Run([]byte(out))
+		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+		fmt.Fprintf(w, htmlPageStart, template.HTMLEscapeString(fmt.Sprintf("Change log for %s", tag)))
+		w.Write(result)
+		fmt.Fprintln(w, htmlPageEnd)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintln(w, out)
+}
+
 func (c *Controller) httpReleaseInfoDownload(w http.ResponseWriter, req *http.Request) {
 	start := time.Now()
 	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
@@ -426,7 +583,7 @@ func (c *Controller) httpReleaseInfoDownload(w http.ResponseWriter, req *http.Re
 		return
 	}
 
-	u, ok := c.urlForArtifacts(tag)
+	u, ok := c.urlForArtifacts(info.Release, tag)
 	if !ok {
 		http.Error(w, "No artifacts download URL is configured, cannot show download link", http.StatusNotFound)
 		return
@@ -483,12 +640,42 @@ func (c *Controller) httpReleaseInfo(w http.ResponseWriter, req *http.Request) {
 	}
 	mirror, _ := c.getMirror(info.Release, info.Tag.Name)
 
+	setDeprecationHeader(w, info.Tag)
+	w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+
+	// The cache key tracks only the release tag's underlying image stream, so
+	// the page is reused until that stream changes; parts of the page drawn
+	// from the upgrade graph or from other release streams can be stale until
+	// then.
+	cacheKey := fmt.Sprintf("releaseInfo\x00%s\x00%s\x00%s\x00%s", info.Release.Target.ResourceVersion, release, tag, from)
+	body, err := c.htmlPageCache.renderOrGet(cacheKey, func() ([]byte, error) {
+		var buf bytes.Buffer
+		c.renderReleaseInfoPage(&buf, tag, info, tagPull, previousTagPull, mirror)
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(body)
+}
+
+// renderReleaseInfoPage renders the body of the release tag detail page for
+// tag into w. It is split out of httpReleaseInfo so its output -- which can
+// be slow to produce because of the changelog fetch below -- can be rendered
+// once and reused for every request that shares a cache key in
+// htmlPageCache. httpReleaseInfo always renders into a buffer rather than a
+// live client, so the incremental "Loading changelog..." progress updates the
+// changelog section writes via flusher.Flush() never reach the caller, even
+// on a cache miss; every caller receives the finished body in one write. This
+// trades away progressive rendering in exchange for not re-rendering the page
+// for every request against an unchanged release.
+func (c *Controller) renderReleaseInfoPage(w io.Writer, tag string, info *ReleaseStreamTag, tagPull, previousTagPull string, mirror *imagev1.ImageStream) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		flusher = nopFlusher{}
 	}
 
-	w.Header().Set("Content-Type", "text/html;charset=UTF-8")
 	fmt.Fprintf(w, htmlPageStart, template.HTMLEscapeString(fmt.Sprintf("Release %s", tag)))
 	defer func() { fmt.Fprintln(w, htmlPageEnd) }()
 
@@ -506,6 +693,15 @@ func (c *Controller) httpReleaseInfo(w http.ResponseWriter, req *http.Request) {
 	fmt.Fprintf(w, "<p><a href=\"/\">Back to index</a></p>\n")
 	fmt.Fprintf(w, "<h1>%s</h1>\n", template.HTMLEscapeString(tag))
 
+	// Surfaced at render time rather than baked into a separate cache key, so
+	// it shares this page's existing cache lifetime (tied to the target
+	// stream's resource version) instead of needing its own invalidation path.
+	if version, err := semver.Parse(tag); err == nil {
+		if reason, err := c.minorVersionFreezeReason(version.Major, version.Minor); err == nil && len(reason) > 0 {
+			fmt.Fprintf(w, "<div class=\"alert alert-warning\">%d.%d is frozen: %s</div>\n", version.Major, version.Minor, template.HTMLEscapeString(reason))
+		}
+	}
+
 	switch info.Tag.Annotations[releaseAnnotationPhase] {
 	case releasePhaseFailed:
 		fmt.Fprintf(w, `<div class="alert alert-danger"><p>%s</p>`, template.HTMLEscapeString(info.Tag.Annotations[releaseAnnotationMessage]))
@@ -518,9 +714,22 @@ func (c *Controller) httpReleaseInfo(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	renderInstallInstructions(w, mirror, info.Tag, tagPull, c.artifactsHost)
+	renderDeprecationWarning(w, info.Tag)
+
+	if issues := knownIssuesForTag(info.Tag); len(issues) > 0 {
+		fmt.Fprintf(w, `<div class="alert alert-warning"><p>Accepted with known issues:</p><ul>`)
+		for _, issue := range issues {
+			fmt.Fprintf(w, `<li><strong>%s</strong>: %s</li>`, template.HTMLEscapeString(issue.Name), template.HTMLEscapeString(issue.Message))
+		}
+		fmt.Fprintf(w, `</ul></div>`)
+	}
+
+	downloadURL, _ := c.urlForArtifacts(info.Release, info.Tag.Name)
+	renderInstallInstructions(w, mirror, info.Tag, tagPull, downloadURL)
 
-	renderVerifyLinks(w, *info.Tag, info.Release)
+	c.renderVerifyLinks(w, *info.Tag, info.Release, info.Older)
+
+	renderCertificationStatus(w, *info.Tag, info.Release)
 
 	if upgradesTo := c.graph.UpgradesTo(tag); len(upgradesTo) > 0 {
 		sort.Sort(newNewestSemVerFromSummaries(upgradesTo))
@@ -709,13 +918,38 @@ func (c *Controller) httpReleaseInfo(w http.ResponseWriter, req *http.Request) {
 	if len(options) > 0 {
 		fmt.Fprint(w, `<p><form class="form-inline" method="GET">`)
 		if info.Previous != nil {
-			fmt.Fprintf(w, `<a href="/changelog?from=%s&to=%s">View changelog in Markdown</a><span>&nbsp;or&nbsp;</span><label for="from">change previous release:&nbsp;</label>`, info.Previous.Name, info.Tag.Name)
+			// Layer diff is linked rather than rendered inline because computing
+			// it means an `oc image info` exec per changed component, which can
+			// be too slow to do synchronously while the rest of this page loads.
+			fmt.Fprintf(w, `<a href="/changelog?from=%s&to=%s">View changelog in Markdown</a><span>&nbsp;or&nbsp;</span><a href="/api/v1/releasestream/%s/release/%s/layerdiff">View image layer diff</a><span>&nbsp;or&nbsp;</span><label for="from">change previous release:&nbsp;</label>`, info.Previous.Name, info.Tag.Name, info.Release.Config.Name, info.Tag.Name)
 		} else {
 			fmt.Fprint(w, `<label for="from">change previous release:&nbsp;</label>`)
 		}
 		fmt.Fprintf(w, `<select onchange="this.form.submit()" id="from" class="form-control" name="from">%s</select> <input class="btn btn-link" type="submit" value="Compare">`, strings.Join(options, ""))
 		fmt.Fprint(w, `</form></p>`)
 	}
+	if len(info.Release.Config.Publish) > 0 {
+		fmt.Fprintf(w, `<p><a href="/api/v1/releasestream/%s/publish-history/%s">View publish history for this tag</a></p>`, info.Release.Config.Name, info.Tag.Name)
+	}
+	if profile := info.Tag.Annotations[releaseAnnotationVerificationProfile]; len(profile) > 0 {
+		fmt.Fprintf(w, `<p>Verification profile: <code>%s</code></p>`, template.HTMLEscapeString(profile))
+	}
+	if len(info.Tag.Annotations[releaseAnnotationImageHash]) > 0 {
+		if duplicates, err := c.findDuplicatePayloads(); err == nil {
+			if others := duplicates[info.Tag.Annotations[releaseAnnotationImageHash]]; len(others) > 1 {
+				var links []string
+				for _, other := range others {
+					if other.Release == info.Release.Config.Name && other.Tag == info.Tag.Name {
+						continue
+					}
+					links = append(links, fmt.Sprintf(`<a href="/releasestream/%s/release/%s">%s/%s</a>`, other.Release, other.Tag, template.HTMLEscapeString(other.Release), template.HTMLEscapeString(other.Tag)))
+				}
+				if len(links) > 0 {
+					fmt.Fprintf(w, `<p>Identical payload also published as: %s</p>`, strings.Join(links, ", "))
+				}
+			}
+		}
+	}
 }
 
 var (
@@ -738,12 +972,15 @@ func (c *Controller) latestForStream(streamName string, constraint semver.Range,
 		}
 		// find all accepted tags, then sort by semantic version
 		tags := findTagReferencesByPhase(r, releasePhaseAccepted)
-		semVers := NewSemanticVersions(tags)
+		semVers := NewSemanticVersions(r.Config.VersionScheme, tags)
 		sort.Sort(semVers)
 		for _, ver := range semVers {
 			if constraint != nil && (ver.Version == nil || !constraint(*ver.Version)) {
 				continue
 			}
+			if deprecationNoticeForTag(ver.Tag) != nil || isVerificationOnly(ver.Tag) {
+				continue
+			}
 			if relativeIndex > 0 {
 				relativeIndex--
 				continue
@@ -771,12 +1008,12 @@ func (c *Controller) httpReleaseLatestDownload(w http.ResponseWriter, req *http.
 	start := time.Now()
 	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
 
-	_, latest, ok := c.locateLatest(w, req)
+	r, latest, ok := c.locateLatest(w, req)
 	if !ok {
 		return
 	}
 
-	u, ok := c.urlForArtifacts(latest.Name)
+	u, ok := c.urlForArtifacts(r, latest.Name)
 	if !ok {
 		http.Error(w, "No artifacts download URL is configured, cannot show download link", http.StatusNotFound)
 		return
@@ -816,6 +1053,9 @@ func (c *Controller) httpReleases(w http.ResponseWriter, req *http.Request) {
 				}
 				return ""
 			},
+			"renderOwners": func(r *ReleaseStream) string {
+				return renderOwners(r.Release.Config.Owners)
+			},
 			"publishDescription": func(r *ReleaseStream) string {
 				if len(r.Release.Config.Message) > 0 {
 					return fmt.Sprintf("<p>%s</p>\n", r.Release.Config.Message)
@@ -889,36 +1129,69 @@ func (c *Controller) httpReleases(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	for _, stream := range imageStreams {
-		r, ok, err := c.releaseDefinition(stream)
-		if err != nil || !ok {
-			continue
-		}
-		s := ReleaseStream{
-			Release: r,
-			Tags:    tagsForRelease(r),
+	fmt.Fprintf(w, htmlPageStart, "Release Status")
+	// The "My streams" banner is specific to the authenticated caller, so it
+	// is rendered directly against the real response rather than folded into
+	// the cached body below.
+	c.renderMyStreamsBanner(w, req)
+
+	// Building page.Streams walks the upgrade graph for every stream, which
+	// dominates this handler's cost, so the cache key only needs to capture
+	// what that walk depends on: the set of image streams and their
+	// resourceVersions, plus the base URL the template embeds into links.
+	cacheKey := fmt.Sprintf("releases\x00%s\x00%s", base.String(), imageStreamsResourceVersionDigest(imageStreams))
+	body, err := c.htmlPageCache.renderOrGet(cacheKey, func() ([]byte, error) {
+		for _, stream := range imageStreams {
+			r, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok {
+				continue
+			}
+			s := ReleaseStream{
+				Release: r,
+				Tags:    tagsForRelease(r),
+			}
+			s.Upgrades = calculateReleaseUpgrades(r, s.Tags, c.graph)
+			page.Streams = append(page.Streams, s)
 		}
-		s.Upgrades = calculateReleaseUpgrades(r, s.Tags, c.graph)
-		page.Streams = append(page.Streams, s)
-	}
 
-	checkReleasePage(page)
+		checkReleasePage(page)
 
-	sort.Slice(page.Streams, func(i, j int) bool {
-		a, b := page.Streams[i], page.Streams[j]
-		if a.Release.Config.As != b.Release.Config.As {
-			return a.Release.Config.As != releaseConfigModeStable
+		sort.Slice(page.Streams, func(i, j int) bool {
+			a, b := page.Streams[i], page.Streams[j]
+			if a.Release.Config.As != b.Release.Config.As {
+				return a.Release.Config.As != releaseConfigModeStable
+			}
+			return a.Release.Config.Name < b.Release.Config.Name
+		})
+
+		var buf bytes.Buffer
+		if err := releasePage.Execute(&buf, page); err != nil {
+			return nil, err
 		}
-		return a.Release.Config.Name < b.Release.Config.Name
+		return buf.Bytes(), nil
 	})
-
-	fmt.Fprintf(w, htmlPageStart, "Release Status")
-	if err := releasePage.Execute(w, page); err != nil {
+	if err != nil {
 		glog.Errorf("Unable to render page: %v", err)
+	} else {
+		w.Write(body)
 	}
 	fmt.Fprintln(w, htmlPageEnd)
 }
 
+// imageStreamsResourceVersionDigest returns a string that changes whenever
+// any image stream in streams is added, removed, or updated, for use as part
+// of an htmlPageCache key. It does not need to be collision-resistant beyond
+// what a cache key requires, so it is simply every stream's
+// namespace/name@resourceVersion joined together.
+func imageStreamsResourceVersionDigest(streams []*imagev1.ImageStream) string {
+	names := make([]string, 0, len(streams))
+	for _, s := range streams {
+		names = append(names, fmt.Sprintf("%s/%s@%s", s.Namespace, s.Name, s.ResourceVersion))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
 var extendedRelTime = []humanize.RelTimeMagnitude{
 	{time.Second, "now", time.Second},
 	{2 * time.Minute, "%d seconds %s", time.Second},
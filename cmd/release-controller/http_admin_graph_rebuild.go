@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// httpAdminGraphRebuild re-scans the controller's cached ProwJobs and re-adds
+// their upgrade edges to the in-memory UpgradeGraph, for recovering from data
+// loss or after a change to edge-recording logic. The optional ?since=90d
+// query parameter limits the scan to jobs that completed within that window
+// (any Go duration, plus a "d" days suffix); omitted, every cached job is
+// scanned.
+//
+// The rebuild runs in the background so it doesn't block the sync loop; the
+// response reports whether it was started, and GET requests to the same URL
+// return the progress of (or result of) the most recently started run.
+func (c *Controller) httpAdminGraphRebuild(w http.ResponseWriter, req *http.Request) {
+	if req.Method == "GET" {
+		c.writeGraphRebuildStatus(w)
+		return
+	}
+
+	var since time.Duration
+	if s := req.URL.Query().Get("since"); len(s) > 0 {
+		d, err := parseSinceDuration(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		since = d
+	}
+
+	sinceLabel := req.URL.Query().Get("since")
+	if !c.graphRebuild.start(sinceLabel) {
+		http.Error(w, "a graph rebuild is already running", http.StatusConflict)
+		return
+	}
+	go func() {
+		err := rebuildGraphFromProwJobs(c.prowLister, c.graph, since, c.graphRebuild)
+		c.graphRebuild.finish(err)
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	c.writeGraphRebuildStatus(w)
+}
+
+func (c *Controller) writeGraphRebuildStatus(w http.ResponseWriter) {
+	data, err := json.MarshalIndent(c.graphRebuild.snapshot(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// AdminOverrideRequest is the payload POSTed to the accept/reject/retry admin
+// override endpoints.
+type AdminOverrideRequest struct {
+	// Reason explains why the override was performed. Required.
+	Reason string `json:"reason"`
+	// Step, for retry only, names a single verification step to retry. If
+	// empty, all verification steps are retried.
+	Step string `json:"step,omitempty"`
+}
+
+// AdminOverrideEvent records a single forced accept, reject, or retry
+// performed through the admin override API, so a tag's history shows that a
+// phase change or re-verification was an operator override rather than the
+// normal sync loop.
+type AdminOverrideEvent struct {
+	Action string `json:"action"`
+	By     string `json:"by"`
+	Reason string `json:"reason,omitempty"`
+	At     string `json:"at"`
+}
+
+// releaseAnnotationAdminOverrides is the JSON serialized []AdminOverrideEvent
+// audit trail of admin override API calls made against a tag. See
+// AdminOverrideEvent.
+const releaseAnnotationAdminOverrides = "release.openshift.io/adminOverrides"
+
+// appendAdminOverrideEvent returns tag's admin override audit log with a new
+// event recording action appended.
+func appendAdminOverrideEvent(tag *imagev1.TagReference, action, by, reason string) string {
+	var events []AdminOverrideEvent
+	if data := tag.Annotations[releaseAnnotationAdminOverrides]; len(data) > 0 {
+		if err := json.Unmarshal([]byte(data), &events); err != nil {
+			events = nil
+		}
+	}
+	events = append(events, AdminOverrideEvent{
+		Action: action,
+		By:     by,
+		Reason: reason,
+		At:     time.Now().UTC().Format(time.RFC3339),
+	})
+	return limitAnnotationSize(releaseAnnotationAdminOverrides, toJSONString(events))
+}
+
+// actorFromRequest returns the identity to record for an admin override
+// performed by req, falling back to "unknown" if no auth proxy user header is
+// configured or present.
+func (c *Controller) actorFromRequest(req *http.Request) string {
+	if user, ok := c.authenticatedUser(req); ok {
+		return user
+	}
+	return "unknown"
+}
+
+func readAdminOverrideRequest(req *http.Request) (AdminOverrideRequest, error) {
+	var update AdminOverrideRequest
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return update, err
+	}
+	if len(body) == 0 {
+		return update, fmt.Errorf("a request body is required")
+	}
+	if err := json.Unmarshal(body, &update); err != nil {
+		return update, fmt.Errorf("invalid request body: %v", err)
+	}
+	if len(update.Reason) == 0 {
+		return update, fmt.Errorf("reason is required")
+	}
+	return update, nil
+}
+
+func (c *Controller) resolveReleaseTag(streamName, tagName string) (*Release, *imagev1.TagReference, error) {
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		return nil, nil, errStreamNotFound
+	}
+	release := streams[streamName].Release
+	tag := findTagReference(release.Target, tagName)
+	if tag == nil {
+		return nil, nil, errStreamTagNotFound
+	}
+	return release, tag, nil
+}
+
+// httpAcceptReleaseTag forces a release tag into the Accepted phase,
+// bypassing normal verification, for release managers responding to an
+// incident without hand-editing imagestream annotations.
+func (c *Controller) httpAcceptReleaseTag(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	release, tag, err := c.resolveReleaseTag(streamName, tagName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	update, err := readAdminOverrideRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotations := map[string]string{
+		releaseAnnotationAdminOverrides: appendAdminOverrideEvent(tag, "Accept", c.actorFromRequest(req), update.Reason),
+	}
+	preconditions := []string{releasePhasePending, releasePhaseReady, releasePhaseFailed, releasePhaseRejected}
+	if err := c.ensureReleaseTagPhase(release, preconditions, releasePhaseAccepted, annotations, tagName); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpRejectReleaseTag forces a release tag into the Rejected phase for
+// release managers responding to an incident without hand-editing imagestream
+// annotations.
+func (c *Controller) httpRejectReleaseTag(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	release, tag, err := c.resolveReleaseTag(streamName, tagName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	update, err := readAdminOverrideRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotations := reasonAndMessage("AdminOverride", update.Reason)
+	annotations[releaseAnnotationRejectReason] = RejectReasonManualReject
+	annotations[releaseAnnotationAdminOverrides] = appendAdminOverrideEvent(tag, "Reject", c.actorFromRequest(req), update.Reason)
+	preconditions := []string{releasePhasePending, releasePhaseReady, releasePhaseFailed, releasePhaseAccepted}
+	if err := c.transitionReleasePhaseFailure(release, preconditions, releasePhaseRejected, annotations, tagName); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpRetryReleaseTag moves a Failed or Ready release tag back to Pending and
+// clears its recorded verification status (or, if Step is set, just that
+// step's), so the sync loop re-runs verification from scratch without an
+// operator hand-editing imagestream annotations.
+func (c *Controller) httpRetryReleaseTag(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	release, tag, err := c.resolveReleaseTag(streamName, tagName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	update, err := readAdminOverrideRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotations := map[string]string{
+		releaseAnnotationAdminOverrides: appendAdminOverrideEvent(tag, "Retry", c.actorFromRequest(req), update.Reason),
+	}
+	if len(update.Step) > 0 {
+		verifyStatus := make(VerificationStatusMap)
+		if data := tag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+			if err := json.Unmarshal([]byte(data), &verifyStatus); err != nil {
+				verifyStatus = make(VerificationStatusMap)
+			}
+		}
+		delete(verifyStatus, update.Step)
+		annotations[releaseAnnotationVerify] = toJSONString(verifyStatus)
+	} else {
+		annotations[releaseAnnotationVerify] = ""
+	}
+
+	preconditions := []string{releasePhaseFailed, releasePhaseReady}
+	if err := c.ensureReleaseTagPhase(release, preconditions, releasePhasePending, annotations, tagName); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// apiV1Sunset is the advertised date after which v1 endpoints may stop being
+// served. It is intentionally generous; bump it forward as v2 coverage grows
+// rather than removing v1 on short notice.
+var apiV1Sunset = time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// deprecatedV1 marks a v1 API handler as deprecated per RFC 8594/draft-ietf-httpapi-deprecation-header,
+// pointing callers at the v2 documentation without changing the v1 response body.
+func deprecatedV1(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", apiV1Sunset.Format(http.TimeFormat))
+		w.Header().Set("Link", `<https://github.com/openshift/release-controller/blob/master/docs/api-v2.md>; rel="successor-version"`)
+		next(w, req)
+	}
+}
+
+// apiEnvelopeV2 wraps every v2 response in a stable, documented shape so new
+// fields can be added without consumers having to guess whether an empty
+// response means "no data" or "wrong endpoint". v1 endpoints return bare
+// arrays/objects and are not changed; v2 is additive so existing integrations
+// can migrate at their own pace.
+type apiEnvelopeV2 struct {
+	APIVersion string      `json:"apiVersion"`
+	Data       interface{} `json:"data"`
+}
+
+// apiOverviewV2 is the v2 equivalent of apiOverview. The v1 schema returns a bare
+// []StreamOverview, which leaks the internal field names/shapes verbatim; v2 wraps
+// the same data in apiEnvelopeV2 so the response format itself is versioned and
+// future fields can be added additively.
+func (c *Controller) apiOverviewV2(w http.ResponseWriter, req *http.Request) {
+	c.overviewCache.lock.Lock()
+	defer c.overviewCache.lock.Unlock()
+
+	if time.Now().After(c.overviewCache.expires) {
+		data, err := c.renderOverview()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.overviewCache.response = data
+		c.overviewCache.expires = time.Now().Add(overviewCacheTTL)
+	}
+
+	var overview []StreamOverview
+	if err := json.Unmarshal(c.overviewCache.response, &overview); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.MarshalIndent(apiEnvelopeV2{APIVersion: "v2", Data: overview}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// badgeColor maps a named health state to the color a shields.io-style badge
+// traditionally uses for it.
+var badgeColor = map[string]string{
+	"green":     "#4c1",
+	"yellow":    "#dfb317",
+	"red":       "#e05d44",
+	"lightgrey": "#9f9f9f",
+}
+
+// badgeSVGTemplate renders a minimal flat badge in the shields.io style: a grey
+// label segment followed by a colored message segment. Segment widths are a
+// rough character-count estimate rather than real font metrics, which is
+// sufficient for the short labels and versions this endpoint renders.
+const badgeSVGTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="20" role="img" aria-label="{{ .Label }}: {{ .Message }}">
+<rect width="{{ .Width }}" height="20" rx="3" fill="#555"/>
+<rect x="{{ .LabelWidth }}" width="{{ .MessageWidth }}" height="20" rx="3" fill="{{ .Color }}"/>
+<rect x="{{ .LabelWidth }}" width="3" height="20" fill="{{ .Color }}"/>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{ .LabelCenter }}" y="14">{{ .Label }}</text>
+<text x="{{ .MessageCenter }}" y="14">{{ .Message }}</text>
+</g>
+</svg>
+`
+
+var badgeSVG = template.Must(template.New("badge").Parse(badgeSVGTemplate))
+
+type badgeData struct {
+	Width         int
+	LabelWidth    int
+	MessageWidth  int
+	LabelCenter   int
+	MessageCenter int
+	Color         string
+	Label         string
+	Message       string
+}
+
+func renderBadgeSVG(w http.ResponseWriter, label, message, color string) {
+	if _, ok := badgeColor[color]; !ok {
+		color = "lightgrey"
+	}
+	labelWidth := 6*len(label) + 20
+	messageWidth := 6*len(message) + 20
+	data := badgeData{
+		Width:         labelWidth + messageWidth,
+		LabelWidth:    labelWidth,
+		MessageWidth:  messageWidth,
+		LabelCenter:   labelWidth / 2,
+		MessageCenter: labelWidth + messageWidth/2,
+		Color:         badgeColor[color],
+		Label:         label,
+		Message:       message,
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	// Badges are embedded in READMEs and wikis that are rarely reloaded, but the
+	// underlying release can move at any time, so keep the cache window short
+	// rather than disabling caching entirely.
+	w.Header().Set("Cache-Control", "max-age=120")
+	badgeSVG.Execute(w, data)
+}
+
+// httpBadge serves an SVG badge summarizing the state of a release stream,
+// suitable for embedding in a README or wiki page.
+//
+// /releasestream/{release}/badge.svg              - latest accepted version
+// /releasestream/{release}/badge.svg?style=health  - health color (green/yellow/red)
+func (c *Controller) httpBadge(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName := vars["release"]
+
+	streamTagMap, ok := c.findReleaseByName(false, streamName)
+	if !ok || streamTagMap[streamName] == nil {
+		renderBadgeSVG(w, streamName, "not found", "lightgrey")
+		return
+	}
+	release := streamTagMap[streamName].Release
+	accepted := tagsForRelease(release, releasePhaseAccepted)
+
+	if req.URL.Query().Get("style") == "health" {
+		color, message := "lightgrey", "unknown"
+		if len(accepted) > 0 {
+			color, message = "green", "accepted"
+		}
+		if recent := tagsForRelease(release); len(recent) > 0 {
+			switch recent[0].Annotations[releaseAnnotationPhase] {
+			case releasePhaseRejected, releasePhaseFailed:
+				color, message = "red", "failing"
+			case releasePhasePending, releasePhaseReady:
+				if color != "green" {
+					color, message = "yellow", "pending"
+				}
+			}
+		}
+		if configured, breached, _ := releaseFreshnessBreach(release, accepted, time.Now()); configured && breached {
+			color, message = "red", "stale"
+		}
+		renderBadgeSVG(w, streamName, message, color)
+		return
+	}
+
+	if len(accepted) == 0 {
+		renderBadgeSVG(w, streamName, "none", "lightgrey")
+		return
+	}
+	renderBadgeSVG(w, streamName, accepted[0].Name, "green")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// httpBuildLogTail proxies the last N lines of a verification step's build
+// log from its artifact bucket, so a user triaging a rejection can see the
+// obvious error without navigating away to the job's full log. A shorter tail
+// of the same log (inlineBuildLogTailLines) is embedded inline on the tag page
+// by renderVerifyLinks.
+//
+// Query parameters:
+//
+//	lines - how many trailing lines to return, default defaultBuildLogTailLines,
+//	        capped at maxBuildLogTailLines
+func (c *Controller) httpBuildLogTail(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName, verification := vars["release"], vars["tag"], vars["verification"]
+
+	streams, ok := c.findReleaseByName(true, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	if findTagReference(streams[streamName].Release.Target, tagName) == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	lines := defaultBuildLogTailLines
+	if v := req.URL.Query().Get("lines"); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "lines must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		lines = n
+	}
+
+	prowJobName := fmt.Sprintf("%s-%s", tagName, verification)
+	obj, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, fmt.Sprintf("no verification job named %s has run for this tag", verification), http.StatusNotFound)
+		return
+	}
+	status, ok := prowJobVerificationStatus(obj.(*unstructured.Unstructured))
+	if !ok || len(status.URL) == 0 {
+		http.Error(w, "this verification job has no log to display yet", http.StatusNotFound)
+		return
+	}
+	logURL, ok := buildLogURLFromSpyglassURL(status.URL)
+	if !ok {
+		http.Error(w, "this verification job's log is not hosted in a location this proxy understands", http.StatusNotFound)
+		return
+	}
+
+	tail, err := c.fetchBuildLogTail(logURL, lines)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to fetch build log: %v", err), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+	fmt.Fprint(w, strings.Join(tail, "\n"))
+}
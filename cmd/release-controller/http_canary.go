@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// CanaryFeedbackRequest is the payload a registered canary consumer POSTs to
+// report on a tag's health after a PublishTagReference advances.
+type CanaryFeedbackRequest struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// httpRegisterCanary registers consumer as a canary for release, so it may
+// later submit feedback against that release's canary-gated publish steps.
+func (c *Controller) httpRegisterCanary(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, consumer := vars["release"], vars["consumer"]
+
+	if _, ok := c.findReleaseByName(false, streamName); !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	c.canaries.registerConsumer(streamName, consumer)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpCanaryFeedback records a registered consumer's health feedback against
+// the open canary window for the named publish step.
+func (c *Controller) httpCanaryFeedback(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, consumer, publish := vars["release"], vars["consumer"], vars["publish"]
+
+	if _, ok := c.findReleaseByName(false, streamName); !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var feedback CanaryFeedbackRequest
+	if err := json.Unmarshal(body, &feedback); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.canaries.recordFeedback(streamName, publish, consumer, feedback.Healthy, feedback.Message, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpCanaryStatus reports the current or most recently evaluated canary
+// window for a release's publish step.
+func (c *Controller) httpCanaryStatus(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, publish := vars["release"], vars["publish"]
+
+	window, ok := c.canaries.snapshot(streamName, publish)
+	if !ok {
+		http.Error(w, "no canary window has been opened for this publish step", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.MarshalIndent(window, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -16,6 +16,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	imagev1 "github.com/openshift/api/image/v1"
+
+	"github.com/openshift/release-controller/pkg/releasecontroller"
 )
 
 const candidatePageHtml = `
@@ -105,6 +107,14 @@ func (c *Controller) httpReleaseCandidateList(w http.ResponseWriter, req *http.R
 	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
 	vars := mux.Vars(req)
 	releaseStreamName := vars["release"]
+
+	if streamTagMap, ok := c.findReleaseByName(false, releaseStreamName); ok && streamTagMap[releaseStreamName] != nil {
+		if streamTagMap[releaseStreamName].Release.Config.As == releaseConfigModeHotfix {
+			http.Error(w, "candidate listing is not available for hotfix release streams", http.StatusNotFound)
+			return
+		}
+	}
+
 	successPercent := 80.0
 	releaseCandidateList, err := c.findReleaseCandidates(successPercent, releaseStreamName)
 	if err != nil {
@@ -185,6 +195,13 @@ type releaseInfoShort struct {
 }
 
 func (c *Controller) findReleaseCandidates(upgradeSuccessPercent float64, releaseStreams ...string) (map[string]*ReleaseCandidateList, error) {
+	return c.findReleaseCandidatesWithWeights(upgradeSuccessPercent, defaultCandidateScoreWeights, releaseStreams...)
+}
+
+// findReleaseCandidatesWithWeights is findReleaseCandidates with the ranking
+// weights exposed, for callers that want to tune how heavily verification
+// results, upgrade-edge success, and age factor into the ranking.
+func (c *Controller) findReleaseCandidatesWithWeights(upgradeSuccessPercent float64, weights CandidateScoreWeights, releaseStreams ...string) (map[string]*ReleaseCandidateList, error) {
 	releaseCandidates := make(map[string]*ReleaseCandidateList)
 	if len(releaseStreams) == 0 {
 		return releaseCandidates, nil
@@ -230,25 +247,39 @@ func (c *Controller) findReleaseCandidates(upgradeSuccessPercent float64, releas
 		latestPromotedTime = promotedTime.Unix()
 
 		candidates := make([]*ReleaseCandidate, 0)
-		releaseTags := tagsForRelease(releaseStreamTagMap[stream].Release)
-		for _, tag := range releaseTags {
+		streamRelease := releaseStreamTagMap[stream].Release
+		releaseTags := tagsForRelease(streamRelease)
+		for i, tag := range releaseTags {
 			if tag.Annotations != nil && tag.Annotations[releaseAnnotationPhase] == releasePhaseAccepted &&
-				tag.Annotations[releaseAnnotationCreationTimestamp] != "" {
+				tag.Annotations[releaseAnnotationCreationTimestamp] != "" && deprecationNoticeForTag(tag) == nil {
 				t, _ := time.Parse(time.RFC3339, tag.Annotations[releaseAnnotationCreationTimestamp])
 				ts := t.Unix()
 				if ts > latestPromotedTime {
 
 					upgradeSuccess := make([]string, 0)
+					var excluded []ExcludedUpgrade
 					upgrades := c.graph.UpgradesTo(tag.Name)
 					for _, u := range upgrades {
 						if u.Total == 0 {
 							continue
 						}
-						if float64(100*u.Success)/float64(u.Total) > upgradeSuccessPercent {
-							upgradeSuccess = append(upgradeSuccess, u.From)
+						if float64(100*u.Success)/float64(u.Total) <= upgradeSuccessPercent {
+							continue
+						}
+						if reason, ok := releaseStreamTagMap[stream].Release.Config.excludedUpgradeReason(u.From); ok {
+							excluded = append(excluded, ExcludedUpgrade{From: u.From, Reason: reason})
+							continue
 						}
+						upgradeSuccess = append(upgradeSuccess, u.From)
 					}
 					sort.Strings(upgradeSuccess)
+					sort.Slice(excluded, func(i, j int) bool { return excluded[i].From < excluded[j].From })
+
+					previous := findPreviousRelease(tag, releaseTags[i+1:], streamRelease)
+					risk, err := c.computeRiskScore(streamRelease, tag, streamRelease, previous)
+					if err != nil {
+						risk = nil
+					}
 
 					candidates = append(candidates, &ReleaseCandidate{
 						ReleasePromoteJobParameters: ReleasePromoteJobParameters{
@@ -256,13 +287,19 @@ func (c *Controller) findReleaseCandidates(upgradeSuccessPercent float64, releas
 							Name:        nextReleaseName,
 							UpgradeFrom: upgradeSuccess,
 						},
-						CreationTime: time.Unix(ts, 0).Format(time.RFC3339),
-						Tag:          tag,
+						CreationTime:         time.Unix(ts, 0).Format(time.RFC3339),
+						Tag:                  tag,
+						ExcludedUpgradesFrom: excluded,
+						Score:                scoreCandidate(tag, upgrades, ts, weights, risk),
+						KnownIssues:          knownIssuesForTag(tag),
 					})
 				}
 			}
 		}
 		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Score.Total != candidates[j].Score.Total {
+				return candidates[i].Score.Total > candidates[j].Score.Total
+			}
 			return candidates[i].CreationTime > candidates[j].CreationTime
 		})
 		releaseCandidates[stream] = &ReleaseCandidateList{Items: candidates}
@@ -270,6 +307,48 @@ func (c *Controller) findReleaseCandidates(upgradeSuccessPercent float64, releas
 	return releaseCandidates, nil
 }
 
+// scoreCandidate combines the tag's own verification results, the success ratio of
+// upgrade edges landing on it, its age, and (if risk is non-nil) its RiskScore
+// into a CandidateScore, weighted by weights. Higher is better: a tag that
+// passed more of its verification jobs, has a better upgrade track record,
+// was promoted more recently, and carries less risk ranks higher.
+func scoreCandidate(tag *imagev1.TagReference, upgrades []releasecontroller.UpgradeHistory, ts int64, weights CandidateScoreWeights, risk *RiskScore) *CandidateScore {
+	score := &CandidateScore{Risk: risk}
+
+	if data := tag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+		var status VerificationStatusMap
+		if err := json.Unmarshal([]byte(data), &status); err == nil && len(status) > 0 {
+			var passed int
+			for _, v := range status {
+				if v != nil && v.State == releaseVerificationStateSucceeded {
+					passed++
+				}
+			}
+			score.VerifyPassRatio = float64(passed) / float64(len(status))
+		}
+	}
+
+	var upgradeSuccess, upgradeTotal int
+	for _, u := range upgrades {
+		upgradeSuccess += u.Success
+		upgradeTotal += u.Total
+	}
+	if upgradeTotal > 0 {
+		score.UpgradeSuccessRatio = float64(upgradeSuccess) / float64(upgradeTotal)
+	}
+
+	score.AgeHours = time.Since(time.Unix(ts, 0)).Hours()
+	if score.AgeHours < 0 {
+		score.AgeHours = 0
+	}
+
+	score.Total = weights.Verify*score.VerifyPassRatio + weights.Upgrade*score.UpgradeSuccessRatio - weights.Age*(score.AgeHours/24)
+	if risk != nil {
+		score.Total -= weights.Risk * risk.Total
+	}
+	return score
+}
+
 func (c *Controller) findReleaseByName(includeStableTags bool, names ...string) (map[string]*ReleaseStreamTag, bool) {
 	needed := make(map[string]*ReleaseStreamTag)
 	for _, name := range names {
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// CertificationUpdateRequest is the payload POSTed to advance a tag's
+// certification workflow.
+type CertificationUpdateRequest struct {
+	// State is one of Submitted, InReview, Certified, or Rejected.
+	State string `json:"state"`
+	// Note is a free-form annotation attached to this transition, e.g. a link
+	// to the certification lab's tracking ticket.
+	Note string `json:"note,omitempty"`
+}
+
+func validCertificationState(state string) bool {
+	switch state {
+	case certificationStateSubmitted, certificationStateInReview, certificationStateCertified, certificationStateRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// httpUpdateCertification records a certification workflow transition for an
+// accepted release tag. name must be one of the stream's configured
+// ReleaseConfig.Certifications.
+func (c *Controller) httpUpdateCertification(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName, name := vars["release"], vars["tag"], vars["name"]
+
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[streamName].Release
+
+	if !stringSliceContains(release.Config.Certifications, name) {
+		http.Error(w, fmt.Sprintf("release %s has no certification named %s", streamName, name), http.StatusNotFound)
+		return
+	}
+	if findTagReference(release.Target, tagName) == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var update CertificationUpdateRequest
+	if err := json.Unmarshal(body, &update); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !validCertificationState(update.State) {
+		http.Error(w, fmt.Sprintf("state must be one of %s, %s, %s, or %s", certificationStateSubmitted, certificationStateInReview, certificationStateCertified, certificationStateRejected), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.applyCertificationUpdate(release, tagName, name, update); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyCertificationUpdate merges update into the certifications annotation of
+// the named release tag, which must currently be Accepted.
+func (c *Controller) applyCertificationUpdate(release *Release, tagName, name string, update CertificationUpdateRequest) error {
+	tag := findTagReference(release.Target, tagName)
+	if tag == nil {
+		return fmt.Errorf("release tag %s no longer exists", tagName)
+	}
+
+	var workflows CertificationWorkflowMap
+	if data := tag.Annotations[releaseAnnotationCertifications]; len(data) > 0 {
+		workflows = make(CertificationWorkflowMap)
+		if err := json.Unmarshal([]byte(data), &workflows); err != nil {
+			glog.Errorf("Release %s has invalid certifications status, overwriting: %v", tagName, err)
+			workflows = make(CertificationWorkflowMap)
+		}
+	} else {
+		workflows = make(CertificationWorkflowMap)
+	}
+
+	workflow, ok := workflows[name]
+	if !ok {
+		workflow = &CertificationWorkflow{}
+		workflows[name] = workflow
+	}
+	workflow.State = update.State
+	workflow.History = append(workflow.History, CertificationEvent{
+		State: update.State,
+		At:    time.Now().UTC().Format(time.RFC3339),
+		Note:  update.Note,
+	})
+
+	return c.setReleaseAnnotation(release, releasePhaseAccepted, map[string]string{
+		releaseAnnotationCertifications: limitAnnotationSize(releaseAnnotationCertifications, toJSONString(workflows)),
+	}, tagName)
+}
+
+// certificationChangelogSection renders a markdown summary of tag's
+// certification workflow states, for inclusion in a release notes draft, or
+// the empty string if release has not opted into any certifications.
+func certificationChangelogSection(tag *imagev1.TagReference, release *Release) string {
+	if tag == nil || len(release.Config.Certifications) == 0 {
+		return ""
+	}
+
+	var workflows CertificationWorkflowMap
+	if data := tag.Annotations[releaseAnnotationCertifications]; len(data) > 0 {
+		workflows = make(CertificationWorkflowMap)
+		if err := json.Unmarshal([]byte(data), &workflows); err != nil {
+			return ""
+		}
+	}
+	if len(workflows) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(release.Config.Certifications))
+	copy(names, release.Config.Certifications)
+	sort.Strings(names)
+
+	section := "## Certification Status\n\n"
+	for _, name := range names {
+		workflow, ok := workflows[name]
+		if !ok {
+			continue
+		}
+		section += fmt.Sprintf("- **%s**: %s\n", name, workflow.State)
+	}
+	return section + "\n"
+}
+
+// renderCertificationStatus renders the current state of each certification
+// workflow configured for release on tag's page, if release opted into any
+// via ReleaseConfig.Certifications.
+func renderCertificationStatus(w io.Writer, tag imagev1.TagReference, release *Release) {
+	if len(release.Config.Certifications) == 0 {
+		return
+	}
+
+	var workflows CertificationWorkflowMap
+	if data := tag.Annotations[releaseAnnotationCertifications]; len(data) > 0 {
+		workflows = make(CertificationWorkflowMap)
+		if err := json.Unmarshal([]byte(data), &workflows); err != nil {
+			fmt.Fprintf(w, `<p><em class="text-danger">Unable to load certification status</em></p>`)
+			return
+		}
+	}
+
+	names := make([]string, len(release.Config.Certifications))
+	copy(names, release.Config.Certifications)
+	sort.Strings(names)
+
+	fmt.Fprintf(w, `<p id="certifications">Certifications:</p><ul>`)
+	for _, name := range names {
+		state := "Not submitted"
+		class := "text-muted"
+		if workflow, ok := workflows[name]; ok {
+			state = workflow.State
+			switch workflow.State {
+			case certificationStateCertified:
+				class = "text-success"
+			case certificationStateRejected:
+				class = "text-danger"
+			default:
+				class = ""
+			}
+		}
+		fmt.Fprintf(w, `<li><span class="%s">%s: %s</span></li>`, class, template.HTMLEscapeString(name), template.HTMLEscapeString(state))
+	}
+	fmt.Fprintf(w, `</ul>`)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ChannelLatest is the response for /api/v1/channels/{channel}/latest: the
+// release this controller currently recommends for that channel, computed
+// directly from its own stream state and publish steps rather than waiting
+// for an externally published Cincinnati graph, for internal consumers that
+// want a fast, always-current answer.
+type ChannelLatest struct {
+	Channel  string `json:"channel"`
+	Stream   string `json:"stream"`
+	Version  string `json:"version"`
+	PullSpec string `json:"pullSpec"`
+}
+
+// channelPublishStep maps a channel type to the Publish step name a stream
+// must define, with a TagRef, to participate in it: "fast" and "stable" are
+// promotion tiers, so they only recognize a release once this controller has
+// actually pointed that named tag at it. "candidate" has no entry here: it
+// reflects this stream's own Accepted state directly, so any stream
+// qualifies without additional publish configuration. Real Cincinnati also
+// has "eus" channels and cross-channel promotion windows; neither is modeled
+// by this controller's data, so requests for anything else are rejected
+// rather than guessed at.
+var channelPublishStep = map[string]string{
+	"fast":   "fast",
+	"stable": "stable",
+}
+
+// splitChannel parses a channel name of the form "<type>" or
+// "<type>-<major>.<minor>", the same "name" / "name-branch" shape the
+// ?channel= query parameter on /api/graph already uses.
+func splitChannel(channel string) (channelType, branch string, ok bool) {
+	channelType = channel
+	if idx := strings.IndexByte(channel, '-'); idx >= 0 {
+		channelType = channel[:idx]
+		branch = channel[idx+1:]
+		major, minor, found := strings.Cut(branch, ".")
+		if !found {
+			return "", "", false
+		}
+		if _, err := strconv.Atoi(major); err != nil {
+			return "", "", false
+		}
+		if _, err := strconv.Atoi(minor); err != nil {
+			return "", "", false
+		}
+	}
+	switch channelType {
+	case "stable", "fast", "candidate":
+		return channelType, branch, true
+	default:
+		return "", "", false
+	}
+}
+
+// httpChannelLatest resolves the release a consumer should install today for
+// a simple stable/fast/candidate channel, optionally scoped to a
+// "<major>.<minor>" branch, without waiting for this controller's graph to
+// be picked up and republished by an external Cincinnati instance.
+func (c *Controller) httpChannelLatest(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	channel := mux.Vars(req)["channel"]
+	channelType, branch, ok := splitChannel(channel)
+	if !ok {
+		http.Error(w, "error: channel must be 'stable', 'fast', or 'candidate', optionally suffixed with '-<major>.<minor>'", http.StatusBadRequest)
+		return
+	}
+
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var best *ChannelLatest
+	var bestVersion semver.Version
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+
+		var version string
+		if stepName, scoped := channelPublishStep[channelType]; scoped {
+			step, ok := r.Config.Publish[stepName]
+			if !ok || step.Disabled || step.TagRef == nil || len(step.TagRef.Name) == 0 {
+				continue
+			}
+			tag := findTagReference(r.Target, step.TagRef.Name)
+			if tag == nil || tag.From == nil || len(tag.From.Name) == 0 {
+				continue
+			}
+			version = tag.From.Name
+		} else {
+			tags := tagsForRelease(r, releasePhaseAccepted)
+			if len(tags) == 0 {
+				continue
+			}
+			version = tags[0].Name
+		}
+
+		if len(branch) > 0 && !strings.HasPrefix(version, branch+".") {
+			continue
+		}
+		ver, err := semverParseTolerant(version)
+		if err != nil {
+			continue
+		}
+		if best != nil && ver.Compare(bestVersion) <= 0 {
+			continue
+		}
+		bestVersion = ver
+		best = &ChannelLatest{
+			Channel:  channel,
+			Stream:   r.Config.Name,
+			Version:  version,
+			PullSpec: findPublicImagePullSpec(r.Target, version),
+		}
+	}
+
+	if best == nil {
+		http.Error(w, "error: no release found for this channel", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.MarshalIndent(best, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
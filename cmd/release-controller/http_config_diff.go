@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/labels"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// releaseAnnotationConfigNext holds a staged ReleaseConfig that has not yet taken
+// effect. It is serialized identically to releaseAnnotationConfig. Setting this
+// annotation has no effect on the running controller until it is promoted (copied)
+// into releaseAnnotationConfig, which lets an operator preview the consequences of a
+// config change before it can affect live tags.
+const releaseAnnotationConfigNext = "release.openshift.io/config-next"
+
+// ConfigDiff summarizes the differences the controller would apply between the
+// active ReleaseConfig and a staged config, in terms a release manager can review
+// without diffing raw JSON.
+type ConfigDiff struct {
+	VerifyAdded    []string `json:"verifyAdded,omitempty"`
+	VerifyRemoved  []string `json:"verifyRemoved,omitempty"`
+	VerifyChanged  []string `json:"verifyChanged,omitempty"`
+	PublishAdded   []string `json:"publishAdded,omitempty"`
+	PublishRemoved []string `json:"publishRemoved,omitempty"`
+	PublishChanged []string `json:"publishChanged,omitempty"`
+	// Other is set when fields outside Verify/Publish differ (name, to, message, etc).
+	Other []string `json:"other,omitempty"`
+}
+
+// Empty returns true if promoting the staged config would change nothing the
+// controller acts on.
+func (d *ConfigDiff) Empty() bool {
+	return len(d.VerifyAdded) == 0 && len(d.VerifyRemoved) == 0 && len(d.VerifyChanged) == 0 &&
+		len(d.PublishAdded) == 0 && len(d.PublishRemoved) == 0 && len(d.PublishChanged) == 0 &&
+		len(d.Other) == 0
+}
+
+func diffReleaseConfigs(current, next *ReleaseConfig) *ConfigDiff {
+	diff := &ConfigDiff{}
+	for name := range next.Verify {
+		if _, ok := current.Verify[name]; !ok {
+			diff.VerifyAdded = append(diff.VerifyAdded, name)
+		}
+	}
+	for name, v := range current.Verify {
+		nv, ok := next.Verify[name]
+		if !ok {
+			diff.VerifyRemoved = append(diff.VerifyRemoved, name)
+			continue
+		}
+		if toJSONString(v) != toJSONString(nv) {
+			diff.VerifyChanged = append(diff.VerifyChanged, name)
+		}
+	}
+	for name := range next.Publish {
+		if _, ok := current.Publish[name]; !ok {
+			diff.PublishAdded = append(diff.PublishAdded, name)
+		}
+	}
+	for name, p := range current.Publish {
+		np, ok := next.Publish[name]
+		if !ok {
+			diff.PublishRemoved = append(diff.PublishRemoved, name)
+			continue
+		}
+		if toJSONString(p) != toJSONString(np) {
+			diff.PublishChanged = append(diff.PublishChanged, name)
+		}
+	}
+	if current.Name != next.Name {
+		diff.Other = append(diff.Other, fmt.Sprintf("name: %s -> %s", current.Name, next.Name))
+	}
+	if current.To != next.To {
+		diff.Other = append(diff.Other, fmt.Sprintf("to: %s -> %s", current.To, next.To))
+	}
+	if current.As != next.As {
+		diff.Other = append(diff.Other, fmt.Sprintf("as: %s -> %s", current.As, next.As))
+	}
+	if current.Expires != next.Expires {
+		diff.Other = append(diff.Other, fmt.Sprintf("expires: %s -> %s", current.Expires.Duration(), next.Expires.Duration()))
+	}
+
+	sort.Strings(diff.VerifyAdded)
+	sort.Strings(diff.VerifyRemoved)
+	sort.Strings(diff.VerifyChanged)
+	sort.Strings(diff.PublishAdded)
+	sort.Strings(diff.PublishRemoved)
+	sort.Strings(diff.PublishChanged)
+	return diff
+}
+
+// releaseStreamSource locates the image stream backing a named release config.
+func (c *Controller) releaseStreamSource(streamName string) (*imageStreamAndRelease, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if r.Config.Name != streamName {
+			continue
+		}
+		return &imageStreamAndRelease{stream: stream, release: r}, nil
+	}
+	return nil, errStreamNotFound
+}
+
+type imageStreamAndRelease struct {
+	stream  *imagev1.ImageStream
+	release *Release
+}
+
+// httpConfigDiff shows what the controller would do differently if the staged
+// release.openshift.io/config-next annotation were promoted into effect.
+func (c *Controller) httpConfigDiff(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	vars := mux.Vars(req)
+	found, err := c.releaseStreamSource(vars["release"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	next, ok := found.stream.Annotations[releaseAnnotationConfigNext]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no %s annotation is staged for this stream", releaseAnnotationConfigNext), http.StatusNotFound)
+		return
+	}
+	nextConfig, err := c.parseReleaseConfig(next)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("staged config is invalid: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	diff := diffReleaseConfigs(found.release.Config, nextConfig)
+
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	fmt.Fprintln(w)
+}
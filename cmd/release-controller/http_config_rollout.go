@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+)
+
+// ConfigRolloutRequest is the payload POSTed to start a config rollout.
+type ConfigRolloutRequest struct {
+	Patch    json.RawMessage `json:"patch"`
+	BakeTime Duration        `json:"bakeTime"`
+	// Canary, if set, overrides which matching stream receives the patch
+	// first. Defaults to the alphabetically first matching stream.
+	Canary string `json:"canary,omitempty"`
+}
+
+// httpStartConfigRollout begins rolling the patch in the request body out to
+// every release stream whose name has the {prefix} path variable as a
+// prefix, applying it to one canary stream immediately and the rest after
+// BakeTime elapses without the rollout being cancelled.
+func (c *Controller) httpStartConfigRollout(w http.ResponseWriter, req *http.Request) {
+	prefix := mux.Vars(req)["prefix"]
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request ConfigRolloutRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(request.Patch) == 0 {
+		http.Error(w, "patch is required", http.StatusBadRequest)
+		return
+	}
+
+	streams, err := c.matchingConfigRolloutStreams(prefix)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(streams) == 0 {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	canary := streams[0]
+	if len(request.Canary) > 0 {
+		canary = nil
+		for _, r := range streams {
+			if r.Config.Name == request.Canary {
+				canary = r
+				break
+			}
+		}
+		if canary == nil {
+			http.Error(w, "canary does not match namePrefix", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var remaining []string
+	for _, r := range streams {
+		if r.Config.Name != canary.Config.Name {
+			remaining = append(remaining, r.Config.Name)
+		}
+	}
+
+	if err := c.applyConfigRolloutPatch(canary, request.Patch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := &ConfigRolloutStatus{
+		Spec: ConfigRolloutSpec{
+			NamePrefix: prefix,
+			Patch:      request.Patch,
+			BakeTime:   request.BakeTime,
+		},
+		Phase:     "Canary",
+		Canary:    canary.Config.Name,
+		Remaining: remaining,
+		Deadline:  time.Now().Add(request.BakeTime.Duration()),
+	}
+	c.rollouts.start(prefix, status)
+	if err := c.scheduleConfigRolloutDeadline(status); err != nil {
+		glog.Errorf("Unable to persist config rollout deadline for %s: %v", prefix, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+// httpConfigRolloutStatus reports the current state of a config rollout.
+func (c *Controller) httpConfigRolloutStatus(w http.ResponseWriter, req *http.Request) {
+	prefix := mux.Vars(req)["prefix"]
+
+	status, ok := c.rollouts.snapshot(prefix)
+	if !ok {
+		http.Error(w, "no config rollout is in progress for this prefix", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// httpCancelConfigRollout stops tracking a config rollout and clears its
+// persisted bake deadline so it is not rolled out to its remaining streams.
+// It does not revert the patch already applied to the canary stream.
+func (c *Controller) httpCancelConfigRollout(w http.ResponseWriter, req *http.Request) {
+	prefix := mux.Vars(req)["prefix"]
+
+	if !c.rollouts.cancel(prefix) {
+		http.Error(w, "no config rollout is in progress for this prefix", http.StatusNotFound)
+		return
+	}
+	if err := c.cancelDelayedAction(configRolloutDeadlineID(prefix)); err != nil {
+		glog.Errorf("Unable to clear persisted config rollout deadline for %s: %v", prefix, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
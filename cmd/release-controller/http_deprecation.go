@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// DeprecateTagRequest is the payload POSTed to mark an Accepted tag
+// Deprecated.
+type DeprecateTagRequest struct {
+	// Reason explains why the tag was deprecated. Required.
+	Reason string `json:"reason"`
+	// Replacement, if set, names the tag consumers should use instead.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// httpDeprecateTag marks an Accepted release tag Deprecated (POST, with a
+// DeprecateTagRequest body) or clears an existing deprecation (DELETE).
+func (c *Controller) httpDeprecateTag(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[streamName].Release
+	if findTagReference(release.Target, tagName) == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodDelete:
+		if err := c.setReleaseAnnotation(release, releasePhaseAccepted, map[string]string{
+			releaseAnnotationDeprecated: "",
+		}, tagName); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	default:
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var request DeprecateTagRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(request.Reason) == 0 {
+			http.Error(w, "reason is required", http.StatusBadRequest)
+			return
+		}
+		notice := DeprecationNotice{
+			Reason:      request.Reason,
+			Replacement: request.Replacement,
+			At:          time.Now().UTC().Format(time.RFC3339),
+		}
+		if err := c.setReleaseAnnotation(release, releasePhaseAccepted, map[string]string{
+			releaseAnnotationDeprecated: toJSONString(notice),
+		}, tagName); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setDeprecationHeader sets X-Release-Deprecated on w if tag carries a
+// DeprecationNotice, so a consumer fetching any JSON response that
+// references the tag can detect it without parsing the body.
+func setDeprecationHeader(w http.ResponseWriter, tag *imagev1.TagReference) {
+	if notice := deprecationNoticeForTag(tag); notice != nil {
+		w.Header().Set("X-Release-Deprecated", notice.Reason)
+	}
+}
+
+// renderDeprecationWarning renders a warning banner for tag's page if it has
+// been deprecated.
+func renderDeprecationWarning(w io.Writer, tag *imagev1.TagReference) {
+	notice := deprecationNoticeForTag(tag)
+	if notice == nil {
+		return
+	}
+	fmt.Fprintf(w, `<div class="alert alert-danger"><p><strong>Deprecated</strong>: %s</p>`, template.HTMLEscapeString(notice.Reason))
+	if len(notice.Replacement) > 0 {
+		fmt.Fprintf(w, `<p>Use <a href="/releasetag/%s">%s</a> instead.</p>`, template.HTMLEscapeString(notice.Replacement), template.HTMLEscapeString(notice.Replacement))
+	}
+	fmt.Fprintf(w, `</div>`)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// httpDuplicatePayloads reports the other release stream tags, if any, that
+// carry the same payload image hash as {release}/{tag}, per
+// (*Controller).findDuplicatePayloads.
+func (c *Controller) httpDuplicatePayloads(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	streams, ok := c.findReleaseByName(true, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	tag := findTagReference(streams[streamName].Release.Target, tagName)
+	if tag == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	var others []DuplicatePayloadTag
+	if hash := tag.Annotations[releaseAnnotationImageHash]; len(hash) > 0 {
+		duplicates, err := c.findDuplicatePayloads()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, dup := range duplicates[hash] {
+			if dup.Release == streamName && dup.Tag == tagName {
+				continue
+			}
+			others = append(others, dup)
+		}
+	}
+
+	data, err := json.MarshalIndent(others, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// httpReleaseEvents serves the retained event history for a release stream,
+// beyond the roughly one-hour TTL Kubernetes applies to Events themselves.
+//
+// Query parameters:
+//
+//	type  - filter to a single event type, "Normal" or "Warning"
+//	since - only events at or after this RFC3339 timestamp
+//	until - only events at or before this RFC3339 timestamp
+func (c *Controller) httpReleaseEvents(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, releaseStreamName)
+	if !ok || streams[releaseStreamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	query := req.URL.Query()
+	var since, until time.Time
+	if v := query.Get("since"); len(v) > 0 {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("since must be a RFC3339 timestamp: %v", err), http.StatusBadRequest)
+			return
+		}
+		since = t
+	}
+	if v := query.Get("until"); len(v) > 0 {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("until must be a RFC3339 timestamp: %v", err), http.StatusBadRequest)
+			return
+		}
+		until = t
+	}
+
+	events := c.eventHistory.list(streams[releaseStreamName].Release.Source.Name, query.Get("type"), since, until)
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
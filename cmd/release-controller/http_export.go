@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ExportRow is a flattened, point-in-time snapshot of a single release tag,
+// suitable for compliance archival. It intentionally denormalizes fields that
+// elsewhere live in annotations or nested structures, since a compliance
+// consumer wants one row per tag with no further joins.
+type ExportRow struct {
+	Stream              string `json:"stream"`
+	Tag                 string `json:"tag"`
+	Phase               string `json:"phase"`
+	Created             string `json:"created,omitempty"`
+	VerificationSummary string `json:"verificationSummary,omitempty"`
+	Published           bool   `json:"published"`
+}
+
+var exportCSVHeader = []string{"stream", "tag", "phase", "created", "verificationSummary", "published"}
+
+func (r ExportRow) csvRecord() []string {
+	return []string{r.Stream, r.Tag, r.Phase, r.Created, r.VerificationSummary, strconv.FormatBool(r.Published)}
+}
+
+// publishedTagNames returns the set of release tag names currently pointed to
+// by one of release.Config.Publish's TagRef steps, i.e. the tags that are
+// "live" per the stream's configured publish targets.
+func publishedTagNames(release *Release) map[string]bool {
+	published := make(map[string]bool)
+	for _, publish := range release.Config.Publish {
+		if publish.Disabled || publish.TagRef == nil {
+			continue
+		}
+		alias := findTagReference(release.Target, publish.TagRef.Name)
+		if alias == nil || alias.From == nil || alias.From.Kind != "ImageStreamTag" {
+			continue
+		}
+		published[alias.From.Name] = true
+	}
+	return published
+}
+
+// exportRowsForRelease builds one ExportRow per tag known to release, across
+// all phases, so a compliance snapshot can account for rejected and failed
+// tags as well as accepted ones.
+func exportRowsForRelease(release *Release) []ExportRow {
+	published := publishedTagNames(release)
+
+	var rows []ExportRow
+	for i := range release.Target.Spec.Tags {
+		tag := &release.Target.Spec.Tags[i]
+		if len(tag.Annotations[releaseAnnotationSource]) == 0 && len(tag.Annotations[releaseAnnotationPhase]) == 0 {
+			continue
+		}
+
+		row := ExportRow{
+			Stream:    release.Config.Name,
+			Tag:       tag.Name,
+			Phase:     tag.Annotations[releaseAnnotationPhase],
+			Created:   tag.Annotations[releaseAnnotationCreationTimestamp],
+			Published: published[tag.Name],
+		}
+
+		if data := tag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+			var status VerificationStatusMap
+			if err := json.Unmarshal([]byte(data), &status); err == nil {
+				var succeeded, failed, pending int
+				for _, s := range status {
+					switch s.State {
+					case releaseVerificationStateSucceeded:
+						succeeded++
+					case releaseVerificationStateFailed:
+						failed++
+					default:
+						pending++
+					}
+				}
+				summary := fmt.Sprintf("%d succeeded, %d failed", succeeded, failed)
+				if pending > 0 {
+					summary = fmt.Sprintf("%s, %d pending", summary, pending)
+				}
+				row.VerificationSummary = summary
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Tag > rows[j].Tag })
+	return rows
+}
+
+// httpExport serves a flattened, point-in-time export of tag phase,
+// timestamps, verification summary, and publish state across one or more
+// release streams, for periodic compliance archival. streams is a
+// comma-separated list of release stream names; if empty, every stream this
+// controller manages is included. format is "json" (default) or "csv".
+func (c *Controller) httpExport(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	format := req.URL.Query().Get("format")
+	switch format {
+	case "", "json", "csv":
+	default:
+		http.Error(w, "format must be one of '', 'json', or 'csv'", http.StatusBadRequest)
+		return
+	}
+
+	var wanted map[string]bool
+	if streamsParam := req.URL.Query().Get("streams"); len(streamsParam) > 0 {
+		wanted = make(map[string]bool)
+		for _, name := range strings.Split(streamsParam, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				wanted[name] = true
+			}
+		}
+	}
+
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var rows []ExportRow
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if wanted != nil && !wanted[r.Config.Name] {
+			continue
+		}
+		rows = append(rows, exportRowsForRelease(r)...)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Stream != rows[j].Stream {
+			return rows[i].Stream < rows[j].Stream
+		}
+		return rows[i].Tag > rows[j].Tag
+	})
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportCSVHeader); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, row := range rows {
+			if err := cw.Write(row.csvRecord()); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		cw.Flush()
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+		fmt.Fprintln(w)
+	}
+}
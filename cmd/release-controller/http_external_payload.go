@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	corev1 "k8s.io/api/core/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// ExternalPayloadRegistration is the payload posted to httpRegisterExternalPayload
+// to register an externally hosted release image for verification-only processing.
+type ExternalPayloadRegistration struct {
+	// Name is the tag name the payload will be registered under. It must not
+	// already exist in the release stream.
+	Name string `json:"name"`
+	// PullSpec is the pull spec of the release image to verify, hosted outside
+	// any image stream this controller watches.
+	PullSpec string `json:"pullSpec"`
+}
+
+// httpRegisterExternalPayload accepts a signed request to register an arbitrary,
+// externally hosted release pullspec under releaseStreamName for verification-only
+// processing: the registered tag is run through the normal verify/gate machinery
+// and shown on the dashboard, but is never treated as part of the stream's real
+// lineage for promotion or "latest" purposes, since it wasn't built by this
+// controller's own pipeline.
+//
+// Requests must be signed with HMAC-SHA256 over the raw request body, keyed by
+// the secret configured via SetExternalPayloadSecret, hex-encoded in the
+// X-Release-Controller-Signature header.
+func (c *Controller) httpRegisterExternalPayload(w http.ResponseWriter, req *http.Request) {
+	if len(c.externalPayloadSecret) == 0 {
+		http.Error(w, "external payload registration is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	releaseStreamName := mux.Vars(req)["release"]
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !validExternalVerificationSignature(c.externalPayloadSecret, body, req.Header.Get("X-Release-Controller-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var registration ExternalPayloadRegistration
+	if err := json.Unmarshal(body, &registration); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(registration.Name) == 0 || len(registration.PullSpec) == 0 {
+		http.Error(w, "name and pullSpec are required", http.StatusBadRequest)
+		return
+	}
+
+	streamTagMap, ok := c.findReleaseByName(false, releaseStreamName)
+	if !ok || streamTagMap[releaseStreamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streamTagMap[releaseStreamName].Release
+
+	tag, err := c.registerExternalPayload(release, registration.Name, registration.PullSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	data, err := json.MarshalIndent(tag, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+// registerExternalPayload adds pullSpec to release's target image stream under
+// name, already in the Ready phase and marked releaseAnnotationVerificationOnly
+// so the normal verify/gate machinery in syncReady picks it up on the next sync
+// without ever being eligible for promotion.
+func (c *Controller) registerExternalPayload(release *Release, name, pullSpec string) (*imagev1.TagReference, error) {
+	if release.Config.As == releaseConfigModeStable {
+		return nil, fmt.Errorf("release %s is a stable stream and does not accept externally registered payloads", release.Config.Name)
+	}
+	if findTagReference(release.Target, name) != nil {
+		return nil, fmt.Errorf("release tag %s already exists", name)
+	}
+
+	target := release.Target.DeepCopy()
+	now := time.Now().UTC().Truncate(time.Second)
+	tag := imagev1.TagReference{
+		Name: name,
+		From: &corev1.ObjectReference{
+			Kind: "DockerImage",
+			Name: pullSpec,
+		},
+		Annotations: map[string]string{
+			releaseAnnotationName:              release.Config.Name,
+			releaseAnnotationSource:            fmt.Sprintf("%s/%s", release.Source.Namespace, release.Source.Name),
+			releaseAnnotationCreationTimestamp: now.Format(time.RFC3339),
+			releaseAnnotationPhase:             releasePhaseReady,
+			releaseAnnotationVerificationOnly:  "true",
+		},
+	}
+	target.Spec.Tags = append(target.Spec.Tags, tag)
+
+	glog.V(2).Infof("Registering externally hosted payload %s for verification under %s", name, release.Config.Name)
+	is, err := c.imageClient.ImageStreams(target.Namespace).Update(target)
+	if err != nil {
+		return nil, err
+	}
+	updateReleaseTarget(release, is)
+	return findTagReference(release.Target, name), nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExternalVerificationReport is the payload an external CI system posts to report a
+// result for a verification step declared with ReleaseVerification.External.
+type ExternalVerificationReport struct {
+	// State is one of Succeeded or Failed.
+	State string `json:"state"`
+	// URL links to the external system's record of this result (e.g. a Jenkins
+	// build page), shown alongside the result on the dashboard.
+	URL string `json:"url"`
+}
+
+// httpImportExternalVerificationResult accepts a signed webhook from a non-prow CI
+// system reporting the result of a verification step declared with
+// ReleaseVerification.External, and writes it onto the release tag's verify
+// annotation so the normal acceptance evaluation picks it up on the next sync.
+//
+// Requests must be signed with HMAC-SHA256 over the raw request body, keyed by the
+// secret configured via SetExternalVerifySecret, hex-encoded in the
+// X-Release-Controller-Signature header.
+func (c *Controller) httpImportExternalVerificationResult(w http.ResponseWriter, req *http.Request) {
+	if len(c.externalVerifySecret) == 0 {
+		http.Error(w, "external verification reporting is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(req)
+	streamName, tagName, verification := vars["release"], vars["tag"], vars["verification"]
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !validExternalVerificationSignature(c.externalVerifySecret, body, req.Header.Get("X-Release-Controller-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var report ExternalVerificationReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if report.State != releaseVerificationStateSucceeded && report.State != releaseVerificationStateFailed {
+		http.Error(w, fmt.Sprintf("state must be %q or %q", releaseVerificationStateSucceeded, releaseVerificationStateFailed), http.StatusBadRequest)
+		return
+	}
+
+	streamTagMap, ok := c.findReleaseByName(false, streamName)
+	if !ok || streamTagMap[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streamTagMap[streamName].Release
+
+	verifyType, ok := release.Config.Verify[verification]
+	if !ok || verifyType.External == nil {
+		http.Error(w, fmt.Sprintf("release %s has no external verification step named %s", streamName, verification), http.StatusNotFound)
+		return
+	}
+	if findTagReference(release.Target, tagName) == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := c.applyExternalVerificationResult(release, tagName, verification, &VerificationStatus{State: report.State, URL: report.URL}); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validExternalVerificationSignature reports whether signature is a valid
+// hex-encoded HMAC-SHA256 of body using secret, in constant time.
+func validExternalVerificationSignature(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
+
+// applyExternalVerificationResult merges status into the verify annotation of the
+// named release tag, which must currently be in the Ready phase awaiting
+// verification.
+func (c *Controller) applyExternalVerificationResult(release *Release, tagName, verification string, status *VerificationStatus) error {
+	tag := findTagReference(release.Target, tagName)
+	if tag == nil {
+		return fmt.Errorf("release tag %s no longer exists", tagName)
+	}
+
+	var verifyStatus VerificationStatusMap
+	if data := tag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+		verifyStatus = make(VerificationStatusMap)
+		if err := json.Unmarshal([]byte(data), &verifyStatus); err != nil {
+			glog.Errorf("Release %s has invalid verification status, overwriting: %v", tagName, err)
+			verifyStatus = make(VerificationStatusMap)
+		}
+	} else {
+		verifyStatus = make(VerificationStatusMap)
+	}
+	verifyStatus[verification] = status
+
+	return c.setReleaseAnnotation(release, releasePhaseReady, map[string]string{
+		releaseAnnotationVerify: limitAnnotationSize(releaseAnnotationVerify, toJSONString(verifyStatus)),
+	}, tagName)
+}
+
+// importExternalVerificationConfigMap looks for a ConfigMap named
+// "<releaseTag>-verify-<verification>" in the controller's job namespace, with
+// "state" and "url" data keys, and normalizes it into a VerificationStatus. This
+// lets a CI system that can't make signed HTTP calls report results simply by
+// writing a ConfigMap instead.
+func (c *Controller) importExternalVerificationConfigMap(releaseTag, verification string) (*VerificationStatus, bool) {
+	if c.configMapClient == nil {
+		return nil, false
+	}
+	name := fmt.Sprintf("%s-verify-%s", releaseTag, verification)
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			glog.V(4).Infof("Unable to check for external verification ConfigMap %s/%s: %v", c.jobNamespace, name, err)
+		}
+		return nil, false
+	}
+	state := cm.Data["state"]
+	if state != releaseVerificationStateSucceeded && state != releaseVerificationStateFailed {
+		glog.Warningf("ConfigMap %s/%s has invalid state %q for external verification %s, ignoring", c.jobNamespace, name, state, verification)
+		return nil, false
+	}
+	return &VerificationStatus{State: state, URL: cm.Data["url"]}, true
+}
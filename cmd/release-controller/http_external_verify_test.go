@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestValidExternalVerificationSignature(t *testing.T) {
+	secret := "s3cr3t"
+	body := []byte(`{"state":"Succeeded","url":"http://example.com/build/1"}`)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	validSignature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid signature", secret: secret, body: body, signature: validSignature, want: true},
+		{name: "wrong secret", secret: "other-secret", body: body, signature: validSignature, want: false},
+		{name: "tampered body", secret: secret, body: []byte(`{"state":"Failed","url":"http://example.com/build/1"}`), signature: validSignature, want: false},
+		{name: "not hex", secret: secret, body: body, signature: "not-hex!!", want: false},
+		{name: "empty signature", secret: secret, body: body, signature: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validExternalVerificationSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("validExternalVerificationSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportExternalVerificationConfigMap_NilClient(t *testing.T) {
+	c := &Controller{}
+	status, ok := c.importExternalVerificationConfigMap("4.1.0", "e2e")
+	if ok || status != nil {
+		t.Errorf("importExternalVerificationConfigMap() = (%v, %v), want (nil, false) when no configmap client is configured", status, ok)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// familyPrefixPattern extracts the "major.minor" z-stream family a release
+// stream belongs to from its Config.Name - e.g. "4.10" from both the stable
+// stream "4.10" and the integration streams "4.10.0-0.ci" and
+// "4.10.0-0.nightly".
+var familyPrefixPattern = regexp.MustCompile(`^(\d+\.\d+)`)
+
+// familyOf returns the z-stream family a release stream name belongs to, and
+// whether it could be determined at all (names that don't start with a
+// version, e.g. a hand-rolled test stream, have none).
+func familyOf(streamName string) (string, bool) {
+	m := familyPrefixPattern.FindStringSubmatch(streamName)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// FamilyOverview aggregates the CI, nightly, and stable streams for a single
+// z-stream family (e.g. "4.10") so a consumer can answer "where is 4.10 right
+// now" without stitching together three separate stream pages by hand.
+type FamilyOverview struct {
+	Family string `json:"family"`
+	// CI is the integration stream that builds directly from component
+	// repositories, if this family has one.
+	CI *StreamOverview `json:"ci,omitempty"`
+	// Nightly is the integration stream that assembles CI output into a
+	// payload, if this family has one.
+	Nightly *StreamOverview `json:"nightly,omitempty"`
+	// TopCandidate is the best-ranked accepted nightly tag not yet promoted to
+	// Stable, per findReleaseCandidates, if any.
+	TopCandidate *ReleaseCandidate `json:"topCandidate,omitempty"`
+	// Stable is this family's published stream, if it has shipped one.
+	Stable *StreamOverview `json:"stable,omitempty"`
+}
+
+// buildFamilyOverview scans every release stream for ones belonging to
+// family and assembles a FamilyOverview from them. A stream is classified as
+// CI or Nightly by its name containing ".ci" or ".nightly" respectively,
+// since Config.As does not otherwise distinguish the two kinds of
+// integration stream.
+func (c *Controller) buildFamilyOverview(family string) (*FamilyOverview, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &FamilyOverview{Family: family}
+	now := time.Now()
+	var nightlyStream string
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if f, ok := familyOf(r.Config.Name); !ok || f != family {
+			continue
+		}
+
+		entry := c.streamOverviewEntry(r, now)
+		switch {
+		case r.Config.As == releaseConfigModeStable:
+			overview.Stable = &entry
+		case nightlyPattern.MatchString(r.Config.Name):
+			overview.Nightly = &entry
+			nightlyStream = r.Config.Name
+		case ciPattern.MatchString(r.Config.Name):
+			overview.CI = &entry
+		}
+	}
+
+	if len(nightlyStream) > 0 {
+		candidates, err := c.findReleaseCandidates(80.0, nightlyStream)
+		if err == nil && candidates[nightlyStream] != nil && len(candidates[nightlyStream].Items) > 0 {
+			overview.TopCandidate = candidates[nightlyStream].Items[0]
+		}
+	}
+
+	return overview, nil
+}
+
+var (
+	nightlyPattern = regexp.MustCompile(`\.nightly\b`)
+	ciPattern      = regexp.MustCompile(`\.ci\b`)
+)
+
+const familyPageHtml = `
+<h1>{{ .Family }} family</h1>
+<hr>
+<div class="row">
+<div class="col">
+<table class="table text-nowrap">
+<thead><tr><th>Stream</th><th>Kind</th><th>Health</th><th>Latest</th></tr></thead>
+<tbody>
+{{ with .CI }}<tr><td><a href="{{ .Link }}">{{ .Name }}</a></td><td>CI</td><td>{{ .Health }}</td><td>{{ .Latest }}</td></tr>{{ end }}
+{{ with .Nightly }}<tr><td><a href="{{ .Link }}">{{ .Name }}</a></td><td>Nightly</td><td>{{ .Health }}</td><td>{{ .Latest }}</td></tr>{{ end }}
+{{ with .Stable }}<tr><td><a href="{{ .Link }}">{{ .Name }}</a></td><td>Stable</td><td>{{ .Health }}</td><td>{{ .Latest }}</td></tr>{{ end }}
+</tbody>
+</table>
+{{ with .TopCandidate }}<p>Top candidate for promotion: <a href="/releasetag/{{ .FromTag }}">{{ .FromTag }}</a></p>{{ end }}
+</div>
+</div>
+`
+
+// httpFamily serves FamilyOverview as HTML by default, or as JSON with
+// ?format=json, following the same convention as httpReleaseCandidateList.
+func (c *Controller) httpFamily(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	family := vars["family"]
+	if _, ok := familyOf(family); !ok {
+		http.Error(w, fmt.Sprintf("%q is not a valid z-stream family, expected e.g. \"4.10\"", family), http.StatusBadRequest)
+		return
+	}
+
+	overview, err := c.buildFamilyOverview(family)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch req.URL.Query().Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		data, err := json.MarshalIndent(overview, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	default:
+		fmt.Fprintf(w, htmlPageStart, fmt.Sprintf("%s family", family))
+		page := template.Must(template.New("familyPage").Parse(familyPageHtml))
+		if err := page.Execute(w, overview); err != nil {
+			fmt.Fprintf(w, "unable to render page: %v", err)
+		}
+		fmt.Fprintln(w, htmlPageEnd)
+	}
+}
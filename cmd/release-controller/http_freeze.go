@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+)
+
+// FreezeRequest is the body of a POST to /api/v1/admin/freeze/{minor}.
+type FreezeRequest struct {
+	// Reason is shown on every stream page for a tag in the frozen minor
+	// version family, and recorded in the freeze listing.
+	Reason string `json:"reason"`
+}
+
+// FreezeEntry describes one frozen minor version family, for the admin
+// freeze listing endpoint.
+type FreezeEntry struct {
+	MinorVersion string `json:"minorVersion"`
+	Reason       string `json:"reason"`
+}
+
+// httpListFreezes reports every currently frozen minor version family.
+func (c *Controller) httpListFreezes(w http.ResponseWriter, req *http.Request) {
+	freezes, err := c.activeFreezes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries := make([]FreezeEntry, 0, len(freezes))
+	for minorVersion, reason := range freezes {
+		entries = append(entries, FreezeEntry{MinorVersion: minorVersion, Reason: reason})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].MinorVersion < entries[j].MinorVersion })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// httpSetFreeze freezes minorVersion (e.g. "4.15"): new stable promotions and
+// tagRef publish steps for that version family are held, with req.Reason
+// shown on affected stream pages, until a matching DELETE clears it.
+func (c *Controller) httpSetFreeze(w http.ResponseWriter, req *http.Request) {
+	minorVersion := mux.Vars(req)["minor"]
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request FreezeRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(request.Reason) == 0 {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.setMinorVersionFreeze(minorVersion, request.Reason); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpClearFreeze lifts a freeze previously set by httpSetFreeze.
+func (c *Controller) httpClearFreeze(w http.ResponseWriter, req *http.Request) {
+	minorVersion := mux.Vars(req)["minor"]
+
+	if err := c.clearMinorVersionFreeze(minorVersion); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
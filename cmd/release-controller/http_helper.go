@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/blang/semver"
 
@@ -39,6 +40,8 @@ type LatestAccepted struct {
 	Name        string `json:"name"`
 	PullSpec    string `json:"pullSpec"`
 	DownloadURL string `json:"downloadURL"`
+	// KnownIssues lists caveats recorded against this tag, if any. See KnownIssue.
+	KnownIssues []KnownIssue `json:"knownIssues,omitempty"`
 }
 
 type ReleaseStreamTag struct {
@@ -77,10 +80,10 @@ type StableRelease struct {
 
 type SemanticVersions []SemanticVersion
 
-func NewSemanticVersions(tags []*imagev1.TagReference) SemanticVersions {
+func NewSemanticVersions(scheme string, tags []*imagev1.TagReference) SemanticVersions {
 	v := make(SemanticVersions, 0, len(tags))
 	for _, tag := range tags {
-		if version, err := semver.Parse(tag.Name); err == nil {
+		if version, err := parseStreamVersion(scheme, tag.Name); err == nil {
 			v = append(v, SemanticVersion{Version: &version, Tag: tag})
 		} else {
 			v = append(v, SemanticVersion{Tag: tag})
@@ -277,6 +280,10 @@ func links(tag imagev1.TagReference, release *Release) string {
 					buf.WriteString(" <a title=\"Failed\" class=\"text-danger\" href=\"")
 				case releaseVerificationStateSucceeded:
 					buf.WriteString(" <a title=\"Succeeded\" class=\"text-success\" href=\"")
+				case releaseVerificationStateErrored:
+					buf.WriteString(" <a title=\"Errored (retrying)\" class=\"text-warning\" href=\"")
+				case releaseVerificationStateAborted:
+					buf.WriteString(" <a title=\"Aborted (retrying)\" class=\"text-warning\" href=\"")
 				default:
 					buf.WriteString(" <a title=\"Pending\" class=\"\" href=\"")
 				}
@@ -291,6 +298,12 @@ func links(tag imagev1.TagReference, release *Release) string {
 				buf.WriteString(" <span title=\"Failed\" class=\"text-danger\">")
 			case releaseVerificationStateSucceeded:
 				buf.WriteString(" <span title=\"Succeeded\" class=\"text-success\">")
+			case releaseVerificationStateBlocked:
+				buf.WriteString(" <span title=\"Waiting for resource\" class=\"text-muted\">")
+			case releaseVerificationStateErrored:
+				buf.WriteString(" <span title=\"Errored (retrying)\" class=\"text-warning\">")
+			case releaseVerificationStateAborted:
+				buf.WriteString(" <span title=\"Aborted (retrying)\" class=\"text-warning\">")
 			default:
 				buf.WriteString(" <span title=\"Pending\" class=\"\">")
 			}
@@ -308,7 +321,7 @@ func links(tag imagev1.TagReference, release *Release) string {
 	return buf.String()
 }
 
-func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release) {
+func (c *Controller) renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release, olderTags []*imagev1.TagReference) {
 	links := tag.Annotations[releaseAnnotationVerify]
 	if len(links) == 0 {
 		fmt.Fprintf(w, `<p><em>No tests for this release</em>`)
@@ -335,6 +348,8 @@ func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release)
 					buf.WriteString("<li><a class=\"text-danger\" href=\"")
 				case releaseVerificationStateSucceeded:
 					buf.WriteString("<li><a class=\"text-success\" href=\"")
+				case releaseVerificationStateErrored, releaseVerificationStateAborted:
+					buf.WriteString("<li><a class=\"text-warning\" href=\"")
 				default:
 					buf.WriteString("<li><a class=\"\" href=\"")
 				}
@@ -346,6 +361,10 @@ func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release)
 					buf.WriteString(" Failed")
 				case releaseVerificationStateSucceeded:
 					buf.WriteString(" Succeeded")
+				case releaseVerificationStateErrored:
+					buf.WriteString(" Errored (retrying)")
+				case releaseVerificationStateAborted:
+					buf.WriteString(" Aborted (retrying)")
 				default:
 					buf.WriteString(" Pending")
 				}
@@ -354,6 +373,13 @@ func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release)
 					buf.WriteString(" ")
 					buf.WriteString(pj.Name)
 				}
+				renderVerificationPassRate(buf, olderTags, key)
+				if s.State == releaseVerificationStateFailed {
+					c.renderBuildLogTail(buf, s.URL)
+					if n := consecutiveMatchingFailureFingerprints(olderTags, key, s.Fingerprint); n > 0 {
+						fmt.Fprintf(buf, ` <span class="text-muted">(same failure as previous %d tags)</span>`, n)
+					}
+				}
 				continue
 			}
 			switch s.State {
@@ -361,6 +387,10 @@ func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release)
 				buf.WriteString("<li><span class=\"text-danger\">")
 			case releaseVerificationStateSucceeded:
 				buf.WriteString("<li><span class=\"text-success\">")
+			case releaseVerificationStateBlocked:
+				buf.WriteString("<li><span class=\"text-muted\">")
+			case releaseVerificationStateErrored, releaseVerificationStateAborted:
+				buf.WriteString("<li><span class=\"text-warning\">")
 			default:
 				buf.WriteString("<li><span class=\"\">")
 			}
@@ -370,14 +400,26 @@ func renderVerifyLinks(w io.Writer, tag imagev1.TagReference, release *Release)
 				buf.WriteString(" Failed")
 			case releaseVerificationStateSucceeded:
 				buf.WriteString(" Succeeded")
+			case releaseVerificationStateBlocked:
+				buf.WriteString(" Waiting for resource")
+			case releaseVerificationStateErrored:
+				buf.WriteString(" Errored (retrying)")
+			case releaseVerificationStateAborted:
+				buf.WriteString(" Aborted (retrying)")
 			default:
 				buf.WriteString(" Pending")
 			}
 			buf.WriteString("</span>")
+			if len(s.Message) > 0 {
+				buf.WriteString(" <span class=\"text-muted\">(")
+				buf.WriteString(template.HTMLEscapeString(s.Message))
+				buf.WriteString(")</span>")
+			}
 			if pj := release.Config.Verify[key].ProwJob; pj != nil {
 				buf.WriteString(" ")
 				buf.WriteString(pj.Name)
 			}
+			renderVerificationPassRate(buf, olderTags, key)
 			continue
 		}
 		final := tag.Annotations[releaseAnnotationPhase] == releasePhaseRejected || tag.Annotations[releaseAnnotationPhase] == releasePhaseAccepted
@@ -686,21 +728,57 @@ func (s newestSemVerToSummaries) Swap(i, j int) {
 }
 func (s newestSemVerToSummaries) Len() int { return len(s.summaries) }
 
-func renderInstallInstructions(w io.Writer, mirror *imagev1.ImageStream, tag *imagev1.TagReference, tagPull, artifactsHost string) {
+func renderInstallInstructions(w io.Writer, mirror *imagev1.ImageStream, tag *imagev1.TagReference, tagPull, downloadURL string) {
 	if len(tagPull) == 0 {
 		fmt.Fprintf(w, `<p class="alert alert-warning">No public location to pull this image from</p>`)
 		return
 	}
-	if len(artifactsHost) == 0 {
+	if len(downloadURL) == 0 {
 		fmt.Fprintf(w, `<p>Download installer and client with:<pre class="ml-4">oc adm release extract --tools %s</pre>`, template.HTMLEscapeString(tagPull))
 		return
 	}
-	fmt.Fprintf(w, `<p><a href="%s">Download the installer</a> for your operating system or run <pre class="ml-4">oc adm release extract --tools %s</pre>`, template.HTMLEscapeString(fmt.Sprintf("https://%s/%s", artifactsHost, tag.Name)), template.HTMLEscapeString(tagPull))
+	fmt.Fprintf(w, `<p><a href="%s">Download the installer</a> for your operating system or run <pre class="ml-4">oc adm release extract --tools %s</pre>`, template.HTMLEscapeString(downloadURL), template.HTMLEscapeString(tagPull))
+}
+
+// releaseFreshnessBreach reports whether release has an ExpectAcceptedEvery SLA
+// configured and, if so, whether the most recent Accepted tag is older than that
+// window. age is the time since the most recent Accepted tag, or zero if there has
+// never been one. accepted is expected to be sorted newest-first, as returned by
+// tagsForRelease(release, releasePhaseAccepted).
+func releaseFreshnessBreach(release *Release, accepted []*imagev1.TagReference, now time.Time) (configured, breached bool, age time.Duration) {
+	sla := release.Config.ExpectAcceptedEvery.Duration()
+	if sla <= 0 {
+		return false, false, 0
+	}
+	if len(accepted) == 0 {
+		return true, true, 0
+	}
+	created, err := time.Parse(time.RFC3339, accepted[0].Annotations[releaseAnnotationCreationTimestamp])
+	if err != nil {
+		return true, true, 0
+	}
+	age = now.Sub(created)
+	return true, age > sla, age
 }
 
 func checkReleasePage(page *ReleasePage) {
+	now := time.Now()
 	for i := range page.Streams {
 		stream := &page.Streams[i]
+		if configured, breached, age := releaseFreshnessBreach(stream.Release, tagsForRelease(stream.Release, releasePhaseAccepted), now); configured && breached {
+			sla := stream.Release.Config.ExpectAcceptedEvery.Duration()
+			if age == 0 {
+				stream.Checks = append(stream.Checks, ReleaseCheckResult{
+					Name:   "freshness",
+					Errors: []string{fmt.Sprintf("No Accepted release yet; SLA requires one every %s.", sla)},
+				})
+			} else {
+				stream.Checks = append(stream.Checks, ReleaseCheckResult{
+					Name:   "freshness",
+					Errors: []string{fmt.Sprintf("Last Accepted release is %s old, which exceeds the %s SLA.", age.Round(time.Minute), sla)},
+				})
+			}
+		}
 		for name, check := range stream.Release.Config.Check {
 			switch {
 			case check.ConsistentImages != nil:
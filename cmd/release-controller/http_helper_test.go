@@ -209,7 +209,7 @@ func TestSemanticVersions_Tags(t *testing.T) {
 		want []*imagev1.TagReference
 	}{
 		{
-			v: NewSemanticVersions([]*imagev1.TagReference{
+			v: NewSemanticVersions("", []*imagev1.TagReference{
 				{Name: "4.0.0"}, {Name: "4.0.1"}, {Name: "4.0.0-2"}, {Name: "4.0.0-1-a"},
 			}),
 			want: []*imagev1.TagReference{
@@ -217,7 +217,7 @@ func TestSemanticVersions_Tags(t *testing.T) {
 			},
 		},
 		{
-			v: NewSemanticVersions([]*imagev1.TagReference{
+			v: NewSemanticVersions("", []*imagev1.TagReference{
 				{Name: "4.0.0-0.9"}, {Name: "4.0.0-0.2"}, {Name: "4.0.0-0.2.a"},
 			}),
 			want: []*imagev1.TagReference{
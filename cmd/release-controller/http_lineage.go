@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PromotionLink identifies a single tag in a specific release stream.
+type PromotionLink struct {
+	Stream string `json:"stream"`
+	Tag    string `json:"tag"`
+}
+
+// PromotionLineage describes where a release tag came from and what happened to
+// it next within its own stream, stitching together annotations that otherwise
+// require several manual lookups to connect.
+type PromotionLineage struct {
+	Stream string `json:"stream"`
+	Tag    string `json:"tag"`
+
+	// PromotedFrom is set for stable tags: the integration stream tag this release
+	// was built from.
+	PromotedFrom *PromotionLink `json:"promotedFrom,omitempty"`
+
+	// Supersedes is the previously accepted tag in this stream, if any.
+	Supersedes *PromotionLink `json:"supersedes,omitempty"`
+	// SupersededBy is the next accepted tag in this stream, if any.
+	SupersededBy *PromotionLink `json:"supersededBy,omitempty"`
+}
+
+// httpLineage reports the promotion lineage of a single tag: the nightly it was
+// promoted from (if any) and the accepted tags immediately before and after it in
+// its own stream.
+func (c *Controller) httpLineage(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName := vars["release"]
+	tagName := vars["tag"]
+
+	streamTagMap, ok := c.findReleaseByName(false, streamName)
+	if !ok || streamTagMap[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streamTagMap[streamName].Release
+
+	releaseTags := tagsForRelease(release)
+	tagIndex := -1
+	for i, t := range releaseTags {
+		if t.Name == tagName {
+			tagIndex = i
+			break
+		}
+	}
+	if tagIndex == -1 {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	tag := releaseTags[tagIndex]
+
+	lineage := &PromotionLineage{Stream: streamName, Tag: tagName}
+
+	if release.Config.As == releaseConfigModeStable {
+		if fromTag, err := c.tagPromotedFrom(tag); err == nil && fromTag != nil {
+			lineage.PromotedFrom = &PromotionLink{Stream: fromTag.Annotations[releaseAnnotationName], Tag: fromTag.Name}
+		}
+	}
+
+	if prev := findPreviousRelease(tag, releaseTags[tagIndex+1:], release); prev != nil {
+		lineage.Supersedes = &PromotionLink{Stream: streamName, Tag: prev.Name}
+	}
+	for i := tagIndex - 1; i >= 0; i-- {
+		if releaseTags[i].Annotations[releaseAnnotationPhase] == releasePhaseAccepted {
+			lineage.SupersededBy = &PromotionLink{Stream: streamName, Tag: releaseTags[i].Name}
+			break
+		}
+	}
+
+	data, err := json.MarshalIndent(lineage, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
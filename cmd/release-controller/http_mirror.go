@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// MirrorInfo is a flattened, point-in-time summary of a single release mirror
+// image stream, so the otherwise opaque mirror namespace can be audited without
+// requiring direct access to the release namespace.
+type MirrorInfo struct {
+	Stream     string    `json:"stream"`
+	Tag        string    `json:"tag"`
+	Mirror     string    `json:"mirror"`
+	Bytes      int64     `json:"bytes"`
+	Tags       int       `json:"tags"`
+	Created    time.Time `json:"created"`
+	AgeSeconds int64     `json:"ageSeconds"`
+	TTLSeconds int64     `json:"ttlSeconds,omitempty"`
+}
+
+// mirrorSize sums the distinct image layers referenced by mirror's status tags,
+// following the same dedup-by-digest approach as computeStorageReport, since a
+// mirror image stream is populated and inspected the same way a release stream is.
+func (c *Controller) mirrorSize(mirror *imagev1.ImageStream) (int64, int, error) {
+	layerSizes := make(map[string]int64)
+	seenDigests := make(map[string]bool)
+	tagCount := 0
+	for i := range mirror.Status.Tags {
+		tagStatus := &mirror.Status.Tags[i]
+		if len(tagStatus.Items) == 0 {
+			continue
+		}
+		digest := tagStatus.Items[0].Image
+		if len(digest) == 0 || seenDigests[digest] {
+			continue
+		}
+		seenDigests[digest] = true
+		tagCount++
+
+		image, err := c.imageClient.Images().Get(digest, metav1.GetOptions{})
+		if err != nil {
+			glog.V(5).Infof("Unable to get image %s for mirror size of %s: %v", digest, mirror.Name, err)
+			continue
+		}
+		for _, layer := range image.DockerImageLayers {
+			layerSizes[layer.Name] = layer.LayerSize
+		}
+	}
+	var bytes int64
+	for _, size := range layerSizes {
+		bytes += size
+	}
+	return bytes, tagCount, nil
+}
+
+// httpMirrors lists every release mirror image stream this controller currently
+// manages, with its size and age, across one or more release streams. streams is
+// a comma-separated list of release stream names; if empty, every stream this
+// controller manages is included.
+func (c *Controller) httpMirrors(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	var wanted map[string]bool
+	if streamsParam := req.URL.Query().Get("streams"); len(streamsParam) > 0 {
+		wanted = make(map[string]bool)
+		for _, name := range strings.Split(streamsParam, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				wanted[name] = true
+			}
+		}
+	}
+
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var rows []MirrorInfo
+	for _, stream := range imageStreams {
+		release, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if wanted != nil && !wanted[release.Config.Name] {
+			continue
+		}
+		ttl := release.Config.MirrorTTL.Duration()
+		for i := range release.Target.Spec.Tags {
+			tagName := release.Target.Spec.Tags[i].Name
+			mirror, err := c.getMirror(release, tagName)
+			if err != nil {
+				continue
+			}
+			bytes, tagCount, err := c.mirrorSize(mirror)
+			if err != nil {
+				continue
+			}
+			row := MirrorInfo{
+				Stream:     release.Config.Name,
+				Tag:        tagName,
+				Mirror:     mirror.Name,
+				Bytes:      bytes,
+				Tags:       tagCount,
+				Created:    mirror.CreationTimestamp.Time,
+				AgeSeconds: int64(time.Since(mirror.CreationTimestamp.Time).Seconds()),
+			}
+			if ttl > 0 {
+				row.TTLSeconds = int64(ttl.Seconds())
+			}
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Stream != rows[j].Stream {
+			return rows[i].Stream < rows[j].Stream
+		}
+		return rows[i].Tag > rows[j].Tag
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+	fmt.Fprintln(w)
+}
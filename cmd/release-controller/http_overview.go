@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// overviewCacheTTL controls how long the rendered /api/v1/overview response is
+// reused before being recomputed. The endpoint is meant for wallboards that poll
+// frequently, so avoiding repeated full scans of every stream matters more than
+// strict freshness.
+const overviewCacheTTL = 30 * time.Second
+
+// creationBlockedLookback bounds how far back renderOverview looks for an
+// UnableToCreateRelease event before considering a stream's CreationBlocked
+// condition to have cleared. It's wide enough to span a few sync retries
+// without making a stream look blocked long after the underlying error was
+// resolved.
+const creationBlockedLookback = 2 * time.Hour
+
+// inputsStaleAfter is how long a release stream's input image stream can go
+// without a new tag being pushed before InputsStale is reported. This is a
+// fixed, repo-wide heuristic rather than a per-stream config value - streams
+// with a legitimately slower cadence will see occasional false positives,
+// which is an acceptable tradeoff for a condition meant to catch inputs that
+// have gone silent unexpectedly.
+const inputsStaleAfter = 7 * 24 * time.Hour
+
+// StreamConditionType is a well-known, stable condition name surfaced on
+// StreamOverview.Conditions. See StreamCondition.
+type StreamConditionType string
+
+const (
+	// StreamConditionCreationBlocked is True when the controller's most recent
+	// attempt to create a new release tag for this stream failed.
+	StreamConditionCreationBlocked StreamConditionType = "CreationBlocked"
+	// StreamConditionInputsStale is True when this stream's input image stream
+	// has gone longer than inputsStaleAfter without a new tag.
+	StreamConditionInputsStale StreamConditionType = "InputsStale"
+	// StreamConditionSLABreached is True when this stream has a configured
+	// ExpectAcceptedEvery SLA and the most recent Accepted tag is older than it
+	// (or there has never been one).
+	StreamConditionSLABreached StreamConditionType = "SLABreached"
+	// StreamConditionPaused is True when ReleaseConfig.Paused is set.
+	StreamConditionPaused StreamConditionType = "Paused"
+	// StreamConditionPublishFailing is True when this stream has a publish step
+	// whose most recent attempt failed and hasn't since succeeded.
+	StreamConditionPublishFailing StreamConditionType = "PublishFailing"
+)
+
+// StreamCondition is a typed, machine-readable observation about a release
+// stream's current state, modeled on the Kubernetes condition convention so
+// automation that already knows how to reason about conditions (Type/Status/
+// Reason/Message) doesn't need stream-specific parsing logic. Every condition
+// type listed above is always present in StreamOverview.Conditions, with
+// Status "False" when it doesn't apply, so a consumer can distinguish
+// "checked and fine" from "this controller version doesn't report that yet".
+type StreamCondition struct {
+	Type   StreamConditionType `json:"type"`
+	Status string              `json:"status"` // "True" or "False"
+	Reason string              `json:"reason,omitempty"`
+	// Message is a human-readable detail for the condition's current status.
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the time of the underlying observation backing this
+	// condition (e.g. the triggering event, or the most recent Accepted tag),
+	// when one is available. It is left zero where no such timestamp exists,
+	// rather than being stamped with the time of this computation, since these
+	// conditions are derived live from current state and not tracked across
+	// syncs, so "now" would not actually reflect when the status last changed.
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// StreamOverview is a condensed, single-row-per-stream summary intended for status
+// boards that don't want to scrape and re-reduce the full dashboard HTML.
+type StreamOverview struct {
+	Name    string `json:"name"`
+	Health  string `json:"health"` // one of "green", "yellow", "red", "unknown"
+	Latest  string `json:"latest,omitempty"`
+	AgeSecs int64  `json:"ageSeconds,omitempty"`
+	Link    string `json:"link"`
+	// Owners, if configured, identifies who to page when this stream is unhealthy.
+	Owners *ReleaseOwners `json:"owners,omitempty"`
+	// Conditions reports this stream's state as typed conditions, so automation
+	// can reason about it without parsing the HTML dashboard or scattered
+	// annotations. See StreamCondition.
+	Conditions []StreamCondition `json:"conditions"`
+}
+
+type overviewCache struct {
+	lock     sync.Mutex
+	expires  time.Time
+	response []byte
+}
+
+func (c *Controller) renderOverview() ([]byte, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var overview []StreamOverview
+	now := time.Now()
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		overview = append(overview, c.streamOverviewEntry(r, now))
+	}
+
+	return json.MarshalIndent(overview, "", "  ")
+}
+
+// streamOverviewEntry computes r's single StreamOverview row as of now. It's
+// shared by renderOverview, which builds one for every stream, and
+// buildFamilyOverview, which builds one for each stream in a single z-stream
+// family.
+func (c *Controller) streamOverviewEntry(r *Release, now time.Time) StreamOverview {
+	entry := StreamOverview{
+		Name:   r.Config.Name,
+		Health: "unknown",
+		Link:   fmt.Sprintf("/releasestream/%s", r.Config.Name),
+		Owners: r.Config.Owners,
+	}
+
+	if latest := tagsForRelease(r, releasePhaseAccepted); len(latest) > 0 {
+		entry.Latest = latest[0].Name
+		entry.Health = "green"
+		if ts, err := time.Parse(time.RFC3339, latest[0].Annotations[releaseAnnotationCreationTimestamp]); err == nil {
+			entry.AgeSecs = int64(now.Sub(ts).Seconds())
+		}
+	}
+	if recent := tagsForRelease(r); len(recent) > 0 {
+		switch recent[0].Annotations[releaseAnnotationPhase] {
+		case releasePhaseRejected, releasePhaseFailed:
+			entry.Health = "red"
+		case releasePhasePending, releasePhaseReady:
+			if entry.Health != "green" {
+				entry.Health = "yellow"
+			}
+		}
+	}
+	entry.Conditions = c.streamConditions(r, now)
+	return entry
+}
+
+// streamConditions computes r's typed condition set for the overview API. See
+// StreamConditionType for what each condition means; every type is always
+// returned so a consumer never has to distinguish a missing condition from a
+// known-false one.
+func (c *Controller) streamConditions(r *Release, now time.Time) []StreamCondition {
+	conditions := make([]StreamCondition, 0, 5)
+
+	var blockedAt time.Time
+	var blockedMessage string
+	for _, evt := range c.eventHistory.list(r.Source.Name, "Warning", now.Add(-creationBlockedLookback), time.Time{}) {
+		if evt.Reason == "UnableToCreateRelease" {
+			blockedAt, blockedMessage = evt.Time, evt.Message
+			break
+		}
+	}
+	conditions = append(conditions, boolCondition(StreamConditionCreationBlocked, !blockedAt.IsZero(), "UnableToCreateRelease", blockedMessage, blockedAt))
+
+	// Default to stale only when there's at least one input tag to judge; a
+	// brand new stream with no tags yet hasn't gone stale, it just hasn't
+	// started.
+	stale := len(r.Source.Status.Tags) > 0
+	for _, tag := range r.Source.Status.Tags {
+		if len(tag.Items) == 0 {
+			continue
+		}
+		if now.Sub(tag.Items[0].Created.Time) <= inputsStaleAfter {
+			stale = false
+			break
+		}
+	}
+	var staleMessage string
+	if stale {
+		staleMessage = fmt.Sprintf("No input tag has been updated in at least %s", inputsStaleAfter)
+	}
+	conditions = append(conditions, boolCondition(StreamConditionInputsStale, stale, "", staleMessage, time.Time{}))
+
+	configured, breached, age := releaseFreshnessBreach(r, tagsForRelease(r, releasePhaseAccepted), now)
+	var slaMessage string
+	var slaAt time.Time
+	if configured && breached {
+		if age == 0 {
+			slaMessage = fmt.Sprintf("No Accepted release yet; SLA requires one every %s.", r.Config.ExpectAcceptedEvery.Duration())
+		} else {
+			slaMessage = fmt.Sprintf("Last Accepted release is %s old, which exceeds the %s SLA.", age.Round(time.Minute), r.Config.ExpectAcceptedEvery.Duration())
+			slaAt = now.Add(-age)
+		}
+	}
+	conditions = append(conditions, boolCondition(StreamConditionSLABreached, configured && breached, "", slaMessage, slaAt))
+
+	var pausedMessage string
+	if r.Config.Paused {
+		pausedMessage = "Stream is paused; no new release tags will be created."
+	}
+	conditions = append(conditions, boolCondition(StreamConditionPaused, r.Config.Paused, "", pausedMessage, time.Time{}))
+
+	failing := c.publishHistory.hasFailing(r.Config.Name)
+	var failingMessage string
+	if failing {
+		failingMessage = "At least one publish step's most recent attempt failed and has not since succeeded."
+	}
+	conditions = append(conditions, boolCondition(StreamConditionPublishFailing, failing, "", failingMessage, time.Time{}))
+
+	return conditions
+}
+
+func boolCondition(t StreamConditionType, value bool, reason, message string, at time.Time) StreamCondition {
+	status := "False"
+	if value {
+		status = "True"
+	}
+	return StreamCondition{Type: t, Status: status, Reason: reason, Message: message, LastTransitionTime: at}
+}
+
+// apiOverview serves a condensed per-stream traffic-light summary suitable for
+// mobile clients and wallboards, cached for overviewCacheTTL to keep repeated
+// polling cheap.
+func (c *Controller) apiOverview(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	c.overviewCache.lock.Lock()
+	defer c.overviewCache.lock.Unlock()
+
+	if time.Now().After(c.overviewCache.expires) {
+		data, err := c.renderOverview()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.overviewCache.response = data
+		c.overviewCache.expires = time.Now().Add(overviewCacheTTL)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(overviewCacheTTL.Seconds())))
+	w.Write(c.overviewCache.response)
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"hash/fnv"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// pageCacheShards is the number of independent LRU shards htmlPageCache
+// splits its keys across, so rendering one hot page doesn't serialize cache
+// lookups for every other page behind a single lock.
+const pageCacheShards = 16
+
+// pageCacheShardSize bounds how many rendered pages each shard retains.
+const pageCacheShardSize = 128
+
+// htmlPageCache is a sharded LRU cache of fully rendered dashboard and
+// release tag HTML page bodies. Callers key each entry with a string that
+// embeds the resourceVersion of the image streams they choose to track, so
+// an update to one of those streams invalidates its pages simply by changing
+// the key; there is
+// no explicit eviction, stale entries just age out of their shard's LRU.
+type htmlPageCache struct {
+	shards [pageCacheShards]*lru.Cache
+
+	// inFlight coalesces concurrent cache misses for the same key into a
+	// single render, so a hot page being refreshed by many clients at once
+	// (the incident scenario this cache exists for) doesn't re-render once
+	// per request.
+	inFlightLock sync.Mutex
+	inFlight     map[string]*pageRender
+}
+
+// pageRender is the shared result of one in-progress render, handed to every
+// caller that asked for the same cache key while it was running.
+type pageRender struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+func newHTMLPageCache() (*htmlPageCache, error) {
+	c := &htmlPageCache{inFlight: make(map[string]*pageRender)}
+	for i := range c.shards {
+		shard, err := lru.New(pageCacheShardSize)
+		if err != nil {
+			return nil, err
+		}
+		c.shards[i] = shard
+	}
+	return c, nil
+}
+
+func (c *htmlPageCache) shardFor(key string) *lru.Cache {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%pageCacheShards]
+}
+
+// renderOrGet returns the cached body for key if present, otherwise calls
+// render exactly once on behalf of every caller currently waiting on key and
+// caches its result.
+func (c *htmlPageCache) renderOrGet(key string, render func() ([]byte, error)) ([]byte, error) {
+	shard := c.shardFor(key)
+	if cached, ok := shard.Get(key); ok {
+		htmlPageCacheTotal.WithLabelValues("hit").Inc()
+		return cached.([]byte), nil
+	}
+
+	c.inFlightLock.Lock()
+	if existing, ok := c.inFlight[key]; ok {
+		c.inFlightLock.Unlock()
+		<-existing.done
+		return existing.body, existing.err
+	}
+	r := &pageRender{done: make(chan struct{})}
+	c.inFlight[key] = r
+	c.inFlightLock.Unlock()
+
+	htmlPageCacheTotal.WithLabelValues("miss").Inc()
+	r.body, r.err = render()
+	if r.err == nil {
+		shard.Add(key, r.body)
+	}
+	close(r.done)
+
+	c.inFlightLock.Lock()
+	delete(c.inFlight, key)
+	c.inFlightLock.Unlock()
+
+	return r.body, r.err
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// httpPublishHistory serves the retained publish-step execution history for a
+// release stream, recording when each publish step ran, its outcome, retry
+// count, and destination. If a tag path segment is present the result is
+// filtered to that tag; otherwise the stream's most recent publishes across
+// all tags are returned.
+func (c *Controller) httpPublishHistory(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	if _, ok := c.findReleaseByName(false, releaseStreamName); !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	entries := c.publishHistory.list(releaseStreamName, vars["tag"])
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
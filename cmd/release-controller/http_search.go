@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// searchCommitMaxTagsPerStream bounds how many of the most recent tags in a stream
+// are scanned per request. Changelog generation shells out to 'oc adm release info'
+// so an unbounded scan of a long-lived stable stream would be far too slow to serve
+// synchronously.
+const searchCommitMaxTagsPerStream = 25
+
+// CommitSearchMatch identifies a release tag whose changelog against its immediate
+// predecessor mentions the searched-for commit or pull request.
+type CommitSearchMatch struct {
+	Stream string `json:"stream"`
+	Tag    string `json:"tag"`
+	From   string `json:"from"`
+}
+
+// CommitSearchResult is the response body for /api/v1/search/commit/{sha}.
+type CommitSearchResult struct {
+	Query   string              `json:"query"`
+	Matches []CommitSearchMatch `json:"matches"`
+}
+
+// httpSearchCommit answers "which release tags contain this commit" by walking each
+// stream's recent accepted tags and checking whether the cached changelog between
+// consecutive tags mentions the requested commit SHA or PR URL. It is a convenience
+// over doing the same binary search by hand against /changelog.
+func (c *Controller) httpSearchCommit(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	query := mux.Vars(req)["sha"]
+	if len(query) < 7 {
+		http.Error(w, "sha must be at least 7 characters", http.StatusBadRequest)
+		return
+	}
+
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := &CommitSearchResult{Query: query}
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		tags := tagsForRelease(r, releasePhaseAccepted)
+		base := r.Target.Status.PublicDockerImageRepository
+		if len(base) == 0 {
+			continue
+		}
+		if len(tags) > searchCommitMaxTagsPerStream {
+			tags = tags[:searchCommitMaxTagsPerStream]
+		}
+		for i := 0; i+1 < len(tags); i++ {
+			to, from := tags[i], tags[i+1]
+			changelog, err := c.releaseInfo.ChangeLog(base+":"+from.Name, base+":"+to.Name)
+			if err != nil {
+				glog.V(4).Infof("Unable to load changelog for %s -> %s: %v", from.Name, to.Name, err)
+				continue
+			}
+			if strings.Contains(changelog, query) {
+				result.Matches = append(result.Matches, CommitSearchMatch{
+					Stream: r.Config.Name,
+					Tag:    to.Name,
+					From:   from.Name,
+				})
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// httpReleaseStorage serves the most recently computed StorageReport for a release
+// stream. Reports are recomputed by storageReportLoop, not on request, since
+// computing one requires an Images().Get() round trip per distinct tag digest.
+func (c *Controller) httpReleaseStorage(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	if _, ok := c.findReleaseByName(false, releaseStreamName); !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	report, ok := c.storageReports.get(releaseStreamName)
+	if !ok {
+		http.Error(w, "storage usage has not been computed for this release stream yet", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
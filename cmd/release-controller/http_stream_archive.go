@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StreamArchiveTag is the full-fidelity (not flattened, unlike ExportRow) state of
+// a single release tag: every controller-managed annotation, verbatim. Importing
+// an archive restores exactly these annotations onto the matching tag, so a
+// migrated stream resumes with the same phase, verification results, and
+// acceptance history it had on the source cluster.
+type StreamArchiveTag struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// StreamArchive is a portable, point-in-time snapshot of everything this
+// controller tracks about a release stream, for migrating the stream to a new
+// controller or namespace without losing acceptance history. Unlike httpExport,
+// which flattens tag state for compliance reporting, an archive is meant to be
+// round-tripped: export now, import later, and the controller picks back up
+// where it left off.
+type StreamArchive struct {
+	Stream     string             `json:"stream"`
+	ExportedAt time.Time          `json:"exportedAt"`
+	Tags       []StreamArchiveTag `json:"tags"`
+	// UpgradeEdges holds every recorded upgrade graph edge with an endpoint among
+	// Tags, so upgrade history for this stream's tags survives the migration.
+	UpgradeEdges []UpgradeRecord `json:"upgradeEdges,omitempty"`
+	// Events holds this stream's retained event history. Kubernetes Events expire
+	// long before a migration is likely to happen, so eventHistory is the only
+	// surviving source for them; see event_history.go.
+	Events []ReleaseEvent `json:"events,omitempty"`
+}
+
+// buildStreamArchive assembles the full exportable state of release: its tags'
+// annotations verbatim, the upgrade graph edges touching those tags, and its
+// retained event history.
+func (c *Controller) buildStreamArchive(release *Release) StreamArchive {
+	archive := StreamArchive{
+		Stream:     release.Config.Name,
+		ExportedAt: time.Now(),
+	}
+
+	names := make(map[string]bool)
+	for i := range release.Target.Spec.Tags {
+		tag := &release.Target.Spec.Tags[i]
+		if len(tag.Annotations[releaseAnnotationSource]) == 0 && len(tag.Annotations[releaseAnnotationPhase]) == 0 {
+			continue
+		}
+		names[tag.Name] = true
+		archive.Tags = append(archive.Tags, StreamArchiveTag{Name: tag.Name, Annotations: tag.Annotations})
+	}
+	sort.Slice(archive.Tags, func(i, j int) bool { return archive.Tags[i].Name < archive.Tags[j].Name })
+
+	if c.graph != nil {
+		for _, record := range c.graph.Records() {
+			if names[record.From] || names[record.To] {
+				archive.UpgradeEdges = append(archive.UpgradeEdges, record)
+			}
+		}
+		sort.Slice(archive.UpgradeEdges, func(i, j int) bool {
+			a, b := archive.UpgradeEdges[i], archive.UpgradeEdges[j]
+			if a.To == b.To {
+				return a.From < b.From
+			}
+			return a.To < b.To
+		})
+	}
+
+	if c.eventHistory != nil {
+		archive.Events = c.eventHistory.list(release.Source.Name, "", time.Time{}, time.Time{})
+	}
+
+	return archive
+}
+
+// httpStreamArchive serves a StreamArchive for the named release stream. See
+// StreamArchive for what it contains.
+func (c *Controller) httpStreamArchive(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	archive := c.buildStreamArchive(streams[streamName].Release)
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+// importStreamArchive restores archive's tag annotations onto release's target
+// image stream, re-adds its upgrade graph edges, and replays its event history.
+// Tags in the archive that no longer exist on release's target are reported but
+// otherwise skipped, since importing into a stream whose tag set has since
+// changed should not fail the rest of the restore.
+func (c *Controller) importStreamArchive(release *Release, archive *StreamArchive) ([]string, error) {
+	var skipped []string
+
+	target := release.Target.DeepCopy()
+	changes := 0
+	for _, archived := range archive.Tags {
+		tag := findTagReference(target, archived.Name)
+		if tag == nil {
+			skipped = append(skipped, archived.Name)
+			continue
+		}
+		tag.Annotations = archived.Annotations
+		changes++
+	}
+	if changes > 0 {
+		is, err := c.imageClient.ImageStreams(target.Namespace).Update(target)
+		if err != nil {
+			return skipped, err
+		}
+		updateReleaseTarget(release, is)
+	}
+
+	if c.graph != nil {
+		for _, record := range archive.UpgradeEdges {
+			c.graph.Add(record.From, record.To, record.Results...)
+		}
+	}
+
+	if c.eventHistory != nil {
+		for _, evt := range archive.Events {
+			c.eventHistory.record(&corev1.Event{
+				InvolvedObject: corev1.ObjectReference{Name: release.Source.Name},
+				Type:           evt.Type,
+				Reason:         evt.Reason,
+				Message:        evt.Message,
+				LastTimestamp:  metav1.NewTime(evt.Time),
+			})
+		}
+	}
+
+	return skipped, nil
+}
+
+// httpStreamArchiveImport restores a StreamArchive (as produced by
+// httpStreamArchive) onto the named release stream, which may be on a different
+// controller or namespace than the one that exported it. The archive's own
+// "stream" field is informational only; tags are matched by name against the
+// target stream regardless of what stream they were exported from.
+func (c *Controller) httpStreamArchiveImport(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var archive StreamArchive
+	if err := json.Unmarshal(body, &archive); err != nil {
+		http.Error(w, fmt.Sprintf("invalid archive: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	skipped, err := c.importStreamArchive(streams[streamName].Release, &archive)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to import archive: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(struct {
+		Imported int      `json:"imported"`
+		Skipped  []string `json:"skipped,omitempty"`
+	}{Imported: len(archive.Tags) - len(skipped), Skipped: skipped}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
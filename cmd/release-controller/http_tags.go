@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/template"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// tagsPageDefaultLimit is used when ?limit is not specified.
+	tagsPageDefaultLimit = 100
+	// tagsPageMaxLimit bounds how many tags a single page may return, so a
+	// client can't force the controller to marshal an unbounded response.
+	tagsPageMaxLimit = 1000
+)
+
+// TagSummary is one row of a paginated tag listing.
+type TagSummary struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	// RejectReason is one of the RejectReason* constants, set only for tags
+	// in the Rejected phase.
+	RejectReason string `json:"rejectReason,omitempty"`
+	Created      string `json:"created,omitempty"`
+}
+
+// TagPage is a single page of a release stream's tags, in the same stable
+// order tagsForRelease produces (semantic-version order for Stable streams,
+// newest-first for others), with a cursor for fetching the next page.
+type TagPage struct {
+	Tags  []TagSummary `json:"tags"`
+	Next  string       `json:"next,omitempty"`
+	Total int          `json:"total"`
+}
+
+// filteredTagNames returns release's stably-ordered tag names, as cached by
+// c.tagIndex, restricted to those whose phase annotation equals phase and
+// (if rejectReason is non-empty) whose rejectReason annotation equals
+// rejectReason. Either filter may be left empty to skip it.
+func (c *Controller) filteredTagNames(release *Release, phase, rejectReason string) []string {
+	index := c.tagIndex.indexFor(release)
+	if len(phase) == 0 && len(rejectReason) == 0 {
+		return index.names
+	}
+	names := make([]string, 0, len(index.names))
+	for _, name := range index.names {
+		tag := findTagReference(release.Target, name)
+		if tag == nil {
+			continue
+		}
+		if len(phase) > 0 && tag.Annotations[releaseAnnotationPhase] != phase {
+			continue
+		}
+		if len(rejectReason) > 0 && tag.Annotations[releaseAnnotationRejectReason] != rejectReason {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// tagPageWindow resolves the ?limit, ?offset, and ?after query parameters
+// against names into the [start,end) slice window to serve. ?after, if
+// present, takes precedence over ?offset.
+func tagPageWindow(req *http.Request, names []string) (start, end int, err error) {
+	limit := tagsPageDefaultLimit
+	if raw := req.URL.Query().Get("limit"); len(raw) > 0 {
+		parsed, convErr := strconv.Atoi(raw)
+		if convErr != nil || parsed <= 0 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = parsed
+	}
+	if limit > tagsPageMaxLimit {
+		limit = tagsPageMaxLimit
+	}
+
+	switch {
+	case len(req.URL.Query().Get("after")) > 0:
+		after := req.URL.Query().Get("after")
+		pos := -1
+		for i, name := range names {
+			if name == after {
+				pos = i
+				break
+			}
+		}
+		if pos < 0 {
+			return 0, 0, fmt.Errorf("after does not name a known tag")
+		}
+		start = pos + 1
+	case len(req.URL.Query().Get("offset")) > 0:
+		parsed, convErr := strconv.Atoi(req.URL.Query().Get("offset"))
+		if convErr != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+		start = parsed
+	}
+	if start > len(names) {
+		start = len(names)
+	}
+
+	end = start + limit
+	if end > len(names) {
+		end = len(names)
+	}
+	return start, end, nil
+}
+
+// httpReleaseTagsPage serves a paginated listing of a release stream's tags,
+// for long-lived streams with too many tags to return in one response.
+// ?after=<tag> resumes after the given tag name in stable order, ?offset
+// resumes at a numeric position (ignored if ?after is also set), ?phase
+// restricts the listing to tags in that phase (e.g. Accepted), ?rejectReason
+// further restricts it to Rejected tags with that RejectReason (e.g.
+// UpgradeRegression), and ?limit bounds the page size (default
+// tagsPageDefaultLimit, max tagsPageMaxLimit). The underlying sort order is
+// cached per release target generation by c.tagIndex, so scanning through
+// many pages only re-sorts the tag set once per generation, not once per
+// page.
+func (c *Controller) httpReleaseTagsPage(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, releaseStreamName)
+	if !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[releaseStreamName].Release
+
+	names := c.filteredTagNames(release, req.URL.Query().Get("phase"), req.URL.Query().Get("rejectReason"))
+	start, end, err := tagPageWindow(req, names)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page := TagPage{Total: len(names)}
+	for _, name := range names[start:end] {
+		tag := findTagReference(release.Target, name)
+		if tag == nil {
+			continue
+		}
+		page.Tags = append(page.Tags, TagSummary{
+			Name:         tag.Name,
+			Phase:        tag.Annotations[releaseAnnotationPhase],
+			RejectReason: tag.Annotations[releaseAnnotationRejectReason],
+			Created:      tag.Annotations[releaseAnnotationCreationTimestamp],
+		})
+	}
+	if end < len(names) {
+		page.Next = names[end-1]
+	}
+
+	data, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+const tagsStreamPageHtml = `
+<h1>Tags for {{ .Release.Config.Name }}</h1>
+<hr>
+<table class="table">
+<thead><tr><th>Name</th><th>Phase</th><th>Reject reason</th><th>Created</th></tr></thead>
+<tbody>
+{{ range .Page.Tags }}
+<tr><td><a href="/releasestream/{{ $.Release.Config.Name }}/release/{{ .Name }}">{{ .Name }}</a></td><td>{{ .Phase }}</td><td>{{ .RejectReason }}</td><td>{{ .Created }}</td></tr>
+{{ end }}
+</tbody>
+</table>
+<p>
+{{ if .PrevQuery }}<a href="?{{ .PrevQuery }}">&laquo; Prev</a>{{ end }}
+{{ if .NextQuery }}<a href="?{{ .NextQuery }}">Next &raquo;</a>{{ end }}
+</p>
+`
+
+// tagsStreamPage is the data passed to tagsStreamPageHtml.
+type tagsStreamPage struct {
+	Release   *Release
+	Page      TagPage
+	PrevQuery string
+	NextQuery string
+}
+
+// httpReleaseStreamTags renders an HTML, offset-paginated listing of a
+// release stream's tags with next/prev links, for streams with too many tags
+// to usefully render on the dashboard page. It shares its ?limit, ?offset,
+// ?phase, and ?rejectReason query parameters with httpReleaseTagsPage, the
+// JSON equivalent of this page.
+func (c *Controller) httpReleaseStreamTags(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, releaseStreamName)
+	if !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[releaseStreamName].Release
+
+	phase := req.URL.Query().Get("phase")
+	rejectReason := req.URL.Query().Get("rejectReason")
+	names := c.filteredTagNames(release, phase, rejectReason)
+
+	limit := tagsPageDefaultLimit
+	if raw := req.URL.Query().Get("limit"); len(raw) > 0 {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	if limit > tagsPageMaxLimit {
+		limit = tagsPageMaxLimit
+	}
+
+	start, end, err := tagPageWindow(req, names)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page := TagPage{Total: len(names)}
+	for _, name := range names[start:end] {
+		tag := findTagReference(release.Target, name)
+		if tag == nil {
+			continue
+		}
+		page.Tags = append(page.Tags, TagSummary{
+			Name:         tag.Name,
+			Phase:        tag.Annotations[releaseAnnotationPhase],
+			RejectReason: tag.Annotations[releaseAnnotationRejectReason],
+			Created:      tag.Annotations[releaseAnnotationCreationTimestamp],
+		})
+	}
+
+	query := func(offset int) string {
+		v := url.Values{}
+		v.Set("offset", strconv.Itoa(offset))
+		v.Set("limit", strconv.Itoa(limit))
+		if len(phase) > 0 {
+			v.Set("phase", phase)
+		}
+		if len(rejectReason) > 0 {
+			v.Set("rejectReason", rejectReason)
+		}
+		return v.Encode()
+	}
+
+	data := tagsStreamPage{Release: release, Page: page}
+	if start > 0 {
+		prevOffset := start - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		data.PrevQuery = query(prevOffset)
+	}
+	if end < len(names) {
+		data.NextQuery = query(end)
+	}
+
+	fmt.Fprintf(w, htmlPageStart, fmt.Sprintf("Tags for %s", releaseStreamName))
+	tmpl := template.Must(template.New("tagsStreamPage").Parse(tagsStreamPageHtml))
+	if err := tmpl.Execute(w, data); err != nil {
+		glog.Errorf("Unable to render page: %v", err)
+	}
+	fmt.Fprintln(w, htmlPageEnd)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// TagBatchSelector narrows a batch operation to a subset of a release stream's
+// tags. A selector field that is left empty does not filter on that
+// criterion. All non-empty fields must match for a tag to be included.
+type TagBatchSelector struct {
+	// Phase restricts the selection to tags currently in this phase (e.g.
+	// "Ready", "Rejected"). See releasePhase* constants.
+	Phase string `json:"phase,omitempty"`
+	// VersionRange restricts the selection to tags whose name parses as a
+	// semantic version satisfying this range, using the same syntax as
+	// ReleaseConfig's UpgradeExclusions (github.com/blang/semver). Tags whose
+	// name does not parse as semver never match a non-empty VersionRange.
+	VersionRange string `json:"versionRange,omitempty"`
+	// OlderThan restricts the selection to tags created more than this long
+	// ago, as a Go duration string (e.g. "720h").
+	OlderThan string `json:"olderThan,omitempty"`
+}
+
+// TagBatchRequest is the payload POSTed to run a bulk tag operation.
+type TagBatchRequest struct {
+	// Operation is one of "pin", "unpin", "expire", "retest".
+	Operation string `json:"operation"`
+	// Selector identifies which tags in the stream the operation applies to.
+	Selector TagBatchSelector `json:"selector"`
+	// DryRun, if true, computes and reports the tags the operation would
+	// apply to without changing anything.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// TagBatchResult reports the outcome of a batch operation against a single
+// selected tag.
+type TagBatchResult struct {
+	Tag     string `json:"tag"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TagBatchResponse is the response to a batch tag operation.
+type TagBatchResponse struct {
+	DryRun  bool             `json:"dryRun"`
+	Results []TagBatchResult `json:"results"`
+}
+
+// retestPreconditionPhases lists the phases a tag must already be in for the
+// "retest" operation to apply to it; anything else is reported as a per-item
+// error rather than silently skipped.
+var retestPreconditionPhases = []string{releasePhaseReady, releasePhaseRejected}
+
+// httpTagsBatch runs a pin/unpin/expire/retest operation across the tags in a
+// release stream that match the request's selector, so an incident cleanup
+// doesn't require scripting dozens of individual calls against the other
+// single-tag endpoints. Each selected tag is applied independently and
+// reported in Results; one tag failing does not stop the others from being
+// attempted.
+func (c *Controller) httpTagsBatch(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[streamName].Release
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request TagBatchRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	switch request.Operation {
+	case "pin", "unpin", "expire", "retest":
+	default:
+		http.Error(w, fmt.Sprintf("operation must be one of pin, unpin, expire, retest, got %q", request.Operation), http.StatusBadRequest)
+		return
+	}
+
+	var versionRange semver.Range
+	if len(request.Selector.VersionRange) > 0 {
+		r, err := semver.ParseRange(request.Selector.VersionRange)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("selector.versionRange must be a valid semantic version range: %v", err), http.StatusBadRequest)
+			return
+		}
+		versionRange = r
+	}
+	var olderThan time.Duration
+	if len(request.Selector.OlderThan) > 0 {
+		d, err := time.ParseDuration(request.Selector.OlderThan)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("selector.olderThan must be a valid duration: %v", err), http.StatusBadRequest)
+			return
+		}
+		olderThan = d
+	}
+
+	now := time.Now()
+	tags := selectTagsForBatch(release, request.Selector.Phase, versionRange, olderThan, now)
+
+	response := TagBatchResponse{DryRun: request.DryRun}
+	for _, tag := range tags {
+		result := TagBatchResult{Tag: tag.Name}
+		if request.DryRun {
+			result.Applied = true
+			response.Results = append(response.Results, result)
+			continue
+		}
+		var err error
+		switch request.Operation {
+		case "pin":
+			err = c.setTagKeep(release, true, tag.Name)
+		case "unpin":
+			err = c.setTagKeep(release, false, tag.Name)
+		case "expire":
+			err = c.removeReleaseTags(release, []*imagev1.TagReference{tag})
+		case "retest":
+			err = c.ensureReleaseTagPhase(release, retestPreconditionPhases, releasePhaseReady, map[string]string{
+				releaseAnnotationVerify: "",
+			}, tag.Name)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Applied = true
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+// selectTagsForBatch returns release's tags matching all of the given
+// criteria; a zero-valued criterion (empty phase, nil range, zero duration)
+// is not applied.
+func selectTagsForBatch(release *Release, phase string, versionRange semver.Range, olderThan time.Duration, now time.Time) []*imagev1.TagReference {
+	var tags []*imagev1.TagReference
+	for i := range release.Target.Spec.Tags {
+		tag := &release.Target.Spec.Tags[i]
+		if tag.Annotations[releaseAnnotationName] != release.Config.Name {
+			continue
+		}
+		if tag.Annotations[releaseAnnotationSource] != fmt.Sprintf("%s/%s", release.Source.Namespace, release.Source.Name) {
+			continue
+		}
+		if len(phase) > 0 && tag.Annotations[releaseAnnotationPhase] != phase {
+			continue
+		}
+		if versionRange != nil {
+			v, err := semver.Parse(tag.Name)
+			if err != nil || !versionRange(v) {
+				continue
+			}
+		}
+		if olderThan > 0 {
+			created, err := time.Parse(time.RFC3339, tag.Annotations[releaseAnnotationCreationTimestamp])
+			if err != nil || now.Sub(created) < olderThan {
+				continue
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+)
+
+// testBudgetConsiderOptionalThreshold is the minimum share of a stream's budget
+// a step must consume, with zero rejections attributed to it in the window,
+// before it's suggested as a candidate to mark Optional.
+const testBudgetConsiderOptionalThreshold = 20.0
+
+// JobBudgetSuggestion summarizes one ProwJob verification step's CI spend
+// against its stream's configured TestBudget, and whether historical
+// rejections justify keeping it mandatory.
+type JobBudgetSuggestion struct {
+	Step             string  `json:"step"`
+	Seconds          float64 `json:"seconds"`
+	BudgetPercent    float64 `json:"budgetPercent"`
+	UniqueRejections int     `json:"uniqueRejections"`
+	WindowDays       int     `json:"windowDays"`
+	Suggestion       string  `json:"suggestion,omitempty"`
+}
+
+// httpReleaseBudget reports, for each ProwJob verification step in the release
+// stream's configured TestBudget window, how much of the budget it consumed
+// and how many distinct tags it caused to be rejected, with a suggestion to
+// consider marking a step Optional when it consumes a large share of the
+// budget without ever being the cause of a rejection.
+func (c *Controller) httpReleaseBudget(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	releaseStreamName := vars["release"]
+
+	streams, ok := c.findReleaseByName(false, releaseStreamName)
+	if !ok {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	release := streams[releaseStreamName].Release
+
+	budget := release.Config.TestBudget
+	if budget == nil {
+		http.Error(w, "release stream does not have a testBudget configured", http.StatusNotFound)
+		return
+	}
+	window := testBudgetWindow(budget)
+	windowDays := budget.WindowDays
+	if windowDays <= 0 {
+		windowDays = testBudgetDefaultWindowDays
+	}
+
+	records := c.testBudget.snapshot(releaseStreamName, window)
+
+	type aggregate struct {
+		seconds    float64
+		rejections map[string]bool
+	}
+	byStep := make(map[string]*aggregate)
+	for _, record := range records {
+		agg, ok := byStep[record.step]
+		if !ok {
+			agg = &aggregate{rejections: make(map[string]bool)}
+			byStep[record.step] = agg
+		}
+		agg.seconds += record.seconds
+		if record.rejected {
+			agg.rejections[record.tag] = true
+		}
+	}
+
+	var suggestions []JobBudgetSuggestion
+	for step, agg := range byStep {
+		percent := 0.0
+		if budget.Seconds > 0 {
+			percent = agg.seconds / float64(budget.Seconds) * 100
+		}
+		suggestion := JobBudgetSuggestion{
+			Step:             step,
+			Seconds:          agg.seconds,
+			BudgetPercent:    percent,
+			UniqueRejections: len(agg.rejections),
+			WindowDays:       windowDays,
+		}
+		if percent >= testBudgetConsiderOptionalThreshold && len(agg.rejections) == 0 {
+			suggestion.Suggestion = fmt.Sprintf("job %s consumed %.0f%% of budget with 0 unique rejections in %d days — consider optional", step, percent, windowDays)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool { return suggestions[i].BudgetPercent > suggestions[j].BudgetPercent })
+
+	data, err := json.MarshalIndent(suggestions, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -19,13 +19,55 @@ import (
 type ReleaseNode struct {
 	Version string `json:"version"`
 	Payload string `json:"payload"`
+	// Metadata carries the stream a node was produced by as a Cincinnati
+	// channel membership annotation, using the same key OpenShift's
+	// production graph-data uses for this purpose, so a consuming OSUS
+	// instance's existing channel-head logic keeps working unchanged.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type ReleaseEdge []int
 
+// conditionalUpdateEdge names a from/to pair the way Cincinnati's
+// conditionalEdges do: by version, not by node index.
+type conditionalUpdateEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// conditionalUpdateMatchingRule is deliberately limited to the "Always" type:
+// this controller has no notion of cluster-reported PromQL facts to scope a
+// risk to (that's Cincinnati's ClusterCondition rule type), so every risk it
+// emits applies unconditionally and lets the client decide.
+type conditionalUpdateMatchingRule struct {
+	Type string `json:"type"`
+}
+
+type conditionalUpdateRisk struct {
+	URL           string                          `json:"url"`
+	Name          string                          `json:"name"`
+	Message       string                          `json:"message"`
+	MatchingRules []conditionalUpdateMatchingRule `json:"matchingRules"`
+}
+
+// conditionalUpdate is one entry of ReleaseGraph.ConditionalEdges: a group of
+// edges sharing the same risk(s).
+type conditionalUpdate struct {
+	Edges []conditionalUpdateEdge `json:"edges"`
+	Risks []conditionalUpdateRisk `json:"risks"`
+}
+
 type ReleaseGraph struct {
 	Nodes []ReleaseNode `json:"nodes"`
 	Edges []ReleaseEdge `json:"edges"`
+	// ConditionalEdges surfaces edges this controller has recorded failed
+	// verifications and no successful one for as Cincinnati conditional edges
+	// (carrying an unconditional risk) instead of silently dropping them, so
+	// an OSUS consumer can still offer them with a warning rather than never
+	// learning the path exists. Only the stable channels ('' and stable-*)
+	// produce them; the prerelease and nightly channels list such edges as
+	// ordinary edges.
+	ConditionalEdges []conditionalUpdate `json:"conditionalEdges,omitempty"`
 }
 
 func (c *Controller) graphHandler(w http.ResponseWriter, req *http.Request) {
@@ -62,36 +104,47 @@ func (c *Controller) graphHandler(w http.ResponseWriter, req *http.Request) {
 		for _, s := range streams {
 			for _, tag := range s.Tags {
 				nodesByName[tag.Name] = len(nodes)
+				metadata := map[string]string{"io.openshift.upgrades.graph.release.channels": s.Release.Config.Name}
 				if id := findImageIDForTag(s.Release.Target, tag.Name); len(id) > 0 {
 					nodes = append(nodes, ReleaseNode{
-						Version: tag.Name,
-						Payload: s.Release.Target.Status.PublicDockerImageRepository + "@" + id,
+						Version:  tag.Name,
+						Payload:  s.Release.Target.Status.PublicDockerImageRepository + "@" + id,
+						Metadata: metadata,
 					})
 				} else {
 					nodes = append(nodes, ReleaseNode{
-						Version: tag.Name,
-						Payload: s.Release.Target.Status.PublicDockerImageRepository + ":" + tag.Name,
+						Version:  tag.Name,
+						Payload:  s.Release.Target.Status.PublicDockerImageRepository + ":" + tag.Name,
+						Metadata: metadata,
 					})
 				}
 			}
 		}
 
 		edges := make([]ReleaseEdge, 0, len(histories))
+		var conditionalEdges []conditionalUpdate
 		for _, history := range histories {
+			rejected := false
 			switch {
 			case channel == "", channel == "stable":
 				if history.Success == 0 {
-					continue
+					rejected = history.Failure > 0
+					if !rejected {
+						continue
+					}
 				}
 			case channel == "prerelease", channel == "nightly":
 			case strings.HasPrefix(channel, "stable-"):
-				if history.Success == 0 {
-					continue
-				}
 				branch := channel[len("stable-"):] + "."
 				if !strings.HasPrefix(history.To, branch) {
 					continue
 				}
+				if history.Success == 0 {
+					rejected = history.Failure > 0
+					if !rejected {
+						continue
+					}
+				}
 			case strings.HasPrefix(channel, "prerelease-"):
 				branch := channel[len("prerelease-"):] + "."
 				if !strings.HasPrefix(history.To, branch) {
@@ -106,20 +159,32 @@ func (c *Controller) graphHandler(w http.ResponseWriter, req *http.Request) {
 				http.Error(w, "Unsupported ?channel, must be '', 'prerelease', 'prerelease-*', 'nightly', 'nightly-*', 'stable', or 'stable-*", http.StatusBadRequest)
 				return
 			}
-			to, ok := nodesByName[history.To]
-			if !ok {
+			if _, ok := nodesByName[history.To]; !ok {
 				continue
 			}
-			from, ok := nodesByName[history.From]
-			if !ok {
+			if _, ok := nodesByName[history.From]; !ok {
+				continue
+			}
+			if rejected {
+				conditionalEdges = append(conditionalEdges, conditionalUpdate{
+					Edges: []conditionalUpdateEdge{{From: history.From, To: history.To}},
+					Risks: []conditionalUpdateRisk{{
+						Name:    "RejectedUpgrade",
+						Message: fmt.Sprintf("Upgrading from %s to %s failed verification %d time(s) and has no recorded success", history.From, history.To, history.Failure),
+						MatchingRules: []conditionalUpdateMatchingRule{
+							{Type: "Always"},
+						},
+					}},
+				})
 				continue
 			}
-			edges = append(edges, ReleaseEdge{from, to})
+			edges = append(edges, ReleaseEdge{nodesByName[history.From], nodesByName[history.To]})
 		}
 
 		graph := &ReleaseGraph{
-			Nodes: nodes,
-			Edges: edges,
+			Nodes:            nodes,
+			Edges:            edges,
+			ConditionalEdges: conditionalEdges,
 		}
 
 		data, err := json.MarshalIndent(graph, "", "  ")
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	prowapiv1 "github.com/openshift/release-controller/pkg/prow/apiv1"
+)
+
+// UpgradeTestRequest launches a one-off upgrade verification between two
+// release payloads that doesn't need a ReleaseConfig.Verify entry.
+type UpgradeTestRequest struct {
+	// From and To each name either an existing release tag (e.g.
+	// "4.10.0-0.nightly-2021-01-01-000000") or a standalone pull spec
+	// (registry/repo@sha256:... or registry/repo:tag). A known tag is
+	// resolved to its public pull spec and its name becomes the graph node
+	// recorded for it; a standalone pull spec is used, and recorded in the
+	// graph, as-is.
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Job is the name of a periodic Prow job (as defined in the loaded Prow
+	// config) to run as the upgrade test.
+	Job string `json:"job"`
+}
+
+// UpgradeTestResponse reports the launched (or reused) one-off ProwJob.
+type UpgradeTestResponse struct {
+	Job   string `json:"job"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	State string `json:"state"`
+	URL   string `json:"url,omitempty"`
+}
+
+// resolveUpgradeTestRef resolves ref to the (graph node name, pull spec) pair
+// to use for an on-demand upgrade test. If ref names a release tag that
+// currently exists, that tag's name and public pull spec are used; otherwise
+// ref is assumed to already be a pull spec and is used for both.
+func (c *Controller) resolveUpgradeTestRef(ref string) (string, string, error) {
+	if tags, _ := c.findReleaseStreamTags(false, ref); tags[ref] != nil {
+		info := tags[ref]
+		pullSpec := findPublicImagePullSpec(info.Release.Target, ref)
+		if len(pullSpec) == 0 {
+			return "", "", fmt.Errorf("tag %s has no public pull spec yet", ref)
+		}
+		return ref, pullSpec, nil
+	}
+	if _, err := imagereference.Parse(ref); err != nil {
+		return "", "", fmt.Errorf("%s is neither a known release tag nor a valid pull spec: %v", ref, err)
+	}
+	return ref, ref, nil
+}
+
+// httpUpgradeTest launches a one-off upgrade verification ProwJob between two
+// arbitrary release payloads (POST only), so a release manager can test an
+// upgrade edge without asking a CI admin to hand-craft a job. The launched
+// job carries the same release.openshift.io/verify label and
+// from/to-tag annotations as verification jobs created by the normal sync
+// path, so it is picked up by the same periodic scan that records completed
+// jobs into the upgrade graph (see rebuildGraphFromProwJobs) - no separate
+// reconciliation is needed, and the result shows up in the "Upgrades
+// from/to" sections of both tags' pages once that scan runs.
+//
+// Unlike verification jobs launched from a ReleaseConfig, there is no
+// release stream providing IMAGE_FORMAT/IMAGE_* component overrides for this
+// job, so a job whose pod spec requires those will fail to launch; this is a
+// deliberate scope limit of an ad hoc, stream-independent test.
+func (c *Controller) httpUpgradeTest(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request UpgradeTestRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(request.From) == 0 || len(request.To) == 0 || len(request.Job) == 0 {
+		http.Error(w, "from, to, and job are all required", http.StatusBadRequest)
+		return
+	}
+
+	config := c.prowConfigLoader.Config()
+	if config == nil {
+		http.Error(w, "no prow jobs have been defined", http.StatusServiceUnavailable)
+		return
+	}
+	periodicConfig, ok := hasProwJob(config, request.Job)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no prow job named %s is defined", request.Job), http.StatusBadRequest)
+		return
+	}
+
+	fromName, fromPullSpec, err := c.resolveUpgradeTestRef(request.From)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	toName, toPullSpec, err := c.resolveUpgradeTestRef(request.To)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spec := prowapiv1.ProwSpecForPeriodicConfig(periodicConfig)
+	ok, err = addReleaseEnvToProwJobSpec(spec, nil, nil, toPullSpec, fromPullSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("prow job %s requires image inputs (IMAGE_FORMAT/IMAGE_*) that an on-demand upgrade test cannot provide", request.Job), http.StatusBadRequest)
+		return
+	}
+
+	prowJobName := fmt.Sprintf("upgrade-test-%s", rand.String(8))
+	pj := &prowapiv1.ProwJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "prow.k8s.io/v1", Kind: "ProwJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: prowJobName,
+			Annotations: map[string]string{
+				releaseAnnotationFromTag: fromName,
+				releaseAnnotationToTag:   toName,
+				"prow.k8s.io/job":        spec.Job,
+			},
+			Labels: map[string]string{
+				"release.openshift.io/verify": "true",
+				"prow.k8s.io/type":            string(spec.Type),
+				"prow.k8s.io/job":             spec.Job,
+			},
+		},
+		Spec: *spec,
+		Status: prowapiv1.ProwJobStatus{
+			StartTime: metav1.Now(),
+			State:     prowapiv1.TriggeredState,
+		},
+	}
+	if inputs := verificationJobInputsForSpec(spec); inputs != nil {
+		data, err := json.Marshal(inputs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pj.Annotations[releaseAnnotationVerifyInputs] = string(data)
+	}
+
+	out, err := c.prowClient.Create(objectToUnstructured(pj), metav1.CreateOptions{})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to launch upgrade test: %v", err), http.StatusInternalServerError)
+		return
+	}
+	response := UpgradeTestResponse{Job: prowJobName, From: fromName, To: toName, State: string(prowapiv1.TriggeredState)}
+	if status, ok := prowJobVerificationStatus(out); ok {
+		response.State = status.State
+		response.URL = status.URL
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+)
+
+// UpgradeSearchResult is one from/to edge in a tag's upgrade history, returned by
+// httpReleaseTagUpgrades. It flattens UpgradeHistory's success/failure counts into a
+// ratio so callers can sort/filter without recomputing it, and drops the per-result
+// History map, which is not meaningful to summarize across a paginated response.
+type UpgradeSearchResult struct {
+	From         string  `json:"from"`
+	To           string  `json:"to"`
+	State        string  `json:"state"`
+	Success      int     `json:"success"`
+	Failure      int     `json:"failure"`
+	Total        int     `json:"total"`
+	SuccessRatio float64 `json:"successRatio"`
+}
+
+func newUpgradeSearchResult(h UpgradeHistory) UpgradeSearchResult {
+	r := UpgradeSearchResult{
+		From:    h.From,
+		To:      h.To,
+		State:   upgradeSummaryState(&h),
+		Success: h.Success,
+		Failure: h.Failure,
+		Total:   h.Total,
+	}
+	if r.Total > 0 {
+		r.SuccessRatio = float64(r.Success) / float64(r.Total)
+	}
+	return r
+}
+
+// httpReleaseTagUpgrades serves a queryable, paginated view of a tag's upgrade
+// history, for tags that have accumulated too many upgrade attempts to usefully
+// render on the tag page. direction selects which side of the edge tag is on
+// ("in": edges upgrading to tag, "out": edges upgrading from tag, default both).
+// state filters to edges whose aggregate state (Succeeded, Failed, or Pending, per
+// upgradeSummaryState) matches. limit bounds the number of results returned, after
+// sorting by descending success ratio (ties broken by descending total attempts).
+func (c *Controller) httpReleaseTagUpgrades(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { glog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	vars := mux.Vars(req)
+	release := vars["release"]
+	tag := vars["tag"]
+
+	tags, ok := c.findReleaseStreamTags(true, tag)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unable to find release tag %s, it may have been deleted", tag), http.StatusNotFound)
+		return
+	}
+	info := tags[tag]
+	if len(release) > 0 && info.Release.Config.Name != release {
+		http.Error(w, fmt.Sprintf("Release tag %s does not belong to release %s", tag, release), http.StatusNotFound)
+		return
+	}
+
+	direction := req.URL.Query().Get("direction")
+	switch direction {
+	case "", "in", "out":
+	default:
+		http.Error(w, "direction must be one of '', 'in', or 'out'", http.StatusBadRequest)
+		return
+	}
+
+	var results []UpgradeSearchResult
+	if direction == "" || direction == "in" {
+		for _, h := range c.graph.UpgradesTo(tag) {
+			results = append(results, newUpgradeSearchResult(h))
+		}
+	}
+	if direction == "" || direction == "out" {
+		for _, h := range c.graph.UpgradesFrom(tag) {
+			results = append(results, newUpgradeSearchResult(h))
+		}
+	}
+
+	if state := req.URL.Query().Get("state"); len(state) > 0 {
+		switch state {
+		case releaseVerificationStateSucceeded, releaseVerificationStateFailed, releaseVerificationStatePending:
+		default:
+			http.Error(w, fmt.Sprintf("state must be one of '', %q, %q, or %q", releaseVerificationStateSucceeded, releaseVerificationStateFailed, releaseVerificationStatePending), http.StatusBadRequest)
+			return
+		}
+		filtered := results[:0]
+		for _, r := range results {
+			if r.State == state {
+				filtered = append(filtered, r)
+			}
+		}
+		results = filtered
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].SuccessRatio != results[j].SuccessRatio {
+			return results[i].SuccessRatio > results[j].SuccessRatio
+		}
+		return results[i].Total > results[j].Total
+	})
+
+	if limitString := req.URL.Query().Get("limit"); len(limitString) > 0 {
+		limit, err := strconv.Atoi(limitString)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		if limit < len(results) {
+			results = results[:limit]
+		}
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// userPrefsConfigMapName is the well-known ConfigMap, in the controller's job
+// namespace, used to persist every user's starred streams/jobs. A single
+// ConfigMap keyed by username (mirroring delayedActionConfigMapName's
+// one-ConfigMap-of-many-ids approach in sync_scheduler.go) avoids granting this
+// controller's service account create/delete on a ConfigMap per user, which a
+// per-user-object design would require.
+const userPrefsConfigMapName = "release-controller-user-prefs"
+
+// UserPreferences is one user's personalization state: the streams and
+// verification jobs they want surfaced on their personalized dashboard.
+type UserPreferences struct {
+	StarredStreams []string `json:"starredStreams,omitempty"`
+	StarredJobs    []string `json:"starredJobs,omitempty"`
+}
+
+// authenticatedUser returns the identity a trusted auth proxy in front of this
+// service has set for req, and whether one is configured and present. See
+// Controller.authProxyUserHeader.
+func (c *Controller) authenticatedUser(req *http.Request) (string, bool) {
+	if len(c.authProxyUserHeader) == 0 {
+		return "", false
+	}
+	user := req.Header.Get(c.authProxyUserHeader)
+	if len(user) == 0 {
+		return "", false
+	}
+	return user, true
+}
+
+// userPreferences returns the persisted preferences for user, or the zero
+// value if none have been saved yet or no ConfigMap client is configured.
+func (c *Controller) userPreferences(user string) (UserPreferences, error) {
+	var prefs UserPreferences
+	if c.configMapClient == nil {
+		return prefs, nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(userPrefsConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, err
+	}
+	raw, ok := cm.Data[user]
+	if !ok {
+		return prefs, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &prefs); err != nil {
+		return prefs, err
+	}
+	return prefs, nil
+}
+
+// updateUserPreferences fetches or creates userPrefsConfigMapName, applies
+// mutate to user's current preferences, and writes the result back.
+func (c *Controller) updateUserPreferences(user string, mutate func(*UserPreferences)) error {
+	if c.configMapClient == nil {
+		return fmt.Errorf("user preferences are not enabled on this controller")
+	}
+	client := c.configMapClient.ConfigMaps(c.jobNamespace)
+	cm, err := client.Get(userPrefsConfigMapName, metav1.GetOptions{})
+	create := errors.IsNotFound(err)
+	if err != nil && !create {
+		return err
+	}
+	if create {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: userPrefsConfigMapName, Namespace: c.jobNamespace},
+			Data:       make(map[string]string),
+		}
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+
+	var prefs UserPreferences
+	if raw, ok := cm.Data[user]; ok {
+		if err := json.Unmarshal([]byte(raw), &prefs); err != nil {
+			return err
+		}
+	}
+	mutate(&prefs)
+
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+	cm.Data[user] = string(data)
+
+	if create {
+		_, err = client.Create(cm)
+	} else {
+		_, err = client.Update(cm)
+	}
+	return err
+}
+
+// toggleStar adds or removes name from a preference list.
+func toggleStar(names []string, name string, star bool) []string {
+	var out []string
+	found := false
+	for _, existing := range names {
+		if existing == name {
+			found = true
+			if !star {
+				continue
+			}
+		}
+		out = append(out, existing)
+	}
+	if star && !found {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// StarRequest stars or unstars a single stream or verification job for the
+// authenticated user.
+type StarRequest struct {
+	// Kind is "stream" or "job".
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Star is false to unstar.
+	Star bool `json:"star"`
+}
+
+// httpMeStars reports (GET) or updates (POST) the authenticated user's starred
+// streams and verification jobs.
+func (c *Controller) httpMeStars(w http.ResponseWriter, req *http.Request) {
+	user, ok := c.authenticatedUser(req)
+	if !ok {
+		http.Error(w, "no authenticated user; this instance is not configured with --auth-proxy-user-header, or the request did not carry it", http.StatusUnauthorized)
+		return
+	}
+
+	if req.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var star StarRequest
+		if err := json.Unmarshal(body, &star); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(star.Name) == 0 {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		switch star.Kind {
+		case "stream":
+			if err := c.updateUserPreferences(user, func(p *UserPreferences) {
+				p.StarredStreams = toggleStar(p.StarredStreams, star.Name, star.Star)
+			}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		case "job":
+			if err := c.updateUserPreferences(user, func(p *UserPreferences) {
+				p.StarredJobs = toggleStar(p.StarredJobs, star.Name, star.Star)
+			}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.Error(w, `kind must be "stream" or "job"`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	prefs, err := c.userPreferences(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(prefs, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+// MeOverview is the authenticated user's personalized subset of the overview
+// dashboard: their starred streams' StreamOverview entries, plus the state of
+// each of their starred verification jobs on the newest tag that ran it, in
+// the first stream (in image stream listing order) that has run a job by that
+// name. States from different streams are not compared by age.
+type MeOverview struct {
+	User      string            `json:"user"`
+	Streams   []StreamOverview  `json:"streams"`
+	JobStates map[string]string `json:"jobStates,omitempty"`
+}
+
+// httpMeOverview serves the authenticated user's personalized overview. See
+// MeOverview.
+func (c *Controller) httpMeOverview(w http.ResponseWriter, req *http.Request) {
+	user, ok := c.authenticatedUser(req)
+	if !ok {
+		http.Error(w, "no authenticated user; this instance is not configured with --auth-proxy-user-header, or the request did not carry it", http.StatusUnauthorized)
+		return
+	}
+	prefs, err := c.userPreferences(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	overview := MeOverview{User: user}
+	if len(prefs.StarredStreams) == 0 && len(prefs.StarredJobs) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		data, _ := json.MarshalIndent(overview, "", "  ")
+		w.Write(data)
+		return
+	}
+
+	starredStreams := make(map[string]bool, len(prefs.StarredStreams))
+	for _, name := range prefs.StarredStreams {
+		starredStreams[name] = true
+	}
+	starredJobs := make(map[string]bool, len(prefs.StarredJobs))
+	for _, name := range prefs.StarredJobs {
+		starredJobs[name] = true
+	}
+
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(starredJobs) > 0 {
+		overview.JobStates = make(map[string]string)
+	}
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if starredStreams[r.Config.Name] {
+			if full, err := c.renderOverview(); err == nil {
+				var all []StreamOverview
+				if json.Unmarshal(full, &all) == nil {
+					for _, entry := range all {
+						if entry.Name == r.Config.Name {
+							overview.Streams = append(overview.Streams, entry)
+						}
+					}
+				}
+			}
+		}
+		if len(starredJobs) == 0 {
+			continue
+		}
+		for _, tag := range tagsForRelease(r) {
+			status, err := decodeVerificationStatus(tag)
+			if err != nil {
+				continue
+			}
+			for job := range starredJobs {
+				if _, recorded := overview.JobStates[job]; recorded {
+					continue
+				}
+				if s, ok := status[job]; ok {
+					overview.JobStates[job] = s.State
+				}
+			}
+		}
+	}
+
+	sort.Slice(overview.Streams, func(i, j int) bool { return overview.Streams[i].Name < overview.Streams[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(overview, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+// renderMyStreamsBanner writes a short links banner for the authenticated
+// user's starred streams at the top of the dashboard HTML page. It writes
+// nothing if no user is authenticated or the user has no starred streams, so
+// it's safe to call unconditionally from httpReleases.
+func (c *Controller) renderMyStreamsBanner(w http.ResponseWriter, req *http.Request) {
+	user, ok := c.authenticatedUser(req)
+	if !ok {
+		return
+	}
+	prefs, err := c.userPreferences(user)
+	if err != nil || len(prefs.StarredStreams) == 0 {
+		return
+	}
+	fmt.Fprintf(w, `<p>My streams (%s):`, template.HTMLEscapeString(user))
+	for i, name := range prefs.StarredStreams {
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, ` <a href="/releasestream/%s">%s</a>`, name, template.HTMLEscapeString(name))
+	}
+	fmt.Fprintln(w, `</p>`)
+}
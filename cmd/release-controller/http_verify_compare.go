@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// VerificationComparison is the per-verification-name result of comparing two
+// tags' verification status, identifying regressions and fixes between them.
+type VerificationComparison struct {
+	A string `json:"a,omitempty"`
+	B string `json:"b,omitempty"`
+}
+
+// VerificationComparisonResult is the response body for httpCompareVerify.
+type VerificationComparisonResult struct {
+	A string `json:"a"`
+	B string `json:"b"`
+
+	// Verifications maps each verification name present on either tag to its
+	// state in a and in b.
+	Verifications map[string]VerificationComparison `json:"verifications"`
+	// NewlyFailing lists verification names that succeeded on a but failed on b.
+	NewlyFailing []string `json:"newlyFailing,omitempty"`
+	// NewlyPassing lists verification names that failed on a but succeeded on b.
+	NewlyPassing []string `json:"newlyPassing,omitempty"`
+}
+
+func decodeVerificationStatus(tag *imagev1.TagReference) (VerificationStatusMap, error) {
+	data := tag.Annotations[releaseAnnotationVerify]
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var status VerificationStatusMap
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// httpCompareVerify compares the verification status of two tags, named by
+// the a and b query parameters, so that regressions introduced between
+// consecutive builds (e.g. nightlies) can be identified programmatically.
+func (c *Controller) httpCompareVerify(w http.ResponseWriter, req *http.Request) {
+	a := req.URL.Query().Get("a")
+	b := req.URL.Query().Get("b")
+	if len(a) == 0 || len(b) == 0 {
+		http.Error(w, "a and b must both be set to valid tag names", http.StatusBadRequest)
+		return
+	}
+
+	tags, ok := c.findReleaseStreamTags(true, a, b)
+	if !ok {
+		for k, v := range tags {
+			if v == nil {
+				http.Error(w, fmt.Sprintf("could not find tag: %s", k), http.StatusNotFound)
+				return
+			}
+		}
+	}
+
+	statusA, err := decodeVerificationStatus(tags[a].Tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("tag %s has invalid verification status: %v", a, err), http.StatusInternalServerError)
+		return
+	}
+	statusB, err := decodeVerificationStatus(tags[b].Tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("tag %s has invalid verification status: %v", b, err), http.StatusInternalServerError)
+		return
+	}
+
+	names := sets.NewString()
+	for name := range statusA {
+		names.Insert(name)
+	}
+	for name := range statusB {
+		names.Insert(name)
+	}
+
+	result := VerificationComparisonResult{
+		A:             a,
+		B:             b,
+		Verifications: make(map[string]VerificationComparison, names.Len()),
+	}
+	for _, name := range names.List() {
+		var stateA, stateB string
+		if s, ok := statusA[name]; ok {
+			stateA = s.State
+		}
+		if s, ok := statusB[name]; ok {
+			stateB = s.State
+		}
+		result.Verifications[name] = VerificationComparison{A: stateA, B: stateB}
+
+		switch {
+		case stateA == releaseVerificationStateSucceeded && stateB == releaseVerificationStateFailed:
+			result.NewlyFailing = append(result.NewlyFailing, name)
+		case stateA == releaseVerificationStateFailed && stateB == releaseVerificationStateSucceeded:
+			result.NewlyPassing = append(result.NewlyPassing, name)
+		}
+	}
+	sort.Strings(result.NewlyFailing)
+	sort.Strings(result.NewlyPassing)
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// httpVerificationJobInputs returns the VerificationJobInputs snapshot
+// recorded on a verification ProwJob at creation time, so a result can still
+// be interpreted against the inputs that produced it even after the
+// periodic job config has since changed.
+func (c *Controller) httpVerificationJobInputs(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName, verification := vars["release"], vars["tag"], vars["verification"]
+
+	streams, ok := c.findReleaseByName(true, streamName)
+	if !ok || streams[streamName] == nil {
+		http.Error(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	if findTagReference(streams[streamName].Release.Target, tagName) == nil {
+		http.Error(w, errStreamTagNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	prowJobName := fmt.Sprintf("%s-%s", tagName, verification)
+	obj, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, fmt.Sprintf("no verification job named %s has run for this tag", verification), http.StatusNotFound)
+		return
+	}
+	data := obj.(*unstructured.Unstructured).GetAnnotations()[releaseAnnotationVerifyInputs]
+	if len(data) == 0 {
+		http.Error(w, "no input snapshot was recorded for this verification job", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, data)
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -38,6 +40,20 @@ func NewCachingReleaseInfo(info ReleaseInfo, size int64) ReleaseInfo {
 			s, err = info.ChangeLog(parts[1], parts[2])
 		case "releaseinfo":
 			s, err = info.ReleaseInfo(parts[1])
+		case "archdigest":
+			s, err = info.ArchDigest(parts[1], parts[2])
+		case "imagelayers":
+			var layers []ImageLayer
+			layers, err = info.ImageLayers(parts[1])
+			if err == nil {
+				s = toJSONString(layers)
+			}
+		case "imagecreated":
+			var created time.Time
+			created, err = info.ImageCreated(parts[1])
+			if err == nil {
+				s = created.Format(time.RFC3339)
+			}
 		}
 		if err != nil {
 			return err
@@ -65,9 +81,65 @@ func (c *CachingReleaseInfo) ReleaseInfo(image string) (string, error) {
 	return s, err
 }
 
+func (c *CachingReleaseInfo) ArchDigest(image, arch string) (string, error) {
+	if strings.Contains(image, "\x00") || strings.Contains(arch, "\x00") {
+		return "", fmt.Errorf("invalid image/arch")
+	}
+	var s string
+	err := c.cache.Get(context.TODO(), strings.Join([]string{"archdigest", image, arch}, "\x00"), groupcache.StringSink(&s))
+	return s, err
+}
+
+// ImageLayer is a subset of an image manifest layer descriptor, sufficient
+// to measure how much a component image changed between two releases.
+type ImageLayer struct {
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+func (c *CachingReleaseInfo) ImageLayers(image string) ([]ImageLayer, error) {
+	if strings.Contains(image, "\x00") {
+		return nil, fmt.Errorf("invalid image")
+	}
+	var s string
+	if err := c.cache.Get(context.TODO(), strings.Join([]string{"imagelayers", image}, "\x00"), groupcache.StringSink(&s)); err != nil {
+		return nil, err
+	}
+	var layers []ImageLayer
+	if err := json.Unmarshal([]byte(s), &layers); err != nil {
+		return nil, fmt.Errorf("could not parse cached image layers for %s: %v", image, err)
+	}
+	return layers, nil
+}
+
+func (c *CachingReleaseInfo) ImageCreated(image string) (time.Time, error) {
+	if strings.Contains(image, "\x00") {
+		return time.Time{}, fmt.Errorf("invalid image")
+	}
+	var s string
+	if err := c.cache.Get(context.TODO(), strings.Join([]string{"imagecreated", image}, "\x00"), groupcache.StringSink(&s)); err != nil {
+		return time.Time{}, err
+	}
+	created, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse cached image created time for %s: %v", image, err)
+	}
+	return created, nil
+}
+
 type ReleaseInfo interface {
 	ChangeLog(from, to string) (string, error)
 	ReleaseInfo(image string) (string, error)
+	// ArchDigest resolves image, which may point at a manifest list, to the
+	// digest of the manifest matching arch (e.g. "arm64", "s390x").
+	ArchDigest(image, arch string) (string, error)
+	// ImageLayers returns image's manifest layer digests and sizes, used to
+	// measure how much of a component image changed between two releases.
+	ImageLayers(image string) ([]ImageLayer, error)
+	// ImageCreated returns image's build time, used to gate acceptance on
+	// every payload component being old enough for component-level CI to
+	// have had a chance to flag it. See ReleaseVerification.MinComponentAge.
+	ImageCreated(image string) (time.Time, error)
 }
 
 type ExecReleaseInfo struct {
@@ -121,6 +193,135 @@ func (r *ExecReleaseInfo) ReleaseInfo(image string) (string, error) {
 	return out.String(), nil
 }
 
+func (r *ExecReleaseInfo) ArchDigest(image, arch string) (string, error) {
+	if _, err := imagereference.Parse(image); err != nil {
+		return "", fmt.Errorf("%s is not an image reference: %v", image, err)
+	}
+	cmd := []string{"oc", "image", "info", "--filter-by-os", arch, "-o", "json", image}
+
+	u := r.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(r.namespace).Name("git-cache-0").SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: "git",
+		Stdout:    true,
+		Stderr:    true,
+		Command:   cmd,
+	}, scheme.ParameterCodec).URL()
+
+	e, err := remotecommand.NewSPDYExecutor(r.restConfig, "POST", u)
+	if err != nil {
+		return "", fmt.Errorf("could not initialize a new SPDY executor: %v", err)
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := e.Stream(remotecommand.StreamOptions{
+		Stdout: out,
+		Stdin:  nil,
+		Stderr: errOut,
+	}); err != nil {
+		glog.V(4).Infof("Failed to get %s digest for %s: %v\n$ %s\n%s\n%s", arch, image, err, strings.Join(cmd, " "), errOut.String(), out.String())
+		msg := errOut.String()
+		if len(msg) == 0 {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("could not get %s digest for %s: %v", arch, image, msg)
+	}
+	var info struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		return "", fmt.Errorf("could not parse image info for %s: %v", image, err)
+	}
+	if len(info.Digest) == 0 {
+		return "", fmt.Errorf("no digest found for %s (%s)", image, arch)
+	}
+	return info.Digest, nil
+}
+
+// ImageLayers shells out to `oc image info`, the same tool ArchDigest uses,
+// to read image's manifest layers without this tree vendoring a registry
+// client of its own.
+func (r *ExecReleaseInfo) ImageLayers(image string) ([]ImageLayer, error) {
+	if _, err := imagereference.Parse(image); err != nil {
+		return nil, fmt.Errorf("%s is not an image reference: %v", image, err)
+	}
+	cmd := []string{"oc", "image", "info", "-o", "json", image}
+
+	u := r.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(r.namespace).Name("git-cache-0").SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: "git",
+		Stdout:    true,
+		Stderr:    true,
+		Command:   cmd,
+	}, scheme.ParameterCodec).URL()
+
+	e, err := remotecommand.NewSPDYExecutor(r.restConfig, "POST", u)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize a new SPDY executor: %v", err)
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := e.Stream(remotecommand.StreamOptions{
+		Stdout: out,
+		Stdin:  nil,
+		Stderr: errOut,
+	}); err != nil {
+		glog.V(4).Infof("Failed to get image layers for %s: %v\n$ %s\n%s\n%s", image, err, strings.Join(cmd, " "), errOut.String(), out.String())
+		msg := errOut.String()
+		if len(msg) == 0 {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("could not get image layers for %s: %v", image, msg)
+	}
+	var info struct {
+		Layers []ImageLayer `json:"layers"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		return nil, fmt.Errorf("could not parse image info for %s: %v", image, err)
+	}
+	return info.Layers, nil
+}
+
+// ImageCreated shells out to `oc image info`, the same tool ArchDigest and
+// ImageLayers use, to read image's build time without this tree vendoring a
+// registry client of its own.
+func (r *ExecReleaseInfo) ImageCreated(image string) (time.Time, error) {
+	if _, err := imagereference.Parse(image); err != nil {
+		return time.Time{}, fmt.Errorf("%s is not an image reference: %v", image, err)
+	}
+	cmd := []string{"oc", "image", "info", "-o", "json", image}
+
+	u := r.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(r.namespace).Name("git-cache-0").SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: "git",
+		Stdout:    true,
+		Stderr:    true,
+		Command:   cmd,
+	}, scheme.ParameterCodec).URL()
+
+	e, err := remotecommand.NewSPDYExecutor(r.restConfig, "POST", u)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not initialize a new SPDY executor: %v", err)
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := e.Stream(remotecommand.StreamOptions{
+		Stdout: out,
+		Stdin:  nil,
+		Stderr: errOut,
+	}); err != nil {
+		glog.V(4).Infof("Failed to get image created time for %s: %v\n$ %s\n%s\n%s", image, err, strings.Join(cmd, " "), errOut.String(), out.String())
+		msg := errOut.String()
+		if len(msg) == 0 {
+			msg = err.Error()
+		}
+		return time.Time{}, fmt.Errorf("could not get image created time for %s: %v", image, msg)
+	}
+	var info struct {
+		Created time.Time `json:"created"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		return time.Time{}, fmt.Errorf("could not parse image info for %s: %v", image, err)
+	}
+	if info.Created.IsZero() {
+		return time.Time{}, fmt.Errorf("no created time found for %s", image)
+	}
+	return info.Created, nil
+}
+
 func (r *ExecReleaseInfo) ChangeLog(from, to string) (string, error) {
 	if _, err := imagereference.Parse(from); err != nil {
 		return "", fmt.Errorf("%s is not an image reference: %v", from, err)
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+)
+
+// MintKioskToken produces a signed, expiring token granting read-only access to
+// a single release stream's dashboard, for embedding on team TVs or sharing
+// with external partners without granting access to the rest of the instance.
+// Tokens are minted offline by whoever holds the --kiosk-secret value; there is
+// no minting endpoint, since producing one requires nothing the server has that
+// the secret holder doesn't already have.
+func MintKioskToken(secret, stream string, expires time.Time) string {
+	payload := fmt.Sprintf("%s|%d", stream, expires.Unix())
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signKioskPayload(secret, payload)
+}
+
+func signKioskPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateKioskToken reports the release stream token grants read access to,
+// and whether token is correctly signed by secret and not yet expired.
+func validateKioskToken(secret, token string) (string, bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	payload := string(payloadBytes)
+	if !hmac.Equal([]byte(signKioskPayload(secret, payload)), []byte(parts[1])) {
+		return "", false
+	}
+	fields := strings.SplitN(payload, "|", 2)
+	if len(fields) != 2 || len(fields[0]) == 0 {
+		return "", false
+	}
+	expires, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", false
+	}
+	if time.Now().After(time.Unix(expires, 0)) {
+		return "", false
+	}
+	return fields[0], true
+}
+
+// httpKioskDashboard validates a kiosk token and, if valid, serves the
+// candidate dashboard for the single release stream it is scoped to - the same
+// read-only view an authenticated user gets at
+// /releasestream/{release}/candidates.
+func (c *Controller) httpKioskDashboard(w http.ResponseWriter, req *http.Request) {
+	if len(c.kioskSecret) == 0 {
+		http.Error(w, "kiosk access is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	stream, ok := validateKioskToken(c.kioskSecret, mux.Vars(req)["token"])
+	if !ok {
+		http.Error(w, "invalid or expired kiosk token", http.StatusUnauthorized)
+		return
+	}
+	glog.V(4).Infof("Serving kiosk dashboard for release stream %s", stream)
+	c.httpReleaseCandidateList(w, mux.SetURLVars(req, map[string]string{"release": stream}))
+}
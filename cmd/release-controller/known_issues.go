@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// KnownIssue is a caveat attached to a tag, surfaced on the tag page and in the
+// latest/candidate API responses so consumers of an accepted payload can see why
+// it might still be risky to use. A tag can carry any number of these, stored as
+// the JSON-serialized releaseAnnotationKnownIssues annotation.
+type KnownIssue struct {
+	// Name identifies the issue. For auto-recorded issues this is the name of the
+	// verification step that failed; for hand-authored notes it is chosen by whoever
+	// added the note.
+	Name string `json:"name"`
+	// Message describes the issue.
+	Message string `json:"message"`
+	// Source is "manual" for a note an authorized user attached directly, or
+	// "verification" for one recorded automatically from a failed optional
+	// verification step.
+	Source string `json:"source"`
+}
+
+const (
+	knownIssueSourceManual       = "manual"
+	knownIssueSourceVerification = "verification"
+)
+
+// knownIssuesForTag returns the known issues already recorded on tag, if any.
+func knownIssuesForTag(tag *imagev1.TagReference) []KnownIssue {
+	if tag == nil {
+		return nil
+	}
+	raw := tag.Annotations[releaseAnnotationKnownIssues]
+	if len(raw) == 0 {
+		return nil
+	}
+	var issues []KnownIssue
+	if err := json.Unmarshal([]byte(raw), &issues); err != nil {
+		return nil
+	}
+	return issues
+}
+
+// knownIssuesFromFailedOptionalVerification derives known issues from verification
+// steps that failed but were Optional, and so did not block acceptance.
+func knownIssuesFromFailedOptionalVerification(verify map[string]ReleaseVerification, status VerificationStatusMap) []KnownIssue {
+	var issues []KnownIssue
+	for name, s := range status {
+		if s.State != releaseVerificationStateFailed {
+			continue
+		}
+		if v, ok := verify[name]; !ok || !v.Optional {
+			continue
+		}
+		issues = append(issues, KnownIssue{
+			Name:    name,
+			Message: "optional verification step failed",
+			Source:  knownIssueSourceVerification,
+		})
+	}
+	return issues
+}
+
+// mergeKnownIssues combines existing known issues with freshly computed ones,
+// keeping hand-authored notes untouched and replacing every previously recorded
+// automatic note with computed, so re-syncing a tag doesn't pile up duplicates;
+// an automatic note whose step no longer fails is dropped.
+func mergeKnownIssues(existing, computed []KnownIssue) []KnownIssue {
+	var merged []KnownIssue
+	for _, issue := range existing {
+		if issue.Source == knownIssueSourceManual {
+			merged = append(merged, issue)
+		}
+	}
+	merged = append(merged, computed...)
+	return merged
+}
+
+// encodeKnownIssues serializes issues for storage in releaseAnnotationKnownIssues,
+// returning an empty string for an empty list so the annotation is removed rather
+// than set to "[]" or "null".
+func encodeKnownIssues(issues []KnownIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	return toJSONString(issues)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// controllerLeaderElection tracks whether this replica currently holds the lease
+// that gates exclusive execution of GC and publish steps. isLeader is only ever
+// read or written through IsLeader and the leaderelection callbacks below, so it
+// is safe to read concurrently with the GC and sync workers.
+type controllerLeaderElection struct {
+	isLeader int32
+}
+
+// SetLeaderElection starts a leader election using a ConfigMap lock named name in
+// namespace, and gates garbageCollectSync and cross-namespace publish steps so
+// that only the replica holding the lease performs them at any given time. This
+// allows the controller to run with multiple replicas for availability while
+// guaranteeing GC and one-shot publish actions execute at most once per interval.
+// identity should be unique per process (e.g. the pod name); an empty identity
+// lets the leaderelection library generate one.
+//
+// If SetLeaderElection is never called, every replica is implicitly the leader,
+// which preserves the single-replica behavior this controller has always had.
+func (c *Controller) SetLeaderElection(client kubernetes.Interface, namespace, name, identity string) error {
+	lock, err := resourcelock.New(
+		resourcelock.ConfigMapsResourceLock,
+		namespace,
+		name,
+		client.CoreV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return err
+	}
+	le := &controllerLeaderElection{}
+	c.leaderElection = le
+	go func() {
+		for {
+			leaderelection.RunOrDie(context.Background(), leaderelection.LeaderElectionConfig{
+				Lock:          lock,
+				LeaseDuration: 30 * time.Second,
+				RenewDeadline: 15 * time.Second,
+				RetryPeriod:   5 * time.Second,
+				Callbacks: leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(ctx context.Context) {
+						glog.Infof("Became leader for %s/%s, GC and publish steps enabled", namespace, name)
+						atomic.StoreInt32(&le.isLeader, 1)
+					},
+					OnStoppedLeading: func() {
+						glog.Infof("Lost leadership for %s/%s, GC and publish steps disabled", namespace, name)
+						atomic.StoreInt32(&le.isLeader, 0)
+					},
+				},
+			})
+		}
+	}()
+	return nil
+}
+
+// IsLeader reports whether this replica is allowed to perform GC and
+// cross-namespace publish steps right now. See SetLeaderElection.
+func (c *Controller) IsLeader() bool {
+	if c.leaderElection == nil {
+		return true
+	}
+	return atomic.LoadInt32(&c.leaderElection.isLeader) == 1
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"net/url"
 	"os"
 	goruntime "runtime"
@@ -58,9 +60,32 @@ type options struct {
 	AuditGCSServiceAccount string
 	SigningKeyring         string
 	CLIImageForAudit       string
+	ExternalVerifySecret   string
+	KioskSecret            string
+	ExternalPayloadSecret  string
+	CloudEventsSink        string
+	DigestSink             string
+	DigestInterval         time.Duration
+	AuthProxyUserHeader    string
+	EnableReleaseConfigCRD bool
+	UpgradeGraphStorage    string
+
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+
+	LeaderElection          bool
+	LeaderElectionNamespace string
+	LeaderElectionName      string
 
 	DryRun       bool
+	GCDryRun     bool
+	EnablePprof  bool
 	LimitSources []string
+
+	ResyncIntegration time.Duration
+	ResyncStable      time.Duration
+	ResyncArchived    time.Duration
 }
 
 func main() {
@@ -73,7 +98,8 @@ func main() {
 	original.Set("v", "2")
 
 	opt := &options{
-		ListenAddr: ":8080",
+		ListenAddr:          ":8080",
+		UpgradeGraphStorage: string(graphStorageSecret),
 	}
 	cmd := &cobra.Command{
 		Run: func(cmd *cobra.Command, arguments []string) {
@@ -87,6 +113,23 @@ func main() {
 	}
 	flag := cmd.Flags()
 	flag.BoolVar(&opt.DryRun, "dry-run", opt.DryRun, "Perform no actions on the release streams")
+	flag.BoolVar(&opt.GCDryRun, "gc-dry-run", opt.GCDryRun, "Report what garbage collection would delete without deleting it. Has no effect if --dry-run is set.")
+	flag.BoolVar(&opt.EnablePprof, "enable-pprof", opt.EnablePprof, "Serve net/http/pprof profiles on the UI/metrics listen address.")
+	flag.StringVar(&opt.ExternalVerifySecret, "external-verify-secret", opt.ExternalVerifySecret, "Shared secret used to validate signed webhook requests reporting results for ReleaseVerification.External steps. If unset, the webhook endpoint is disabled.")
+	flag.StringVar(&opt.KioskSecret, "kiosk-secret", opt.KioskSecret, "Shared secret used to validate kiosk tokens minted with MintKioskToken, granting read-only access to a single release stream's dashboard at /kiosk/{token}. If unset, the kiosk endpoint is disabled.")
+	flag.StringVar(&opt.ExternalPayloadSecret, "external-payload-secret", opt.ExternalPayloadSecret, "Shared secret used to validate signed requests to register an externally hosted release pullspec for verification-only processing. If unset, the endpoint is disabled.")
+	flag.StringVar(&opt.CloudEventsSink, "cloudevents-sink", opt.CloudEventsSink, "HTTP(S) URL to POST CloudEvents-formatted (structured mode JSON) notifications of tag creation, phase transitions, verification completion, and publish completion. If unset, no events are emitted.")
+	flag.StringVar(&opt.DigestSink, "digest-sink", opt.DigestSink, "HTTP(S) URL to POST an aggregated NotificationDigest (accepted/rejected tags, top failing jobs, newly broken upgrade edges) once per --digest-interval, for high-churn streams that want one message per interval instead of one per event. If unset, no digests are sent.")
+	flag.DurationVar(&opt.DigestInterval, "digest-interval", opt.DigestInterval, "How often to aggregate and send a notification digest. Defaults to 24h.")
+	flag.StringVar(&opt.AuthProxyUserHeader, "auth-proxy-user-header", opt.AuthProxyUserHeader, "Name of the request header a trusted, authenticating reverse proxy in front of this service (e.g. an oauth-proxy sidecar terminating OIDC) sets to the logged in user's identity. If unset, per-user features (starred streams/jobs) are disabled.")
+	flag.BoolVar(&opt.EnableReleaseConfigCRD, "enable-release-config-crd", opt.EnableReleaseConfigCRD, "Watch for ReleaseConfig custom resources (releases.release.openshift.io) and prefer one over the release.openshift.io/config imagestream annotation when both exist for the same stream, allowing configs to be migrated off the annotation one stream at a time. The CRD must already be installed; this flag does not create it.")
+	flag.StringVar(&opt.UpgradeGraphStorage, "upgrade-graph-storage", opt.UpgradeGraphStorage, "Where to persist the upgrade graph across restarts: \"secret\" (a pre-existing release-upgrade-graph Secret, the default) or \"configmap\" (chunked release-upgrade-graph-* ConfigMaps, created on demand, for clusters that restrict large or long-lived Secrets).")
+	flag.StringVar(&opt.TLSCertFile, "tls-cert", opt.TLSCertFile, "Path to a PEM certificate to serve the UI/metrics listener over TLS. Must be set together with --tls-key. The certificate and key are reloaded automatically if their files change, so they can be rotated without a restart.")
+	flag.StringVar(&opt.TLSKeyFile, "tls-key", opt.TLSKeyFile, "Path to the PEM private key matching --tls-cert.")
+	flag.StringVar(&opt.TLSClientCAFile, "tls-client-ca", opt.TLSClientCAFile, "Path to a PEM bundle of CA certificates. If set, mutating requests (POST/PUT/PATCH/DELETE) must present a client certificate verified against this bundle; other requests are unaffected. Requires --tls-cert/--tls-key; setting it without them is an error.")
+	flag.BoolVar(&opt.LeaderElection, "leader-election", opt.LeaderElection, "Coordinate GC and cross-namespace publish steps across replicas using a leader lease, so that running more than one replica is safe.")
+	flag.StringVar(&opt.LeaderElectionNamespace, "leader-election-namespace", opt.LeaderElectionNamespace, "The namespace to create the leader election lock in. Defaults to the job namespace.")
+	flag.StringVar(&opt.LeaderElectionName, "leader-election-name", opt.LeaderElectionName, "The name of the leader election lock. Has no effect unless --leader-election is set.")
 	flag.StringVar(&opt.AuditStorage, "audit", opt.AuditStorage, "A storage location to report audit logs to, if specified. The location may be a file://path or gs:// GCS bucket and path.")
 	flag.StringVar(&opt.AuditGCSServiceAccount, "audit-gcs-service-account", opt.AuditGCSServiceAccount, "An optional path to a service account file that should be used for uploading audit information to GCS.")
 	flag.StringSliceVar(&opt.LimitSources, "only-source", opt.LimitSources, "The names of the image streams to operate on. Intended for testing.")
@@ -105,8 +148,14 @@ func main() {
 
 	flag.StringVar(&opt.ListenAddr, "listen", opt.ListenAddr, "The address to serve release information on")
 
+	flag.DurationVar(&opt.ResyncIntegration, "resync-integration", opt.ResyncIntegration, "Safety-net resync interval for Integration streams (0 keeps the default).")
+	flag.DurationVar(&opt.ResyncStable, "resync-stable", opt.ResyncStable, "Safety-net resync interval for Stable streams (0 keeps the default).")
+	flag.DurationVar(&opt.ResyncArchived, "resync-archived", opt.ResyncArchived, "Safety-net resync interval for Archived streams (0 keeps the default).")
+
 	flag.AddGoFlag(original.Lookup("v"))
 
+	cmd.AddCommand(newMigrateCommand())
+
 	if err := cmd.Execute(); err != nil {
 		klog.Exitf("error: %v", err)
 	}
@@ -189,6 +238,10 @@ func (o *options) Run() error {
 	execReleaseFiles := NewExecReleaseFiles(client, config, o.JobNamespace, fmt.Sprintf("%s", releaseNamespace), imageCache.Get)
 
 	graph := NewUpgradeGraph()
+	graphStore, err := newGraphStorage(graphStorageKind(o.UpgradeGraphStorage), client.CoreV1(), client.CoreV1(), releaseNamespace, "release-upgrade-graph")
+	if err != nil {
+		return fmt.Errorf("--upgrade-graph-storage: %v", err)
+	}
 
 	c := NewController(
 		client.Core(),
@@ -205,6 +258,36 @@ func (o *options) Run() error {
 		graph,
 	)
 
+	c.SetResyncInterval(streamClassIntegration, o.ResyncIntegration)
+	c.SetResyncInterval(streamClassStable, o.ResyncStable)
+	c.SetResyncInterval(streamClassArchived, o.ResyncArchived)
+	c.SetDryRun(o.DryRun)
+	c.SetGCDryRun(o.GCDryRun || o.DryRun)
+	c.SetClusterConfig(config)
+	c.SetConfigMapClient(client.Core())
+	c.SetSecretClient(client.Core())
+	c.SetExternalVerifySecret(o.ExternalVerifySecret)
+	c.SetKioskSecret(o.KioskSecret)
+	c.SetExternalPayloadSecret(o.ExternalPayloadSecret)
+	c.SetCloudEventsSink(o.CloudEventsSink)
+	c.SetDigestSink(o.DigestSink)
+	c.SetAuthProxyUserHeader(o.AuthProxyUserHeader)
+	c.SetDigestInterval(o.DigestInterval)
+
+	if o.LeaderElection {
+		leaderElectionNamespace := o.LeaderElectionNamespace
+		if len(leaderElectionNamespace) == 0 {
+			leaderElectionNamespace = o.JobNamespace
+		}
+		leaderElectionName := o.LeaderElectionName
+		if len(leaderElectionName) == 0 {
+			leaderElectionName = "release-controller-leader"
+		}
+		if err := c.SetLeaderElection(client, leaderElectionNamespace, leaderElectionName, os.Getenv("HOSTNAME")); err != nil {
+			return fmt.Errorf("unable to start leader election: %v", err)
+		}
+	}
+
 	if len(o.AuditStorage) > 0 {
 		u, err := url.Parse(o.AuditStorage)
 		if err != nil {
@@ -253,16 +336,57 @@ func (o *options) Run() error {
 		c.signer = signer
 	}
 
+	if (len(o.TLSCertFile) > 0) != (len(o.TLSKeyFile) > 0) {
+		return fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+	if len(o.TLSClientCAFile) > 0 && len(o.TLSCertFile) == 0 {
+		return fmt.Errorf("--tls-client-ca has no effect without --tls-cert/--tls-key")
+	}
+
 	if len(o.ListenAddr) > 0 {
-		http.DefaultServeMux.Handle("/metrics", promhttp.Handler())
-		http.DefaultServeMux.HandleFunc("/graph", c.graphHandler)
-		http.DefaultServeMux.Handle("/", c.userInterfaceHandler())
-		go func() {
-			klog.Infof("Listening on %s for UI and metrics", o.ListenAddr)
-			if err := http.ListenAndServe(o.ListenAddr, nil); err != nil {
-				klog.Exitf("Server exited: %v", err)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/graph", c.graphHandler)
+		if o.EnablePprof {
+			mux.HandleFunc("/debug/pprof/", pprof.Index)
+			mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+			mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+			mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		}
+		mux.Handle("/", c.userInterfaceHandler())
+
+		server := &http.Server{Addr: o.ListenAddr, Handler: mux}
+		if len(o.TLSCertFile) > 0 {
+			cert, err := newReloadableCertificate(o.TLSCertFile, o.TLSKeyFile)
+			if err != nil {
+				return fmt.Errorf("unable to load --tls-cert/--tls-key: %v", err)
 			}
-		}()
+			tlsConfig := &tls.Config{GetCertificate: cert.GetCertificate}
+			if len(o.TLSClientCAFile) > 0 {
+				pool, err := loadClientCAPool(o.TLSClientCAFile)
+				if err != nil {
+					return fmt.Errorf("unable to load --tls-client-ca: %v", err)
+				}
+				tlsConfig.ClientCAs = pool
+				tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+				c.SetRequireClientCertForMutations(true)
+			}
+			server.TLSConfig = tlsConfig
+			go func() {
+				klog.Infof("Listening on %s for UI and metrics (TLS)", o.ListenAddr)
+				if err := server.ListenAndServeTLS("", ""); err != nil {
+					klog.Exitf("Server exited: %v", err)
+				}
+			}()
+		} else {
+			go func() {
+				klog.Infof("Listening on %s for UI and metrics", o.ListenAddr)
+				if err := server.ListenAndServe(); err != nil {
+					klog.Exitf("Server exited: %v", err)
+				}
+			}()
+		}
 	}
 
 	batchFactory.Start(stopCh)
@@ -277,6 +401,14 @@ func (o *options) Run() error {
 	}
 	imageCache.SetLister(c.imageStreamLister.ImageStreams(releaseNamespace))
 
+	if o.EnableReleaseConfigCRD {
+		releaseConfigClient := dynamicClient.Resource(releaseConfigCRDResource)
+		releaseConfigInformer := newDynamicSharedIndexInformer(releaseConfigClient, metav1.NamespaceAll, 10*time.Minute, labels.Everything())
+		hasSynced = append(hasSynced, releaseConfigInformer.HasSynced)
+		c.AddReleaseConfigInformer(releaseConfigInformer)
+		go releaseConfigInformer.Run(stopCh)
+	}
+
 	if len(o.ProwConfigPath) > 0 {
 		prowInformers := newDynamicSharedIndexInformer(prowClient, o.ProwNamespace, 10*time.Minute, labels.SelectorFromSet(labels.Set{"release.openshift.io/verify": "true"}))
 		hasSynced = append(hasSynced, prowInformers.HasSynced)
@@ -287,24 +419,8 @@ func (o *options) Run() error {
 			index := prowInformers.GetIndexer()
 			cache.WaitForCacheSync(stopCh, prowInformers.HasSynced)
 			wait.Until(func() {
-				for _, item := range index.List() {
-					job, ok := item.(*unstructured.Unstructured)
-					if !ok {
-						continue
-					}
-					from, ok := job.GetAnnotations()[releaseAnnotationFromTag]
-					if !ok {
-						continue
-					}
-					to, ok := job.GetAnnotations()[releaseAnnotationToTag]
-					if !ok {
-						continue
-					}
-					status, ok := prowJobVerificationStatus(job)
-					if !ok {
-						continue
-					}
-					graph.Add(from, to, UpgradeResult(*status))
+				if err := rebuildGraphFromProwJobs(index, graph, 0, nil); err != nil {
+					klog.Errorf("Unable to scan prow jobs into the upgrade graph: %v", err)
 				}
 			}, 2*time.Minute, stopCh)
 		}()
@@ -315,18 +431,18 @@ func (o *options) Run() error {
 
 	switch {
 	case o.DryRun:
-		klog.Infof("Dry run mode (no changes will be made)")
+		klog.Infof("Dry run mode: running the full sync loop, but logging rather than making any changes")
 
 		// read the graph
-		go syncGraphToSecret(graph, false, client.CoreV1().Secrets(releaseNamespace), releaseNamespace, "release-upgrade-graph", stopCh)
+		go syncGraphToStorage(graph, false, graphStore, stopCh)
 
-		<-stopCh
+		c.RunSync(3, stopCh)
 		return nil
 	case len(o.AuditStorage) > 0:
 		klog.Infof("Auditing releases to %s", o.AuditStorage)
 
 		// read the graph
-		go syncGraphToSecret(graph, false, client.CoreV1().Secrets(releaseNamespace), releaseNamespace, "release-upgrade-graph", stopCh)
+		go syncGraphToStorage(graph, false, graphStore, stopCh)
 
 		c.RunAudit(2, stopCh)
 		return nil
@@ -334,7 +450,7 @@ func (o *options) Run() error {
 		klog.Infof("Managing only %s, no garbage collection", o.LimitSources)
 
 		// read the graph
-		go syncGraphToSecret(graph, false, client.CoreV1().Secrets(releaseNamespace), releaseNamespace, "release-upgrade-graph", stopCh)
+		go syncGraphToStorage(graph, false, graphStore, stopCh)
 
 		c.RunSync(3, stopCh)
 		return nil
@@ -342,7 +458,7 @@ func (o *options) Run() error {
 		klog.Infof("Managing releases")
 
 		// keep the graph in a more persistent form
-		go syncGraphToSecret(graph, true, client.CoreV1().Secrets(releaseNamespace), releaseNamespace, "release-upgrade-graph", stopCh)
+		go syncGraphToStorage(graph, true, graphStore, stopCh)
 		// maintain the release pods
 		go refreshReleaseToolsEvery(2*time.Hour, execReleaseInfo, execReleaseFiles, stopCh)
 
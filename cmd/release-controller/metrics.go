@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	syncDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "release_controller_sync_duration_seconds",
+		Help:    "Time it took to sync a single release stream.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"stream"})
+
+	annotationCodecDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "release_controller_annotation_codec_duration_seconds",
+		Help:    "Time spent encoding or decoding annotation values such as verification status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	imageStreamUpdateConflictsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "release_controller_imagestream_update_conflicts_total",
+		Help: "Number of times an image stream update was rejected because of a resource version conflict.",
+	}, []string{"namespace", "name"})
+
+	releaseStreamSLABreached = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "release_controller_stream_sla_breached",
+		Help: "Whether a release stream with a configured freshness SLA (expectAcceptedEvery) currently has no Accepted release within that window (1) or is within SLA (0).",
+	}, []string{"stream"})
+
+	releaseStreamStorageBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "release_controller_stream_storage_bytes",
+		Help: "Registry storage in bytes attributable to a release stream's tags, as last computed by the storage reporter.",
+	}, []string{"stream"})
+
+	releaseStreamStorageQuotaBreached = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "release_controller_stream_storage_quota_breached",
+		Help: "Whether a release stream with a configured storageQuotaBytes currently exceeds it (1) or is within quota (0).",
+	}, []string{"stream"})
+
+	parseReleaseConfigTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "release_controller_release_config_parse_total",
+		Help: "Number of calls to parse a release config annotation, broken down by whether the parsed result was served from parsedReleaseConfigCache (hit) or freshly unmarshaled (miss).",
+	}, []string{"result"})
+
+	htmlPageCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "release_controller_html_page_cache_total",
+		Help: "Number of requests for a cached dashboard or release tag HTML page, broken down by whether the page was served from htmlPageCache (hit) or freshly rendered (miss).",
+	}, []string{"result"})
+
+	configMapJanitorObjectsTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "release_controller_configmap_janitor_objects",
+		Help: "Number of controller-owned ConfigMaps tracked by the ConfigMap janitor, by kind, as of the last garbage collection pass.",
+	}, []string{"kind"})
+)
+
+func init() {
+	prometheus.MustRegister(syncDurationSeconds, annotationCodecDurationSeconds, imageStreamUpdateConflictsTotal, releaseStreamSLABreached, releaseStreamStorageBytes, releaseStreamStorageQuotaBreached, parseReleaseConfigTotal, htmlPageCacheTotal, configMapJanitorObjectsTotal)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imageclientset "github.com/openshift/client-go/image/clientset/versioned"
+)
+
+// annotationMigration rewrites a single release tag's annotations from one schema
+// version to another. It must be idempotent: running it again on an
+// already-migrated tag should report no change, since a partial migration run may
+// be resumed by running the tool again.
+type annotationMigration func(tag *imagev1.TagReference) (changed bool)
+
+// annotationSchemaMigrations holds every known "<from>->[<to>]" annotation schema
+// rewrite. New entries should be added here whenever a status annotation's format
+// changes (see ReleaseVerification.External, ExpectAcceptedEvery, and similar
+// additions), so operators have a safe way to backfill old tags rather than
+// leaving them in a format the running controller no longer understands.
+var annotationSchemaMigrations = map[string]annotationMigration{
+	"v1->v2": migrateVerifyAnnotationV1ToV2,
+}
+
+// migrateVerifyAnnotationV1ToV2 rewrites the legacy verify annotation format (a
+// comma-separated list of "name=State" pairs, with no URL) into the current
+// VerificationStatusMap JSON format. Tags already in the JSON format, or with no
+// verify annotation at all, are left untouched.
+func migrateVerifyAnnotationV1ToV2(tag *imagev1.TagReference) bool {
+	data := tag.Annotations[releaseAnnotationVerify]
+	if len(data) == 0 || strings.HasPrefix(strings.TrimSpace(data), "{") {
+		return false
+	}
+	status := make(VerificationStatusMap)
+	for _, pair := range strings.Split(data, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			continue
+		}
+		status[parts[0]] = &VerificationStatus{State: parts[1]}
+	}
+	if len(status) == 0 {
+		return false
+	}
+	tag.Annotations[releaseAnnotationVerify] = toJSONString(status)
+	return true
+}
+
+type migrateOptions struct {
+	ReleaseNamespaces []string
+	From              string
+	To                string
+	DryRun            bool
+}
+
+func newMigrateCommand() *cobra.Command {
+	o := &migrateOptions{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite release tag annotations from one schema version to another",
+		Long: "migrate rewrites annotations (such as the verify status map) on every release tag " +
+			"in the given namespaces from the --from schema version to the --to schema version. " +
+			"It is safe to run repeatedly: already-migrated tags are left untouched.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringSliceVar(&o.ReleaseNamespaces, "release-namespace", o.ReleaseNamespaces, "The namespace(s) containing release image streams to migrate.")
+	flags.StringVar(&o.From, "from", o.From, "The schema version currently in use (e.g. v1).")
+	flags.StringVar(&o.To, "to", o.To, "The schema version to migrate to (e.g. v2).")
+	flags.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Report what would be migrated without making any changes.")
+	return cmd
+}
+
+func (o *migrateOptions) Run() error {
+	if len(o.ReleaseNamespaces) == 0 {
+		return fmt.Errorf("no namespace set, use --release-namespace")
+	}
+	if len(o.From) == 0 || len(o.To) == 0 {
+		return fmt.Errorf("both --from and --to schema versions are required")
+	}
+	migrate, ok := annotationSchemaMigrations[fmt.Sprintf("%s->%s", o.From, o.To)]
+	if !ok {
+		return fmt.Errorf("no migration registered from schema %s to %s", o.From, o.To)
+	}
+
+	config, _, _, err := loadClusterConfig()
+	if err != nil {
+		return err
+	}
+	imageClient, err := imageclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to create client: %v", err)
+	}
+
+	var streamsChanged, tagsChanged, tagsTotal int
+	for _, ns := range o.ReleaseNamespaces {
+		streams, err := imageClient.Image().ImageStreams(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to list image streams in %s: %v", ns, err)
+		}
+		for i := range streams.Items {
+			stream := &streams.Items[i]
+			var changedHere int
+			for j := range stream.Spec.Tags {
+				tag := &stream.Spec.Tags[j]
+				tagsTotal++
+				if migrate(tag) {
+					changedHere++
+				}
+			}
+			if changedHere == 0 {
+				continue
+			}
+			tagsChanged += changedHere
+			streamsChanged++
+			if o.DryRun {
+				glog.Infof("Would migrate %d tag(s) in %s/%s from %s to %s", changedHere, ns, stream.Name, o.From, o.To)
+				continue
+			}
+			if _, err := imageClient.Image().ImageStreams(ns).Update(stream); err != nil {
+				return fmt.Errorf("unable to update image stream %s/%s: %v", ns, stream.Name, err)
+			}
+			glog.Infof("Migrated %d tag(s) in %s/%s from %s to %s", changedHere, ns, stream.Name, o.From, o.To)
+		}
+	}
+
+	if o.DryRun {
+		glog.Infof("Dry run complete: %d/%d tags across %d stream(s) would be migrated from %s to %s", tagsChanged, tagsTotal, streamsChanged, o.From, o.To)
+	} else {
+		glog.Infof("Migration complete: %d/%d tags across %d stream(s) migrated from %s to %s", tagsChanged, tagsTotal, streamsChanged, o.From, o.To)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// notificationPhases is the default set of phases a NotificationConfig fires
+// on when it does not set Phases explicitly.
+var notificationPhases = []string{releasePhaseReady, releasePhaseAccepted, releasePhaseRejected, releasePhaseFailed}
+
+var notificationClient = &http.Client{Timeout: 10 * time.Second}
+
+// NotificationEvent describes a single tag phase transition, enough to
+// render a message for any provider in notificationFormatters.
+type NotificationEvent struct {
+	Release string
+	Tag     string
+	Phase   string
+	Reason  string
+	Message string
+	// FailedVerifications maps verify step name to its job URL, for Rejected
+	// or Failed phases.
+	FailedVerifications map[string]string
+}
+
+// notificationFormatters renders a NotificationEvent into the body and
+// content type POSTed to a provider's incoming webhook. Adding a provider is
+// just adding an entry here and to the provider name validated in
+// parseReleaseConfig.
+var notificationFormatters = map[string]func(NotificationEvent) ([]byte, string, error){
+	"slack": formatSlackNotification,
+	"teams": formatTeamsNotification,
+}
+
+func notificationSummary(event NotificationEvent) string {
+	summary := fmt.Sprintf("Release %s tag %s is now %s", event.Release, event.Tag, event.Phase)
+	if len(event.Message) > 0 {
+		summary += ": " + event.Message
+	}
+	return summary
+}
+
+// formatSlackNotification renders event as a Slack incoming-webhook message
+// (https://api.slack.com/messaging/webhooks).
+func formatSlackNotification(event NotificationEvent) ([]byte, string, error) {
+	text := notificationSummary(event)
+	for name, url := range event.FailedVerifications {
+		text += fmt.Sprintf("\n• <%s|%s> failed", url, name)
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	return body, "application/json", err
+}
+
+// formatTeamsNotification renders event as a legacy Microsoft Teams
+// connector message card
+// (https://learn.microsoft.com/en-us/outlook/actionable-messages/message-card-reference).
+func formatTeamsNotification(event NotificationEvent) ([]byte, string, error) {
+	text := notificationSummary(event)
+	for name, url := range event.FailedVerifications {
+		text += fmt.Sprintf("\n\n[%s](%s) failed", name, url)
+	}
+	themeColor := "0076D7"
+	switch event.Phase {
+	case releasePhaseAccepted, releasePhaseReady:
+		themeColor = "2EB886"
+	case releasePhaseRejected, releasePhaseFailed:
+		themeColor = "D00000"
+	}
+	card := map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "http://schema.org/extensions",
+		"summary":    notificationSummary(event),
+		"themeColor": themeColor,
+		"title":      fmt.Sprintf("%s: %s", event.Release, event.Phase),
+		"text":       text,
+	}
+	body, err := json.Marshal(card)
+	return body, "application/json", err
+}
+
+// notificationWebhookURL resolves the URL a NotificationConfig should POST
+// to, fetching it from the "webhookUrl" key of SecretName's Secret if URL
+// itself was not set.
+func (c *Controller) notificationWebhookURL(cfg NotificationConfig) (string, error) {
+	if len(cfg.URL) > 0 {
+		return cfg.URL, nil
+	}
+	secret, err := c.webhookPublishSecret(cfg.SecretName)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// notificationEventForTag builds the NotificationEvent for a tag that just
+// transitioned to phase, pulling its reason/message annotations and, for
+// Rejected or Failed, the URLs of its failed verification steps off of
+// release.Target (which has already been updated to reflect the transition).
+func (c *Controller) notificationEventForTag(release *Release, name, phase string) NotificationEvent {
+	event := NotificationEvent{Release: release.Config.Name, Tag: name, Phase: phase}
+	tag := findTagReference(release.Target, name)
+	if tag == nil {
+		return event
+	}
+	event.Reason = tag.Annotations[releaseAnnotationReason]
+	event.Message = tag.Annotations[releaseAnnotationMessage]
+	if phase == releasePhaseRejected || phase == releasePhaseFailed {
+		if status, err := decodeVerificationStatus(tag); err == nil {
+			for name, s := range status {
+				if s.State == releaseVerificationStateFailed {
+					if event.FailedVerifications == nil {
+						event.FailedVerifications = make(map[string]string)
+					}
+					event.FailedVerifications[name] = s.URL
+				}
+			}
+		}
+	}
+	return event
+}
+
+// notifyPhaseTransition sends event to every NotificationConfig on release
+// that opts into event.Phase. Like emitCloudEvent, delivery is best-effort
+// and happens off the sync loop's goroutine: a slow or unreachable chat
+// webhook must never hold up a phase transition, and a missed chat message
+// is not worth rolling back a tag's already-recorded phase for.
+func (c *Controller) notifyPhaseTransition(release *Release, event NotificationEvent) {
+	for _, cfg := range release.Config.Notifications {
+		phases := cfg.Phases
+		if len(phases) == 0 {
+			phases = notificationPhases
+		}
+		if !containsString(phases, event.Phase) {
+			continue
+		}
+		formatter, ok := notificationFormatters[cfg.Provider]
+		if !ok {
+			glog.Errorf("Release %s has a notification with unknown provider %q", release.Config.Name, cfg.Provider)
+			continue
+		}
+		body, contentType, err := formatter(event)
+		if err != nil {
+			glog.Errorf("Unable to format %s notification for %s: %v", cfg.Provider, event.Tag, err)
+			continue
+		}
+		url, err := c.notificationWebhookURL(cfg)
+		if err != nil {
+			glog.Errorf("Unable to resolve webhook URL for %s notification on %s: %v", cfg.Provider, release.Config.Name, err)
+			continue
+		}
+		go func(provider, url string, body []byte, contentType string) {
+			resp, err := notificationClient.Post(url, contentType, bytes.NewReader(body))
+			if err != nil {
+				glog.V(2).Infof("Unable to deliver %s notification for %s: %v", provider, event.Tag, err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				glog.V(2).Infof("%s notification webhook rejected %s with status %s", provider, event.Tag, resp.Status)
+			}
+		}(cfg.Provider, url, body, contentType)
+	}
+}
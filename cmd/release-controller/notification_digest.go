@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// defaultDigestInterval is how often digestLoop aggregates and sends a
+// notification digest when --digest-interval isn't set. A day matches the
+// "daily/shift" cadence the feature is meant for; a shorter interval can be
+// configured for streams wanting tighter digests.
+const defaultDigestInterval = 24 * time.Hour
+
+var digestHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// DigestJobFailureCount is a verification job name and how many rejected
+// tags it was responsible for within a digest window.
+type DigestJobFailureCount struct {
+	Name     string `json:"name"`
+	Failures int    `json:"failures"`
+}
+
+// StreamDigest aggregates one release stream's activity over a digest
+// window, so it can be delivered as a single notification instead of one
+// per accepted tag, rejected tag, and failing job.
+type StreamDigest struct {
+	Stream   string    `json:"stream"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+	Accepted []string  `json:"accepted,omitempty"`
+	Rejected []string  `json:"rejected,omitempty"`
+	// TopFailingJobs lists the verification jobs most often responsible for a
+	// rejection in this window, most-frequent first.
+	TopFailingJobs []DigestJobFailureCount `json:"topFailingJobs,omitempty"`
+	// BrokenUpgradeEdges lists "from->to" upgrade edges, among edges landing
+	// on a tag created in this window, that have never recorded a success.
+	// The upgrade graph doesn't timestamp individual edge results, so this
+	// can't distinguish an edge that just started failing from one that has
+	// always failed; restricting to edges targeting tags from this window is
+	// what keeps the signal tied to recent activity despite that.
+	BrokenUpgradeEdges []string `json:"brokenUpgradeEdges,omitempty"`
+}
+
+// empty reports whether d has nothing worth notifying about.
+func (d StreamDigest) empty() bool {
+	return len(d.Accepted) == 0 && len(d.Rejected) == 0 && len(d.TopFailingJobs) == 0 && len(d.BrokenUpgradeEdges) == 0
+}
+
+// NotificationDigest is the payload POSTed to the configured digest sink.
+type NotificationDigest struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Streams     []StreamDigest `json:"streams"`
+}
+
+// computeStreamDigest summarizes release's tags created in [since, until).
+func (c *Controller) computeStreamDigest(release *Release, since, until time.Time) StreamDigest {
+	digest := StreamDigest{Stream: release.Config.Name, Since: since, Until: until}
+
+	failureCounts := make(map[string]int)
+	var newTagNames []string
+	for _, tag := range findTagReferencesByPhase(release, releasePhaseAccepted, releasePhaseRejected) {
+		created, err := time.Parse(time.RFC3339, tag.Annotations[releaseAnnotationCreationTimestamp])
+		if err != nil || created.Before(since) || !created.Before(until) {
+			continue
+		}
+		newTagNames = append(newTagNames, tag.Name)
+		switch tag.Annotations[releaseAnnotationPhase] {
+		case releasePhaseAccepted:
+			digest.Accepted = append(digest.Accepted, tag.Name)
+		case releasePhaseRejected:
+			digest.Rejected = append(digest.Rejected, tag.Name)
+			status, err := decodeVerificationStatus(tag)
+			if err != nil {
+				continue
+			}
+			if failures, ok := status.Failures(); ok {
+				for _, name := range failures {
+					failureCounts[name]++
+				}
+			}
+		}
+	}
+	sort.Strings(digest.Accepted)
+	sort.Strings(digest.Rejected)
+
+	for name, count := range failureCounts {
+		digest.TopFailingJobs = append(digest.TopFailingJobs, DigestJobFailureCount{Name: name, Failures: count})
+	}
+	sort.Slice(digest.TopFailingJobs, func(i, j int) bool {
+		if digest.TopFailingJobs[i].Failures != digest.TopFailingJobs[j].Failures {
+			return digest.TopFailingJobs[i].Failures > digest.TopFailingJobs[j].Failures
+		}
+		return digest.TopFailingJobs[i].Name < digest.TopFailingJobs[j].Name
+	})
+
+	if c.graph != nil && len(newTagNames) > 0 {
+		for _, h := range c.graph.SummarizeUpgradesTo(newTagNames...) {
+			if h.Failure > 0 && h.Success == 0 {
+				digest.BrokenUpgradeEdges = append(digest.BrokenUpgradeEdges, fmt.Sprintf("%s->%s", h.From, h.To))
+			}
+		}
+		sort.Strings(digest.BrokenUpgradeEdges)
+	}
+
+	return digest
+}
+
+// digestLoop periodically aggregates every release stream's recent activity
+// into a NotificationDigest and POSTs it to the configured sink, so a
+// high-churn stream can send one digest per interval instead of one
+// notification per accepted/rejected tag. It is a no-op if no sink is
+// configured.
+func (c *Controller) digestLoop(stopCh <-chan struct{}) {
+	if len(c.digestSink) == 0 {
+		return
+	}
+	interval := c.digestInterval
+	if interval <= 0 {
+		interval = defaultDigestInterval
+	}
+
+	last := time.Now()
+	wait.Until(func() {
+		now := time.Now()
+		since := last
+		last = now
+
+		imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+		if err != nil {
+			glog.V(4).Infof("Unable to list image streams for notification digest: %v", err)
+			return
+		}
+
+		var notification NotificationDigest
+		notification.GeneratedAt = now
+		for _, stream := range imageStreams {
+			release, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok {
+				continue
+			}
+			digest := c.computeStreamDigest(release, since, now)
+			if !digest.empty() {
+				notification.Streams = append(notification.Streams, digest)
+			}
+		}
+		if len(notification.Streams) == 0 {
+			return
+		}
+
+		data, err := json.Marshal(notification)
+		if err != nil {
+			glog.V(4).Infof("Unable to marshal notification digest: %v", err)
+			return
+		}
+		resp, err := digestHTTPClient.Post(c.digestSink, "application/json", bytes.NewReader(data))
+		if err != nil {
+			glog.V(4).Infof("Unable to deliver notification digest: %v", err)
+			return
+		}
+		resp.Body.Close()
+	}, interval, stopCh)
+}
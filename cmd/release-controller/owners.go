@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ownerSuffix formats owners as a trailing clause for an event message (e.g.
+// " (owners: Team A, Team B; #release-alerts)"), or "" if owners is unset.
+func ownerSuffix(owners *ReleaseOwners) string {
+	if owners == nil {
+		return ""
+	}
+	var parts []string
+	if len(owners.Names) > 0 {
+		parts = append(parts, strings.Join(owners.Names, ", "))
+	}
+	if len(owners.SlackChannel) > 0 {
+		parts = append(parts, "#"+owners.SlackChannel)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (owners: %s)", strings.Join(parts, "; "))
+}
+
+// renderOwners renders owners as an HTML fragment for the stream dashboard, or
+// "" if owners is unset.
+func renderOwners(owners *ReleaseOwners) string {
+	if owners == nil {
+		return ""
+	}
+	var parts []string
+	if len(owners.Names) > 0 {
+		parts = append(parts, html.EscapeString(strings.Join(owners.Names, ", ")))
+	}
+	if len(owners.SlackChannel) > 0 {
+		parts = append(parts, fmt.Sprintf("<a href=\"https://slack.com/app_redirect?channel=%s\">#%s</a>", html.EscapeString(owners.SlackChannel), html.EscapeString(owners.SlackChannel)))
+	}
+	if len(owners.EscalationLink) > 0 {
+		parts = append(parts, fmt.Sprintf("<a href=\"%s\">escalate</a>", html.EscapeString(owners.EscalationLink)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`<p class="small text-muted">Owners: %s</p>`, strings.Join(parts, " &middot; "))
+}
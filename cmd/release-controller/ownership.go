@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// componentOwnersConfigMapName is the well-known ConfigMap, in the controller's
+// job namespace, that maps image components to their owning teams. Each data
+// key is a component (release payload tag) name, and its value is a
+// comma-separated list of owning teams. The mapping is optional: if the
+// ConfigMap doesn't exist, component ownership routing is simply skipped.
+const componentOwnersConfigMapName = "component-owners"
+
+// componentOwners loads the component->owner mapping from the
+// component-owners ConfigMap, if one exists.
+func (c *Controller) componentOwners() (map[string][]string, error) {
+	if c.configMapClient == nil {
+		return nil, nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(componentOwnersConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	owners := make(map[string][]string, len(cm.Data))
+	for component, teams := range cm.Data {
+		var list []string
+		for _, team := range strings.Split(teams, ",") {
+			if team = strings.TrimSpace(team); len(team) > 0 {
+				list = append(list, team)
+			}
+		}
+		owners[component] = list
+	}
+	return owners, nil
+}
+
+// changedComponents returns the names of payload components whose image
+// changed between previousTag and releaseTag, by comparing the digests
+// embedded in each tag's `oc adm release info -o json` output - the same
+// releaseInfoShort parsing convention used by tagPromotedFrom and
+// checkReleaseMetadata.
+func (c *Controller) changedComponents(release *Release, previousTag, releaseTag *imagev1.TagReference) ([]string, error) {
+	current, err := c.componentDigests(release, releaseTag.Name)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := c.componentDigests(release, previousTag.Name)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for component, digest := range current {
+		if previous[component] != digest {
+			changed = append(changed, component)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func (c *Controller) componentDigests(release *Release, tagName string) (map[string]string, error) {
+	pullSpec := findPublicImagePullSpec(release.Target, tagName)
+	if len(pullSpec) == 0 {
+		return nil, fmt.Errorf("no pull spec available for tag %s", tagName)
+	}
+	op, err := c.releaseInfo.ReleaseInfo(pullSpec)
+	if err != nil {
+		return nil, err
+	}
+	info := releaseInfoShort{}
+	if err := json.Unmarshal([]byte(op), &info); err != nil {
+		return nil, fmt.Errorf("could not unmarshal release info for tag %s: %v", tagName, err)
+	}
+	if info.References == nil {
+		return nil, fmt.Errorf("release info for tag %s has no embedded references", tagName)
+	}
+	digests := make(map[string]string, len(info.References.Spec.Tags))
+	for _, tag := range info.References.Spec.Tags {
+		if tag.From != nil {
+			digests[tag.Name] = tag.From.Name
+		}
+	}
+	return digests, nil
+}
+
+// routeVerificationFailure names the owning teams of any payload components that
+// changed since the last accepted tag in a Warning event on the release's source
+// image stream, so cluster-level alerting can route it - speeding up triage of
+// "whose change broke the nightly". The teams are carried only in that event; no
+// annotation is written to the rejected tag. It is best-effort: owners not being configured, or
+// the underlying release info calls failing, only prevents routing, never the
+// rejection itself.
+func (c *Controller) routeVerificationFailure(release *Release, releaseTag *imagev1.TagReference, reason, message string) {
+	owners, err := c.componentOwners()
+	if err != nil {
+		glog.V(4).Infof("Unable to load component owners for %s: %v", release.Config.Name, err)
+		return
+	}
+	if len(owners) == 0 {
+		return
+	}
+	accepted := tagsForRelease(release, releasePhaseAccepted)
+	if len(accepted) == 0 {
+		return
+	}
+	changed, err := c.changedComponents(release, accepted[0], releaseTag)
+	if err != nil {
+		glog.V(4).Infof("Unable to determine changed components for %s: %v", releaseTag.Name, err)
+		return
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	teamSet := make(map[string]struct{})
+	for _, component := range changed {
+		for _, team := range owners[component] {
+			teamSet[team] = struct{}{}
+		}
+	}
+	if len(teamSet) == 0 {
+		return
+	}
+	var teams []string
+	for team := range teamSet {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "VerificationFailedComponentOwners",
+		"Release %s was rejected (%s: %s): changed components %s since %s, owned by %s",
+		releaseTag.Name, reason, message, strings.Join(changed, ", "), accepted[0].Name, strings.Join(teams, ", "))
+}
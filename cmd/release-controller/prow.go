@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/golang/glog"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -16,8 +18,18 @@ func prowJobVerificationStatus(obj *unstructured.Unstructured) (*VerificationSta
 	switch prowapiv1.ProwJobState(s) {
 	case prowapiv1.SuccessState:
 		return &VerificationStatus{State: releaseVerificationStateSucceeded, URL: url}, true
-	case prowapiv1.FailureState, prowapiv1.ErrorState, prowapiv1.AbortedState:
+	case prowapiv1.FailureState:
 		return &VerificationStatus{State: releaseVerificationStateFailed, URL: url}, true
+	case prowapiv1.ErrorState:
+		// ErrorState means prow could not run the job to completion (e.g. it could
+		// not be scheduled), not that the release under test is broken; it is
+		// retried by ensureVerificationJobs rather than treated as a genuine failure.
+		return &VerificationStatus{State: releaseVerificationStateErrored, URL: url}, true
+	case prowapiv1.AbortedState:
+		// AbortedState is also retried, unless the job was intentionally aborted by
+		// cancelVerificationJobs because a newer release superseded it.
+		description, _, _ := unstructured.NestedString(obj.Object, "status", "description")
+		return &VerificationStatus{State: releaseVerificationStateAborted, URL: url, TimedOut: description == prowJobTimedOutDescription}, true
 	case prowapiv1.TriggeredState, prowapiv1.PendingState, prowapiv1.ProwJobState(""):
 		return &VerificationStatus{State: releaseVerificationStatePending, URL: url}, true
 	default:
@@ -25,3 +37,27 @@ func prowJobVerificationStatus(obj *unstructured.Unstructured) (*VerificationSta
 		return nil, false
 	}
 }
+
+// prowJobDuration returns how long obj's ProwJob ran, from status.startTime to
+// status.completionTime. It returns false if either timestamp is missing, as
+// is the case for a job that hasn't finished yet, or for the synthetic "job
+// was never started" ProwJob returned when a job has no valid spec.
+func prowJobDuration(obj *unstructured.Unstructured) (time.Duration, bool) {
+	startStr, _, _ := unstructured.NestedString(obj.Object, "status", "startTime")
+	completionStr, _, _ := unstructured.NestedString(obj.Object, "status", "completionTime")
+	if len(startStr) == 0 || len(completionStr) == 0 {
+		return 0, false
+	}
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return 0, false
+	}
+	completion, err := time.Parse(time.RFC3339, completionStr)
+	if err != nil {
+		return 0, false
+	}
+	if completion.Before(start) {
+		return 0, false
+	}
+	return completion.Sub(start), true
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPublishHistoryPerStream bounds how many publish history entries
+// publishHistory retains per stream. Once the cap is hit the oldest entries
+// are dropped to make room for new ones.
+const maxPublishHistoryPerStream = 500
+
+// PublishHistoryEntry records one execution of a ReleasePublish step against
+// a release tag.
+type PublishHistoryEntry struct {
+	Step        string    `json:"step"`
+	Tag         string    `json:"tag"`
+	At          time.Time `json:"at"`
+	Outcome     string    `json:"outcome"` // Succeeded or Failed
+	Destination string    `json:"destination,omitempty"`
+	// Retries counts how many consecutive prior attempts of this exact
+	// (step, tag) pair failed before this entry, reset to 0 once an attempt
+	// succeeds.
+	Retries int    `json:"retries,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// publishHistory retains a bounded, in-memory history of publish step
+// executions per release stream, for /publish-history and the stream-level
+// recent-publishes feed. Like eventHistory, losing this on restart only
+// means the history view is momentarily incomplete, not that any publish
+// outcome itself is lost - the publish steps themselves remain idempotent
+// and are simply re-evaluated on the next sync.
+type publishHistory struct {
+	lock     sync.Mutex
+	byStream map[string][]PublishHistoryEntry
+	// failures counts consecutive failed attempts, keyed by "stream/step/tag".
+	failures map[string]int
+}
+
+func newPublishHistory() *publishHistory {
+	return &publishHistory{
+		byStream: make(map[string][]PublishHistoryEntry),
+		failures: make(map[string]int),
+	}
+}
+
+func publishHistoryKey(stream, step, tag string) string {
+	return stream + "/" + step + "/" + tag
+}
+
+// record appends an entry for stream's step execution against tag,
+// identified by its destination (the exact tag, stream, or registry path the
+// step wrote to) and result, to the stream's history.
+func (h *publishHistory) record(stream, step, tag, destination string, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := publishHistoryKey(stream, step, tag)
+	entry := PublishHistoryEntry{Step: step, Tag: tag, At: time.Now(), Destination: destination}
+	if err != nil {
+		entry.Retries = h.failures[key]
+		h.failures[key]++
+		entry.Outcome = releaseVerificationStateFailed
+		entry.Error = err.Error()
+	} else {
+		entry.Outcome = releaseVerificationStateSucceeded
+		entry.Retries = h.failures[key]
+		delete(h.failures, key)
+	}
+
+	entries := append(h.byStream[stream], entry)
+	if len(entries) > maxPublishHistoryPerStream {
+		entries = entries[len(entries)-maxPublishHistoryPerStream:]
+	}
+	h.byStream[stream] = entries
+}
+
+// hasFailing reports whether stream currently has any publish step whose most
+// recent attempt failed, i.e. a (step, tag) pair with unresolved consecutive
+// failures. It does not distinguish how many steps are failing or how long
+// they have been failing, since that detail belongs in the full history
+// returned by list.
+func (h *publishHistory) hasFailing(stream string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	prefix := stream + "/"
+	for key := range h.failures {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns stream's recorded publish history, newest first, optionally
+// filtered to a single tag.
+func (h *publishHistory) list(stream, tag string) []PublishHistoryEntry {
+	h.lock.Lock()
+	entries := append([]PublishHistoryEntry(nil), h.byStream[stream]...)
+	h.lock.Unlock()
+
+	var filtered []PublishHistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if len(tag) > 0 && e.Tag != tag {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
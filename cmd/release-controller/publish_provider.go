@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// PublishContext carries everything a PublishProvider needs to act on an accepted
+// release, without exposing the whole Controller.
+type PublishContext struct {
+	Controller *Controller
+	Release    *Release
+	Tag        *imagev1.TagReference
+}
+
+// PublishProvider implements a custom publish step, selected by a ReleasePublish
+// entry's Type field. Providers are intended to live in their own package (e.g. an
+// S3 upload or an internal catalog push) and register themselves from an init().
+type PublishProvider interface {
+	Publish(ctx *PublishContext, config map[string]string) error
+}
+
+var publishProviders = make(map[string]PublishProvider)
+
+// RegisterPublishProvider makes provider available to any ReleasePublish entry
+// with "type": name. It is intended to be called from an init() function and
+// panics if name is already registered.
+func RegisterPublishProvider(name string, provider PublishProvider) {
+	if _, exists := publishProviders[name]; exists {
+		panic(fmt.Sprintf("publish provider %q is already registered", name))
+	}
+	publishProviders[name] = provider
+}
@@ -4,29 +4,74 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/golang/glog"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	imagev1 "github.com/openshift/api/image/v1"
 )
 
-func (c *Controller) releaseDefinition(is *imagev1.ImageStream) (*Release, bool, error) {
-	src, ok := is.Annotations[releaseAnnotationConfig]
-	if !ok {
-		return nil, false, nil
+var hotfixNamePattern = regexp.MustCompile(hotfixNameSuffix)
+
+// validateHotfixConfig enforces the stricter set of rules a Hotfix release config
+// must follow, reflecting the narrow, short-lived nature of a hotfix: a name that
+// makes its relationship to the base stream obvious, at least one mandatory
+// verification so a hotfix can't ship unverified, and retention long enough to
+// outlive the incident it addresses.
+func validateHotfixConfig(cfg *ReleaseConfig) error {
+	if !hotfixNamePattern.MatchString(cfg.Name) {
+		return fmt.Errorf("hotfix release name %q must end in \"-hotfix-{id}\" (e.g. %s-hotfix-1)", cfg.Name, cfg.Name)
+	}
+	mandatory := false
+	for _, verify := range cfg.Verify {
+		if !verify.Optional {
+			mandatory = true
+			break
+		}
+	}
+	if !mandatory {
+		return fmt.Errorf("hotfix release %s must define at least one mandatory (non-optional) verify step", cfg.Name)
+	}
+	if expires := cfg.Expires.Duration(); expires > 0 && expires < hotfixMinExpires {
+		return fmt.Errorf("hotfix release %s expires must be at least %s to outlive the incident it addresses", cfg.Name, hotfixMinExpires)
 	}
-	cfg, err := c.parseReleaseConfig(src)
+	if cfg.Expires.Duration() == 0 {
+		cfg.Expires = Duration(hotfixDefaultExpires)
+	}
+	return nil
+}
+
+func (c *Controller) releaseDefinition(is *imagev1.ImageStream) (*Release, bool, error) {
+	// A ReleaseConfig custom resource, if one exists for this imagestream,
+	// takes precedence over the annotation - see release_config_crd.go. This
+	// lets a cluster migrate from the annotation to the CRD one stream at a
+	// time rather than all at once.
+	cfg, ok, err := c.releaseConfigFromCRD(is.Namespace, is.Name)
 	if err != nil {
-		err = fmt.Errorf("the %s annotation for %s is invalid: %v", releaseAnnotationConfig, is.Name, err)
+		err = fmt.Errorf("the ReleaseConfig %s/%s is invalid: %v", is.Namespace, is.Name, err)
 		c.eventRecorder.Eventf(is, corev1.EventTypeWarning, "InvalidReleaseDefinition", "%v", err)
 		return nil, false, terminalError{err}
 	}
+	if !ok {
+		src, annotated := is.Annotations[releaseAnnotationConfig]
+		if !annotated {
+			return nil, false, nil
+		}
+		cfg, err = c.parseReleaseConfig(src)
+		if err != nil {
+			err = fmt.Errorf("the %s annotation for %s is invalid: %v", releaseAnnotationConfig, is.Name, err)
+			c.eventRecorder.Eventf(is, corev1.EventTypeWarning, "InvalidReleaseDefinition", "%v", err)
+			return nil, false, terminalError{err}
+		}
+	}
 
 	// TODO: require release config to point to a particular image stream, and then we should ignore image streams
 	//   that don't target c.releaseImageStream (so we can run separate controllers)
@@ -69,9 +114,11 @@ func (c *Controller) parseReleaseConfig(data string) (*ReleaseConfig, error) {
 	}
 	obj, ok := c.parsedReleaseConfigCache.Get(data)
 	if ok {
+		parseReleaseConfigTotal.WithLabelValues("hit").Inc()
 		cfg := obj.(ReleaseConfig)
 		return &cfg, nil
 	}
+	parseReleaseConfigTotal.WithLabelValues("miss").Inc()
 	cfg := &ReleaseConfig{}
 	if err := json.Unmarshal([]byte(data), cfg); err != nil {
 		return nil, err
@@ -91,11 +138,45 @@ func (c *Controller) parseReleaseConfig(data string) (*ReleaseConfig, error) {
 		default:
 			return nil, fmt.Errorf("verify config %s has an invalid upgradeFrom: %s", name, verify.UpgradeFrom)
 		}
+		for _, source := range verify.UpgradeFromSources {
+			switch source {
+			case releaseUpgradeFromPreviousMinor, releaseUpgradeFromPreviousPatch, releaseUpgradeFromPrevious:
+			default:
+				return nil, fmt.Errorf("verify config %s has an invalid upgradeFromSources entry: %s", name, source)
+			}
+		}
+		switch verify.UpgradeAggregation {
+		case releaseUpgradeAggregationAll, releaseUpgradeAggregationAny, "":
+		default:
+			return nil, fmt.Errorf("verify config %s has an invalid upgradeAggregation: %s", name, verify.UpgradeAggregation)
+		}
 		if verify.ProwJob != nil {
 			if len(verify.ProwJob.Name) == 0 {
 				return nil, fmt.Errorf("prow job for %s has no name", name)
 			}
 		}
+		if verify.Timeout < 0 {
+			return nil, fmt.Errorf("verify config %s has an invalid timeout: must not be negative", name)
+		}
+		if verify.MinComponentAge != nil && verify.MinComponentAge.MinimumAge <= 0 {
+			return nil, fmt.Errorf("verify config %s has an invalid minComponentAge.minimumAge: must be positive", name)
+		}
+		if verify.AggregatedProwJob != nil {
+			if verify.ProwJob == nil {
+				return nil, fmt.Errorf("verify config %s sets aggregatedProwJob but has no prowJob", name)
+			}
+			if verify.AggregatedProwJob.Count <= 0 {
+				return nil, fmt.Errorf("verify config %s has an invalid aggregatedProwJob.count: %d", name, verify.AggregatedProwJob.Count)
+			}
+			if verify.AggregatedProwJob.Threshold <= 0 || verify.AggregatedProwJob.Threshold > verify.AggregatedProwJob.Count {
+				return nil, fmt.Errorf("verify config %s has an invalid aggregatedProwJob.threshold: %d", name, verify.AggregatedProwJob.Threshold)
+			}
+		}
+		for _, env := range verify.RequiredEnv {
+			if len(env) == 0 {
+				return nil, fmt.Errorf("verify config %s has an empty requiredEnv entry", name)
+			}
+		}
 	}
 	for name, publish := range cfg.Publish {
 		if len(name) == 0 {
@@ -111,7 +192,108 @@ func (c *Controller) parseReleaseConfig(data string) (*ReleaseConfig, error) {
 				return nil, fmt.Errorf("imageStreamRef publish for %s has no name", name)
 			}
 		}
+		if publish.Webhook != nil {
+			if len(publish.Webhook.URL) == 0 {
+				return nil, fmt.Errorf("webhook publish for %s has no url", name)
+			}
+			if publish.Webhook.MaxRetries < 0 {
+				return nil, fmt.Errorf("webhook publish for %s has a negative maxRetries", name)
+			}
+		}
+	}
+	if backoff := cfg.RetryBackoff; backoff != nil {
+		if backoff.Initial < 0 {
+			return nil, fmt.Errorf("retryBackoff has an invalid initial: must not be negative")
+		}
+		if backoff.Factor < 0 {
+			return nil, fmt.Errorf("retryBackoff has an invalid factor: must not be negative")
+		}
+		if backoff.Cap < 0 {
+			return nil, fmt.Errorf("retryBackoff has an invalid cap: must not be negative")
+		}
+		if backoff.Jitter < 0 || backoff.Jitter > 1 {
+			return nil, fmt.Errorf("retryBackoff has an invalid jitter: must be between 0 and 1")
+		}
+	}
+	for _, exclusion := range cfg.UpgradeExclusions {
+		if _, err := semver.ParseRange(exclusion.Range); err != nil {
+			return nil, fmt.Errorf("upgradeExclusions range %q is invalid: %v", exclusion.Range, err)
+		}
+	}
+	for name, profile := range cfg.VerificationProfiles {
+		if len(name) == 0 {
+			return nil, fmt.Errorf("verificationProfiles config has no name")
+		}
+		for _, step := range profile.Only {
+			if _, ok := cfg.Verify[step]; !ok {
+				return nil, fmt.Errorf("verificationProfiles %s references unknown verify step %q", name, step)
+			}
+		}
+	}
+	for i, rule := range cfg.ProfileSchedule {
+		if _, ok := cfg.VerificationProfiles[rule.Profile]; !ok {
+			return nil, fmt.Errorf("profileSchedule[%d] references unknown verificationProfiles entry %q", i, rule.Profile)
+		}
+		for _, day := range rule.Days {
+			if _, err := time.Parse("Monday", day); err != nil {
+				return nil, fmt.Errorf("profileSchedule[%d] has an invalid day %q", i, day)
+			}
+		}
+		for _, hour := range rule.Hours {
+			if hour < 0 || hour > 23 {
+				return nil, fmt.Errorf("profileSchedule[%d] has an invalid hour %d, must be 0-23", i, hour)
+			}
+		}
+	}
+
+	if len(cfg.DownloadURLTemplate) > 0 {
+		arch := cfg.Architecture
+		if len(arch) == 0 {
+			arch = defaultReleaseArchitecture
+		}
+		if _, err := resolveDownloadURLTemplate(cfg.DownloadURLTemplate, "4.10.0-0.nightly-2021-01-01-000000", arch); err != nil {
+			return nil, fmt.Errorf("downloadURLTemplate is invalid: %v", err)
+		}
+	}
+
+	if cfg.As == releaseConfigModeHotfix {
+		if err := validateHotfixConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.PayloadJob != nil && len(cfg.PayloadJob.EgressPolicyProfile) > 0 {
+		if errs := validation.IsValidLabelValue(cfg.PayloadJob.EgressPolicyProfile); len(errs) > 0 {
+			return nil, fmt.Errorf("payloadJob.egressPolicyProfile %q is invalid: %s", cfg.PayloadJob.EgressPolicyProfile, strings.Join(errs, ", "))
+		}
+	}
+	if !validVersionScheme(cfg.VersionScheme) {
+		return nil, fmt.Errorf("versionScheme %q is not one of semver, date, or numeric", cfg.VersionScheme)
+	}
+	if m := cfg.UpgradeMatrix; m != nil {
+		if len(m.ProwJob) == 0 {
+			return nil, fmt.Errorf("upgradeMatrix for %s must specify a prowJob", cfg.Name)
+		}
+		if m.MaxPerDay == 0 {
+			m.MaxPerDay = defaultUpgradeMatrixMaxPerDay
+		}
+	}
+	for i, notification := range cfg.Notifications {
+		if _, ok := notificationFormatters[notification.Provider]; !ok {
+			return nil, fmt.Errorf("notifications[%d] has an unknown provider %q", i, notification.Provider)
+		}
+		if (len(notification.URL) == 0) == (len(notification.SecretName) == 0) {
+			return nil, fmt.Errorf("notifications[%d] must set exactly one of url or secretName", i)
+		}
+		for _, phase := range notification.Phases {
+			if !containsString(notificationPhases, phase) {
+				return nil, fmt.Errorf("notifications[%d] has an invalid phase %q", i, phase)
+			}
+		}
+	}
+	if b := cfg.AcceptanceCircuitBreaker; b != nil && b.ConsecutiveRejections < 1 {
+		return nil, fmt.Errorf("acceptanceCircuitBreaker.consecutiveRejections must be at least 1")
 	}
+
 	copied := *cfg
 	c.parsedReleaseConfigCache.Add(data, copied)
 	return cfg, nil
@@ -269,6 +451,25 @@ func findPublicImagePullSpec(is *imagev1.ImageStream, name string) string {
 	return ""
 }
 
+// isVerificationOnly reports whether tag was registered by registerExternalPayload
+// for verify/gate processing only. Such tags must never be treated as part of the
+// release's real lineage for promotion or "latest" reporting purposes.
+func isVerificationOnly(tag *imagev1.TagReference) bool {
+	return tag.Annotations[releaseAnnotationVerificationOnly] == "true"
+}
+
+// withoutVerificationOnly filters tags down to those that are part of the
+// release's real lineage, dropping any registered by registerExternalPayload.
+func withoutVerificationOnly(tags []*imagev1.TagReference) []*imagev1.TagReference {
+	var out []*imagev1.TagReference
+	for _, tag := range tags {
+		if !isVerificationOnly(tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
 func semanticTagsForRelease(release *Release, phases ...string) SemanticVersions {
 	is := release.Target
 	sourceName := fmt.Sprintf("%s/%s", release.Source.Namespace, release.Source.Name)
@@ -283,13 +484,16 @@ func semanticTagsForRelease(release *Release, phases ...string) SemanticVersions
 		if tag.Annotations[releaseAnnotationName] != release.Config.Name {
 			continue
 		}
+		if isVerificationOnly(tag) {
+			continue
+		}
 		if len(phases) > 0 {
 			if !stringSliceContains(phases, tag.Annotations[releaseAnnotationPhase]) {
 				continue
 			}
 		}
 
-		if version, err := semver.Parse(tag.Name); err == nil {
+		if version, err := parseStreamVersion(release.Config.VersionScheme, tag.Name); err == nil {
 			versions = append(versions, SemanticVersion{Tag: tag, Version: &version})
 		} else {
 			versions = append(versions, SemanticVersion{Tag: tag})
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// releaseConfigCRDResource identifies the optional ReleaseConfig custom
+// resource (releases.release.openshift.io) that a cluster admin may install
+// instead of, or alongside, the release.openshift.io/config imagestream
+// annotation. The CRD is not shipped by this repository (it has no
+// manifests/ directory to put one in); a cluster wanting to use it applies
+// one with a structural OpenAPI schema mirroring ReleaseConfig's JSON tags,
+// with at least "name" required, so the API server rejects malformed configs
+// at admission time rather than this controller discovering the problem
+// later. A ReleaseConfig resource's namespace and name must match the
+// imagestream it configures - that's how releaseConfigFromCRD finds it.
+var releaseConfigCRDResource = schema.GroupVersionResource{Group: "release.openshift.io", Version: "v1", Resource: "releases"}
+
+// releaseConfigFromCRD looks up the ReleaseConfig custom resource named name
+// in namespace, if the controller was started with a ReleaseConfig informer
+// (see AddReleaseConfigInformer), and parses its "spec" field the same way an
+// annotation value is parsed - including the cache in parseReleaseConfig and
+// all of its validation. ok is false, with no error, if no informer is
+// configured or no matching resource exists, the two cases in which
+// releaseDefinition should fall back to the imagestream annotation.
+func (c *Controller) releaseConfigFromCRD(namespace, name string) (cfg *ReleaseConfig, ok bool, err error) {
+	if c.releaseConfigLister == nil {
+		return nil, false, nil
+	}
+	obj, exists, err := c.releaseConfigLister.GetByKey(fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return nil, false, nil
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected type %T for ReleaseConfig %s/%s", obj, namespace, name)
+	}
+	spec, found, err := unstructured.NestedMap(u.Object, "spec")
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, fmt.Errorf("ReleaseConfig %s/%s has no spec", namespace, name)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, false, err
+	}
+	parsed, err := c.parseReleaseConfig(string(data))
+	if err != nil {
+		return nil, false, err
+	}
+	return parsed, true, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// riskScoreWeights returns release's configured RiskWeights, or
+// defaultRiskScoreWeights if it hasn't set any.
+func riskScoreWeights(release *Release) RiskScoreWeights {
+	if release.Config.RiskWeights != nil {
+		return *release.Config.RiskWeights
+	}
+	return defaultRiskScoreWeights
+}
+
+// computeRiskScore estimates how risky it is to promote releaseTag, combining
+// optional-verification-step failures, infra-failure retries, the changelog
+// size, and the number of changed payload components since previousTag (the
+// release this tag would be promoted over). previousTag may be nil, in which
+// case the changelog and component-diff signals are left at zero, since there
+// is nothing to diff against.
+//
+// The changelog and component-digest lookups shell out to "oc adm release
+// info" (see CachingReleaseInfo), so this is deliberately not called from the
+// sync loop; it's exposed on demand via httpReleaseTagRisk instead, following
+// the precedent set by componentLayerDiffs.
+func (c *Controller) computeRiskScore(release *Release, releaseTag *imagev1.TagReference, previousRelease *Release, previousTag *imagev1.TagReference) (*RiskScore, error) {
+	score := &RiskScore{}
+	weights := riskScoreWeights(release)
+
+	verify, _ := effectiveVerifyForTag(release, releaseTag)
+	if status, err := decodeVerificationStatus(releaseTag); err == nil {
+		for name, s := range status {
+			if s != nil && s.State == releaseVerificationStateFailed && verify[name].Optional {
+				score.OptionalFailures++
+			}
+		}
+	}
+
+	if data := releaseTag.Annotations[releaseAnnotationInfraRetries]; len(data) > 0 {
+		var retries map[string]int
+		if err := json.Unmarshal([]byte(data), &retries); err == nil {
+			for _, n := range retries {
+				score.InfraRetries += n
+			}
+		}
+	}
+
+	if previousTag != nil {
+		if changed, err := c.changedComponents(release, previousTag, releaseTag); err == nil {
+			score.NewComponents = len(changed)
+		}
+
+		fromPull := findPublicImagePullSpec(previousRelease.Target, previousTag.Name)
+		toPull := findPublicImagePullSpec(release.Target, releaseTag.Name)
+		if len(fromPull) > 0 && len(toPull) > 0 {
+			if log, err := c.releaseInfo.ChangeLog(fromPull, toPull); err == nil {
+				score.ChangelogLines = strings.Count(log, "\n")
+			}
+		}
+	}
+
+	score.Total = weights.OptionalFailures*float64(score.OptionalFailures) +
+		weights.ChangelogSize*float64(score.ChangelogLines) +
+		weights.NewComponents*float64(score.NewComponents) +
+		weights.InfraRetries*float64(score.InfraRetries)
+
+	return score, nil
+}
+
+// httpReleaseTagRisk serves the RiskScore for a single release tag. See
+// computeRiskScore.
+func (c *Controller) httpReleaseTagRisk(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	release := vars["release"]
+	tag := vars["tag"]
+
+	tags, ok := c.findReleaseStreamTags(true, tag)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unable to find release tag %s, it may have been deleted", tag), http.StatusNotFound)
+		return
+	}
+	info := tags[tag]
+	if len(release) > 0 && info.Release.Config.Name != release {
+		http.Error(w, fmt.Sprintf("Release tag %s does not belong to release %s", tag, release), http.StatusNotFound)
+		return
+	}
+
+	score, err := c.computeRiskScore(info.Release, info.Tag, info.PreviousRelease, info.Previous)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to compute risk score: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(score, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// storageReportInterval controls how often storageReportLoop recomputes usage for
+// every release stream. Storage totals change slowly relative to release cadence,
+// so this runs far less often than the sync loop.
+const storageReportInterval = 15 * time.Minute
+
+// StorageReport summarizes the registry storage attributable to a release stream's
+// tags, deduplicated by the underlying image layers they reference.
+type StorageReport struct {
+	// Stream is the release name the report was computed for.
+	Stream string `json:"stream"`
+	// Bytes is the sum of distinct layer sizes referenced by the stream's tags.
+	Bytes int64 `json:"bytes"`
+	// Tags is the number of distinct tag digests that contributed to Bytes.
+	Tags int `json:"tags"`
+	// QuotaBytes is the configured Config.StorageQuotaBytes, or 0 if unset.
+	QuotaBytes int64 `json:"quotaBytes,omitempty"`
+	// QuotaBreached is true when QuotaBytes is set and Bytes exceeds it.
+	QuotaBreached bool `json:"quotaBreached,omitempty"`
+	// CheckedAt is when this report was computed.
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// storageReportCache holds the most recently computed StorageReport per stream, so
+// that the HTTP API and dashboard can serve reports without recomputing them (which
+// requires an Images().Get() round trip per distinct tag digest) on every request.
+type storageReportCache struct {
+	lock    sync.Mutex
+	reports map[string]StorageReport
+}
+
+func newStorageReportCache() *storageReportCache {
+	return &storageReportCache{reports: make(map[string]StorageReport)}
+}
+
+func (c *storageReportCache) get(stream string) (StorageReport, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	report, ok := c.reports[stream]
+	return report, ok
+}
+
+func (c *storageReportCache) set(report StorageReport) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.reports[report.Stream] = report
+}
+
+// storageReportLoop periodically recomputes and caches per-stream storage usage, and
+// warns when a stream has a configured quota and has exceeded it.
+func (c *Controller) storageReportLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+		if err != nil {
+			glog.V(4).Infof("Unable to list image streams for storage reporting: %v", err)
+			return
+		}
+		now := time.Now()
+		for _, stream := range imageStreams {
+			release, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok {
+				continue
+			}
+			report, err := c.computeStorageReport(release, now)
+			if err != nil {
+				glog.V(4).Infof("Unable to compute storage report for %s: %v", release.Config.Name, err)
+				continue
+			}
+			c.storageReports.set(report)
+
+			releaseStreamStorageBytes.WithLabelValues(report.Stream).Set(float64(report.Bytes))
+			if report.QuotaBytes <= 0 {
+				releaseStreamStorageQuotaBreached.WithLabelValues(report.Stream).Set(0)
+				continue
+			}
+			if report.QuotaBreached {
+				releaseStreamStorageQuotaBreached.WithLabelValues(report.Stream).Set(1)
+				c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "ReleaseStorageQuotaBreached", "Release stream %s is using %d bytes of registry storage, over its configured quota of %d bytes", report.Stream, report.Bytes, report.QuotaBytes)
+				continue
+			}
+			releaseStreamStorageQuotaBreached.WithLabelValues(report.Stream).Set(0)
+		}
+	}, storageReportInterval, stopCh)
+}
+
+// computeStorageReport sums the distinct image layers referenced by release's tags.
+// Tags that resolve to the same image digest (e.g. re-imported or re-tagged content)
+// are only counted once, and layers shared between images are only counted once,
+// matching how a content-addressable registry actually stores them.
+func (c *Controller) computeStorageReport(release *Release, now time.Time) (StorageReport, error) {
+	report := StorageReport{
+		Stream:     release.Config.Name,
+		QuotaBytes: release.Config.StorageQuotaBytes,
+		CheckedAt:  now,
+	}
+
+	layerSizes := make(map[string]int64)
+	seenDigests := make(map[string]bool)
+
+	statusByTag := make(map[string]string)
+	for i := range release.Target.Status.Tags {
+		tagStatus := &release.Target.Status.Tags[i]
+		if len(tagStatus.Items) == 0 {
+			continue
+		}
+		statusByTag[tagStatus.Tag] = tagStatus.Items[0].Image
+	}
+
+	for i := range release.Target.Spec.Tags {
+		tag := &release.Target.Spec.Tags[i]
+		if tag.Annotations[releaseAnnotationName] != release.Config.Name {
+			continue
+		}
+		digest := statusByTag[tag.Name]
+		if len(digest) == 0 || seenDigests[digest] {
+			continue
+		}
+		seenDigests[digest] = true
+
+		image, err := c.imageClient.Images().Get(digest, metav1.GetOptions{})
+		if err != nil {
+			glog.V(5).Infof("Unable to get image %s for storage report of %s: %v", digest, release.Config.Name, err)
+			continue
+		}
+		report.Tags++
+		for _, layer := range image.DockerImageLayers {
+			layerSizes[layer.Name] = layer.LayerSize
+		}
+	}
+
+	for _, size := range layerSizes {
+		report.Bytes += size
+	}
+	if report.QuotaBytes > 0 && report.Bytes > report.QuotaBytes {
+		report.QuotaBreached = true
+	}
+	return report, nil
+}
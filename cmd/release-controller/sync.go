@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -80,7 +81,34 @@ func (c *Controller) sync(key queueKey) error {
 	}
 
 	// ensure that changes to the input image stream turn into a new release (if no current release is being processed)
-	if len(pendingTags) == 0 && hasNewImages {
+	if len(pendingTags) == 0 && hasNewImages && !release.Config.Paused {
+		if unhealthy, err := c.unhealthyComponents(release); err != nil {
+			glog.Errorf("Unable to check component health for %s, proceeding with release creation: %v", release.Config.Name, err)
+		} else if len(unhealthy) > 0 {
+			c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "ComponentsUnhealthy", "waiting on components: %s", strings.Join(unhealthy, ", "))
+			c.queue.AddAfter(key, componentHealthRecheckInterval)
+			return nil
+		}
+
+		if untrusted, err := c.untrustedComponents(release); err != nil {
+			glog.Errorf("Unable to check component signatures for %s: %v", release.Config.Name, err)
+			return err
+		} else if len(untrusted) > 0 {
+			c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "ComponentsUntrusted", "refusing to build payload, unsigned or untrusted components: %s", strings.Join(untrusted, ", "))
+			c.queue.AddAfter(key, componentHealthRecheckInterval)
+			return nil
+		}
+
+		if c.checkAcceptanceCircuitBreaker(release) {
+			c.queue.AddAfter(key, componentHealthRecheckInterval)
+			return nil
+		}
+
+		if c.dryRun {
+			glog.Infof("[dry-run] release %s: would create a new release tag for input image hash %s", release.Config.Name, inputImageHash)
+			return nil
+		}
+
 		releaseTag, err := c.createReleaseTag(release, now, inputImageHash)
 		if err != nil {
 			c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "UnableToCreateRelease", "%v", err)
@@ -92,6 +120,7 @@ func (c *Controller) sync(key queueKey) error {
 	// ensure any pending tags have the necessary jobs/mirrors created
 	if err := c.syncPending(release, pendingTags, inputImageHash); err != nil {
 		if errors.IsConflict(err) {
+			imageStreamUpdateConflictsTotal.WithLabelValues(release.Target.Namespace, release.Target.Name).Inc()
 			return nil
 		}
 		c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "UnableToProcessRelease", "%v", err)
@@ -101,6 +130,7 @@ func (c *Controller) sync(key queueKey) error {
 	// ensure verification steps are run on the ready tags
 	if err := c.syncReady(release); err != nil {
 		if errors.IsConflict(err) {
+			imageStreamUpdateConflictsTotal.WithLabelValues(release.Target.Namespace, release.Target.Name).Inc()
 			return nil
 		}
 		c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "UnableToVerifyRelease", "%v", err)
@@ -110,16 +140,35 @@ func (c *Controller) sync(key queueKey) error {
 	// ensure publish steps are run on the accepted tags
 	if err := c.syncAccepted(release); err != nil {
 		if errors.IsConflict(err) {
+			imageStreamUpdateConflictsTotal.WithLabelValues(release.Target.Namespace, release.Target.Name).Inc()
 			return nil
 		}
 		c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "UnableToVerifyRelease", "%v", err)
 		return err
 	}
 
+	c.checkReleaseFreshness(release, now)
+
 	c.gcQueue.AddAfter("", 15*time.Second)
 	return nil
 }
 
+// checkReleaseFreshness exports the freshness SLA (Config.ExpectAcceptedEvery)
+// conformance of release as a metric and, on a breach, emits a warning event so
+// that operators are notified without needing to watch the dashboard.
+func (c *Controller) checkReleaseFreshness(release *Release, now time.Time) {
+	configured, breached, _ := releaseFreshnessBreach(release, tagsForRelease(release, releasePhaseAccepted), now)
+	if !configured {
+		return
+	}
+	if breached {
+		releaseStreamSLABreached.WithLabelValues(release.Config.Name).Set(1)
+		c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "ReleaseFreshnessSLABreached", "No Accepted release within the configured %s SLA%s", release.Config.ExpectAcceptedEvery.Duration(), ownerSuffix(release.Config.Owners))
+		return
+	}
+	releaseStreamSLABreached.WithLabelValues(release.Config.Name).Set(0)
+}
+
 func calculateSyncActions(release *Release, now time.Time) (adoptTags, pendingTags, removeTags []*imagev1.TagReference, hasNewImages bool, inputImageHash string) {
 	hasNewImages = true
 	inputImageHash = hashSpecTagImageDigests(release.Source)
@@ -229,9 +278,19 @@ func (c *Controller) syncAdopted(release *Release, adoptTags []*imagev1.TagRefer
 			// changes the list of tags, so needs to exit
 			return true, c.replaceReleaseTagWithNext(release, tag)
 		}
-		if _, err := semver.Parse(tag.Name); err == nil {
-			names = append(names, tag.Name)
+		version, err := semver.Parse(tag.Name)
+		if err != nil {
+			continue
+		}
+		reason, err := c.minorVersionFreezeReason(version.Major, version.Minor)
+		if err != nil {
+			return false, err
+		}
+		if len(reason) > 0 {
+			glog.V(4).Infof("Not promoting %s into stable release %s: %d.%d is frozen: %s", tag.Name, release.Config.Name, version.Major, version.Minor, reason)
+			continue
 		}
+		names = append(names, tag.Name)
 	}
 	if len(names) == 0 {
 		return false, nil
@@ -359,17 +418,19 @@ func (c *Controller) syncPending(release *Release, pendingTags []*imagev1.TagRef
 			return fmt.Errorf("mirror hash for %q does not match, release cannot be created", tag.Name)
 		}
 
-		job, err := c.ensureReleaseJob(release, tag.Name, mirror)
-		if err != nil || job == nil {
+		complete, success, job, err := payloadEngineFor(release.Config).ensurePayload(c, release, tag.Name, mirror)
+		if err != nil {
 			return err
 		}
-		success, complete := jobIsComplete(job)
 		switch {
 		case !complete:
 			return nil
 		case !success:
-			// try to get the last termination message
-			log, _, _ := ensureJobTerminationMessageRetrieved(c.podClient, job, "status.phase=Failed", "build", false)
+			// try to get the last termination message, if the engine used a Job
+			var log string
+			if job != nil {
+				log, _, _ = ensureJobTerminationMessageRetrieved(c.podClient, job, "status.phase=Failed", "build", false)
+			}
 			if err := c.transitionReleasePhaseFailure(release, []string{releasePhasePending}, releasePhaseFailed, withLog(reasonAndMessage("CreateReleaseFailed", "Could not create the release image"), log), tag.Name); err != nil {
 				return err
 			}
@@ -397,38 +458,97 @@ func (c *Controller) syncReady(release *Release) error {
 		glog.Infof("ready=%v", tagNames(readyTags))
 	}
 
+	if release.Config.CancelSupersededJobs && len(readyTags) > 1 {
+		newest := readyTags[0]
+		newestCreated, _ := time.Parse(time.RFC3339, newest.Annotations[releaseAnnotationCreationTimestamp])
+		for _, t := range readyTags[1:] {
+			if created, err := time.Parse(time.RFC3339, t.Annotations[releaseAnnotationCreationTimestamp]); err == nil && created.After(newestCreated) {
+				newest, newestCreated = t, created
+			}
+		}
+		for _, t := range readyTags {
+			if t == newest {
+				continue
+			}
+			if err := c.cancelVerificationJobs(release, t); err != nil {
+				glog.Errorf("Unable to cancel superseded verification jobs for %s: %v", t.Name, err)
+			}
+		}
+	}
+
 	for _, releaseTag := range readyTags {
-		status, err := c.ensureVerificationJobs(release, releaseTag)
+		if release.Config.VerificationPropagation != nil {
+			phase, annotations, ok := c.propagatedVerificationDecision(release, releaseTag)
+			if !ok {
+				// the parent tag doesn't exist yet or hasn't reached a terminal
+				// phase; keep waiting instead of running our own verify suite.
+				continue
+			}
+			switch phase {
+			case releasePhaseAccepted:
+				if err := c.markReleaseAccepted(release, annotations, releaseTag.Name); err != nil {
+					return err
+				}
+				c.ensureReleaseChangelog(release, releaseTag)
+			case releasePhaseRejected:
+				if err := c.transitionReleasePhaseFailure(release, []string{releasePhaseReady}, releasePhaseRejected, annotations, releaseTag.Name); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		verify, profileName := effectiveVerifyForTag(release, releaseTag)
+		status, err := c.ensureVerificationJobs(release, releaseTag, verify)
 		if err != nil {
 			return err
 		}
 
-		if names, ok := status.Incomplete(release.Config.Verify); ok {
-			glog.V(4).Infof("Verification jobs for %s are still running: %s", releaseTag.Name, strings.Join(names, ", "))
-			if err := c.markReleaseReady(release, map[string]string{releaseAnnotationVerify: toJSONString(status)}, releaseTag.Name); err != nil {
+		phase, reason, rejectReason, message := evaluateAcceptance(verify, status)
+		switch phase {
+		case releasePhaseReady:
+			glog.V(4).Infof("Verification jobs for %s are still running: %s", releaseTag.Name, message)
+			annotations := map[string]string{releaseAnnotationVerify: limitAnnotationSize(releaseAnnotationVerify, toJSONString(status))}
+			if len(profileName) > 0 {
+				annotations[releaseAnnotationVerificationProfile] = profileName
+			}
+			if err := c.markReleaseReady(release, annotations, releaseTag.Name); err != nil {
 				return err
 			}
 			continue
-		}
-
-		if names, ok := status.Failures(); ok {
-			if allOptional(release.Config.Verify, names...) {
-				glog.V(4).Infof("Release %s had only optional job failures: %v", releaseTag.Name, strings.Join(names, ", "))
-			} else {
-				glog.V(4).Infof("Release %s was rejected", releaseTag.Name)
-				annotations := reasonAndMessage("VerificationFailed", fmt.Sprintf("release verification step failed: %s", strings.Join(names, ", ")))
-				annotations[releaseAnnotationVerify] = toJSONString(status)
-				if err := c.transitionReleasePhaseFailure(release, []string{releasePhaseReady}, releasePhaseRejected, annotations, releaseTag.Name); err != nil {
-					return err
+		case releasePhaseRejected:
+			glog.V(4).Infof("Release %s was rejected", releaseTag.Name)
+			annotations := reasonAndMessage(reason, message)
+			annotations[releaseAnnotationRejectReason] = rejectReason
+			annotations[releaseAnnotationVerify] = limitAnnotationSize(releaseAnnotationVerify, toJSONString(status))
+			if len(profileName) > 0 {
+				annotations[releaseAnnotationVerificationProfile] = profileName
+			}
+			c.routeVerificationFailure(release, releaseTag, reason, message)
+			if release.Config.TestBudget != nil {
+				for name, s := range status {
+					if s.State == releaseVerificationStateFailed && !verify[name].Optional {
+						c.testBudget.markRejected(release.Config.Name, releaseTag.Name, name)
+					}
 				}
-				continue
 			}
+			if err := c.transitionReleasePhaseFailure(release, []string{releasePhaseReady}, releasePhaseRejected, annotations, releaseTag.Name); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// if all jobs are complete and there are no failures, this is accepted
-		if err := c.markReleaseAccepted(release, map[string]string{releaseAnnotationVerify: toJSONString(status)}, releaseTag.Name); err != nil {
+		acceptedAnnotations := map[string]string{releaseAnnotationVerify: limitAnnotationSize(releaseAnnotationVerify, toJSONString(status))}
+		if len(profileName) > 0 {
+			acceptedAnnotations[releaseAnnotationVerificationProfile] = profileName
+		}
+		knownIssues := mergeKnownIssues(knownIssuesForTag(releaseTag), knownIssuesFromFailedOptionalVerification(verify, status))
+		acceptedAnnotations[releaseAnnotationKnownIssues] = encodeKnownIssues(knownIssues)
+		if err := c.markReleaseAccepted(release, acceptedAnnotations, releaseTag.Name); err != nil {
 			return err
 		}
+		c.ensureReleaseChangelog(release, releaseTag)
 		glog.V(4).Infof("Release %s accepted", releaseTag.Name)
 	}
 
@@ -437,11 +557,18 @@ func (c *Controller) syncReady(release *Release) error {
 
 func (c *Controller) syncAccepted(release *Release) error {
 	acceptedTags := findTagReferencesByPhase(release, releasePhaseAccepted)
+	acceptedTags = withoutVerificationOnly(acceptedTags)
 
 	if glog.V(4) && len(acceptedTags) > 0 {
 		glog.Infof("release=%s accepted=%v", release.Config.Name, tagNames(acceptedTags))
 	}
 
+	if release.Config.As == releaseConfigModeHotfix {
+		// Hotfixes are promoted by hand, not through the normal Publish steps, so that
+		// a targeted fix doesn't silently fan out to every location the base stream
+		// publishes to.
+		return nil
+	}
 	if len(release.Config.Publish) == 0 || len(acceptedTags) == 0 {
 		return nil
 	}
@@ -453,19 +580,78 @@ func (c *Controller) syncAccepted(release *Release) error {
 		}
 		switch {
 		case publishType.TagRef != nil:
+			previousTag := ""
+			if current := findTagReference(release.Target, publishType.TagRef.Name); current != nil && current.From != nil {
+				previousTag = current.From.Name
+			}
+			destination := fmt.Sprintf("%s/%s:%s", release.Target.Namespace, release.Target.Name, publishType.TagRef.Name)
 			if err := c.ensureTagPointsToRelease(release, publishType.TagRef.Name, newestAccepted.Name); err != nil {
 				errs = append(errs, fmt.Errorf("unable to update tag for publish step %s: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, err)
 				continue
 			}
+			if cfg := publishType.TagRef.Canary; cfg != nil && previousTag != newestAccepted.Name {
+				now := time.Now()
+				c.canaries.openWindow(release.Config.Name, name, publishType.TagRef.Name, newestAccepted.Name, previousTag, cfg, now)
+				if err := c.scheduleCanaryDeadline(release.Config.Name, name, publishType.TagRef.Name, previousTag, cfg.AutoRevert, now.Add(cfg.Window.Duration())); err != nil {
+					glog.Errorf("Unable to persist canary deadline for %s/%s, it will not survive a controller restart: %v", release.Config.Name, name, err)
+				}
+			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
 		case publishType.ImageStreamRef != nil:
-			ns := publishType.ImageStreamRef.Namespace
-			if len(ns) == 0 {
-				ns = release.Target.Namespace
+			namespace := publishType.ImageStreamRef.Namespace
+			if len(namespace) == 0 {
+				namespace = release.Target.Namespace
 			}
-			if err := c.ensureImageStreamMatchesRelease(release, ns, publishType.ImageStreamRef.Name, newestAccepted.Name, publishType.ImageStreamRef.Tags, publishType.ImageStreamRef.ExcludeTags); err != nil {
+			destination := fmt.Sprintf("%s/%s", namespace, publishType.ImageStreamRef.Name)
+			if err := c.ensureImageStreamMatchesRelease(release, publishType.ImageStreamRef, newestAccepted.Name); err != nil {
 				errs = append(errs, fmt.Errorf("unable to update image stream for publish step %s: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, err)
+				continue
+			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
+		case publishType.MirrorBundle != nil:
+			destination := publishType.MirrorBundle.ArtifactDir
+			if err := c.ensureMirrorBundle(release, newestAccepted.Name, publishType.MirrorBundle); err != nil {
+				errs = append(errs, fmt.Errorf("unable to write mirror bundle for publish step %s: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, err)
+				continue
+			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
+		case publishType.OCIArtifact != nil:
+			destination := filepath.Join(publishType.OCIArtifact.ArtifactDir, newestAccepted.Name)
+			if err := c.ensureOCIArtifact(release, newestAccepted.Name, publishType.OCIArtifact); err != nil {
+				errs = append(errs, fmt.Errorf("unable to write OCI artifact for publish step %s: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, err)
+				continue
+			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, destination, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
+		case publishType.Webhook != nil:
+			if err := c.ensureWebhookPublish(release, newestAccepted, publishType.Webhook); err != nil {
+				errs = append(errs, fmt.Errorf("unable to deliver webhook for publish step %s: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, publishType.Webhook.URL, err)
+				continue
+			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, publishType.Webhook.URL, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
+		case len(publishType.Type) > 0:
+			provider, ok := publishProviders[publishType.Type]
+			if !ok {
+				errs = append(errs, fmt.Errorf("publish step %s has unknown type %q", name, publishType.Type))
+				continue
+			}
+			ctx := &PublishContext{Controller: c, Release: release, Tag: newestAccepted}
+			if err := provider.Publish(ctx, publishType.CustomConfig); err != nil {
+				errs = append(errs, fmt.Errorf("publish step %s failed: %v", name, err))
+				c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, publishType.Type, err)
 				continue
 			}
+			c.publishHistory.record(release.Config.Name, name, newestAccepted.Name, publishType.Type, nil)
+			c.emitPublishedCloudEvent(release, name, newestAccepted.Name)
 		}
 	}
 	if len(errs) > 0 {
@@ -507,6 +693,9 @@ func containsString(arr []string, s string) bool {
 }
 
 func toJSONString(data interface{}) string {
+	defer func(start time.Time) {
+		annotationCodecDurationSeconds.WithLabelValues("encode").Observe(time.Since(start).Seconds())
+	}(time.Now())
 	out, err := json.Marshal(data)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// canaryCheckInterval is how often canaryLoop looks for windows whose
+// deadline has passed and need to be evaluated.
+const canaryCheckInterval = 30 * time.Second
+
+// CanaryFeedback is one consumer's health report against an open canary
+// window, e.g. an internal cluster tracking a PublishTagReference reporting
+// whether it came up healthy after the tag advanced.
+type CanaryFeedback struct {
+	Consumer string `json:"consumer"`
+	Healthy  bool   `json:"healthy"`
+	Message  string `json:"message,omitempty"`
+	At       string `json:"at"`
+}
+
+// canaryWindow tracks feedback collection for one advance of a
+// PublishTagReference's tag.
+type canaryWindow struct {
+	Stream      string `json:"stream"`
+	Publish     string `json:"publish"`
+	TagRef      string `json:"tagRef"`
+	Tag         string `json:"tag"`
+	PreviousTag string `json:"previousTag,omitempty"`
+	AutoRevert  bool   `json:"autoRevert"`
+
+	Opened   time.Time `json:"opened"`
+	Deadline time.Time `json:"deadline"`
+
+	Feedback []CanaryFeedback `json:"feedback,omitempty"`
+
+	// Result is set once the window has been evaluated: "Healthy", "Unhealthy",
+	// or "NoFeedback". An auto-revert after an Unhealthy result is not recorded
+	// here.
+	Result string `json:"result,omitempty"`
+}
+
+func canaryKey(stream, publish string) string {
+	return fmt.Sprintf("%s/%s", stream, publish)
+}
+
+// canaryDeadline is the payload persisted via scheduleDelayedAction for an
+// open canary window, so its deadline survives a controller restart instead
+// of being lost along with the rest of canaryTracker's in-memory state.
+// Feedback submitted by consumers is not part of this payload and so cannot
+// be reconstructed after a restart; a window restored this way is evaluated
+// as having received no feedback.
+type canaryDeadline struct {
+	Stream      string `json:"stream"`
+	Publish     string `json:"publish"`
+	TagRef      string `json:"tagRef"`
+	PreviousTag string `json:"previousTag,omitempty"`
+	AutoRevert  bool   `json:"autoRevert"`
+}
+
+// scheduleCanaryDeadline persists the deadline for a newly opened canary
+// window under the well-known delayed-action schedule.
+func (c *Controller) scheduleCanaryDeadline(stream, publish, tagRef, previousTag string, autoRevert bool, deadline time.Time) error {
+	payload, err := json.Marshal(canaryDeadline{Stream: stream, Publish: publish, TagRef: tagRef, PreviousTag: previousTag, AutoRevert: autoRevert})
+	if err != nil {
+		return err
+	}
+	return c.scheduleDelayedAction("canary/"+canaryKey(stream, publish), deadline, string(payload))
+}
+
+// canaryTracker holds canary consumer registrations and open feedback windows
+// in memory, per the repo's established advisory-tracker pattern (see
+// testBudgetTracker, tagIndexCache): consumer lists and reported feedback are
+// rebuildable from a fresh registration/publish cycle, so losing them on a
+// controller restart is acceptable. A window's deadline, however, is
+// separately persisted via scheduleCanaryDeadline/dueDelayedActions so it is
+// never silently lost or recomputed across a restart, even though the
+// feedback gathered toward it may be.
+type canaryTracker struct {
+	lock      sync.Mutex
+	consumers map[string]map[string]bool
+	windows   map[string]*canaryWindow
+}
+
+func newCanaryTracker() *canaryTracker {
+	return &canaryTracker{
+		consumers: make(map[string]map[string]bool),
+		windows:   make(map[string]*canaryWindow),
+	}
+}
+
+func (t *canaryTracker) registerConsumer(stream, name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.consumers[stream] == nil {
+		t.consumers[stream] = make(map[string]bool)
+	}
+	t.consumers[stream][name] = true
+}
+
+func (t *canaryTracker) isRegistered(stream, name string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.consumers[stream][name]
+}
+
+// openWindow starts feedback collection for a newly advanced tagRef, replacing
+// any prior window for the same stream/publish step.
+func (t *canaryTracker) openWindow(stream, publish, tagRef, tag, previousTag string, cfg *CanaryConfig, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.windows[canaryKey(stream, publish)] = &canaryWindow{
+		Stream:      stream,
+		Publish:     publish,
+		TagRef:      tagRef,
+		Tag:         tag,
+		PreviousTag: previousTag,
+		AutoRevert:  cfg.AutoRevert,
+		Opened:      now,
+		Deadline:    now.Add(cfg.Window.Duration()),
+	}
+}
+
+// recordFeedback appends a consumer's report to the open window for
+// stream/publish. It returns an error if no window is currently open, or if
+// consumer never registered for stream.
+func (t *canaryTracker) recordFeedback(stream, publish, consumer string, healthy bool, message string, now time.Time) error {
+	if !t.isRegistered(stream, consumer) {
+		return fmt.Errorf("consumer %s is not registered as a canary for %s", consumer, stream)
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	window, ok := t.windows[canaryKey(stream, publish)]
+	if !ok || window.Result != "" {
+		return fmt.Errorf("no open canary window for %s/%s", stream, publish)
+	}
+	window.Feedback = append(window.Feedback, CanaryFeedback{
+		Consumer: consumer,
+		Healthy:  healthy,
+		Message:  message,
+		At:       now.UTC().Format(time.RFC3339),
+	})
+	return nil
+}
+
+func (t *canaryTracker) snapshot(stream, publish string) (canaryWindow, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	window, ok := t.windows[canaryKey(stream, publish)]
+	if !ok {
+		return canaryWindow{}, false
+	}
+	return *window, true
+}
+
+// due returns, and marks evaluated, every window whose deadline has passed
+// and has not yet been evaluated.
+func (t *canaryTracker) due(now time.Time) []canaryWindow {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var due []canaryWindow
+	for _, window := range t.windows {
+		if window.Result != "" || now.Before(window.Deadline) {
+			continue
+		}
+		result := "Healthy"
+		switch {
+		case len(window.Feedback) == 0:
+			result = "NoFeedback"
+		default:
+			for _, f := range window.Feedback {
+				if !f.Healthy {
+					result = "Unhealthy"
+					break
+				}
+			}
+		}
+		window.Result = result
+		due = append(due, *window)
+	}
+	return due
+}
+
+// canaryLoop periodically evaluates expired canary windows, reverting the
+// associated PublishTagReference when AutoRevert is set and feedback was bad.
+func (c *Controller) canaryLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		now := time.Now()
+		for _, window := range c.canaries.due(now) {
+			id := "canary/" + canaryKey(window.Stream, window.Publish)
+			if err := c.cancelDelayedAction(id); err != nil {
+				glog.Errorf("Unable to clear persisted canary deadline for %s/%s: %v", window.Stream, window.Publish, err)
+			}
+			if window.Result != "Unhealthy" || !window.AutoRevert {
+				glog.V(4).Infof("Canary window for %s/%s closed with result %s", window.Stream, window.Publish, window.Result)
+				continue
+			}
+			c.revertCanaryTag(window.Stream, window.TagRef, window.PreviousTag)
+		}
+
+		// Pick up deadlines that outlived a controller restart and whose
+		// in-memory window (and any feedback reported against it) was lost.
+		// Without feedback there is nothing to judge the tag's health by, so
+		// these are simply cleared rather than auto-reverted; see
+		// canaryDeadline.
+		due, err := c.dueDelayedActions(now)
+		if err != nil {
+			glog.Errorf("Unable to check for persisted canary deadlines: %v", err)
+			return
+		}
+		for id, payload := range due {
+			var deadline canaryDeadline
+			if err := json.Unmarshal([]byte(payload), &deadline); err != nil {
+				glog.Errorf("Unable to decode persisted delayed action %s: %v", id, err)
+				continue
+			}
+			glog.V(2).Infof("Canary deadline for %s/%s expired after a controller restart with no feedback history to evaluate; taking no action", deadline.Stream, deadline.Publish)
+		}
+	}, canaryCheckInterval, stopCh)
+}
+
+// revertCanaryTag points tagRef back at previousTag after unhealthy canary
+// feedback, if one was recorded.
+func (c *Controller) revertCanaryTag(stream, tagRef, previousTag string) {
+	if len(previousTag) == 0 {
+		glog.V(2).Infof("Canary feedback for %s/%s was unhealthy but there is no previous tag to revert to", stream, tagRef)
+		return
+	}
+	streams, ok := c.findReleaseByName(false, stream)
+	if !ok {
+		glog.Errorf("Unable to find release %s to revert canary publish %s", stream, tagRef)
+		return
+	}
+	release := streams[stream].Release
+	if err := c.ensureTagPointsToRelease(release, tagRef, previousTag); err != nil {
+		glog.Errorf("Unable to revert %s tag %s after unhealthy canary feedback: %v", stream, tagRef, err)
+		return
+	}
+	glog.V(2).Infof("Reverted %s tag %s to %s after unhealthy canary feedback from consumers", stream, tagRef, previousTag)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// changelogConfigMapKind is the configMapJanitorKindLabel value for ConfigMaps
+// persisting a release's changelog, so RegisterConfigMapRetention can bound how
+// many of them accumulate in the job namespace.
+const changelogConfigMapKind = "changelog"
+
+// changelogConfigMapDataFrom and changelogConfigMapDataTo record the two tags a
+// persisted changelog compares, and changelogConfigMapDataChangelog the
+// rendered markdown itself.
+const (
+	changelogConfigMapDataFrom      = "from"
+	changelogConfigMapDataTo        = "to"
+	changelogConfigMapDataChangelog = "changelog"
+)
+
+// changelogConfigMapName returns the well-known ConfigMap name a release tag's
+// persisted changelog is stored under.
+func changelogConfigMapName(releaseTagName string) string {
+	return fmt.Sprintf("release-changelog-%s", releaseTagName)
+}
+
+// ensureReleaseChangelog generates the changelog from the most recently
+// Accepted release tag other than newTag to newTag and persists it to a
+// configMapJanitorKindLabel=changelogConfigMapKind ConfigMap, so
+// /releasestream/{release}/release/{tag}/changelog can serve it without
+// depending on the lazy, unpersisted /changelog endpoint or its cache. newTag
+// is expected to have just transitioned to Accepted; if it is the first
+// Accepted tag in the stream, there is nothing to compare against and this is
+// a no-op. Generation shells out to the same oc adm release info --changelog
+// subprocess behind /changelog, so it runs in a goroutine: a slow or stuck
+// subprocess must not hold up the sync loop acting on the next release.
+func (c *Controller) ensureReleaseChangelog(release *Release, newTag *imagev1.TagReference) {
+	if c.configMapClient == nil {
+		return
+	}
+	// callers mark newTag Accepted before calling this, so it is skipped here
+	var from string
+	for _, tag := range findTagReferencesByPhase(release, releasePhaseAccepted) {
+		if tag.Name != newTag.Name {
+			from = tag.Name
+			break
+		}
+	}
+	if len(from) == 0 {
+		return
+	}
+	base := release.Target.Status.PublicDockerImageRepository
+	if len(base) == 0 {
+		return
+	}
+	to := newTag.Name
+
+	go func() {
+		out, err := c.releaseInfo.ChangeLog(fmt.Sprintf("%s:%s", base, from), fmt.Sprintf("%s:%s", base, to))
+		if err != nil {
+			glog.V(4).Infof("Unable to generate changelog for accepted release %s: %v", to, err)
+			return
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      changelogConfigMapName(to),
+				Namespace: c.jobNamespace,
+				Labels: map[string]string{
+					configMapJanitorKindLabel: changelogConfigMapKind,
+				},
+				Annotations: map[string]string{
+					releaseAnnotationSource:     fmt.Sprintf("%s/%s", release.Source.Namespace, release.Source.Name),
+					releaseAnnotationReleaseTag: to,
+				},
+			},
+			Data: map[string]string{
+				changelogConfigMapDataFrom:      from,
+				changelogConfigMapDataTo:        to,
+				changelogConfigMapDataChangelog: out,
+			},
+		}
+		if _, err := c.configMapClient.ConfigMaps(c.jobNamespace).Create(cm); err != nil && !errors.IsAlreadyExists(err) {
+			glog.V(4).Infof("Unable to persist changelog configmap for accepted release %s: %v", to, err)
+		}
+	}()
+}
+
+// releaseChangelog returns the persisted changelog for releaseTagName, if one
+// has been generated, along with the tag it was compared against.
+func (c *Controller) releaseChangelog(releaseTagName string) (from, changelog string, ok bool, err error) {
+	if c.configMapClient == nil {
+		return "", "", false, nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(changelogConfigMapName(releaseTagName), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+	return cm.Data[changelogConfigMapDataFrom], cm.Data[changelogConfigMapDataChangelog], true, nil
+}
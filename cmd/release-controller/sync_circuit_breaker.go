@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	corev1 "k8s.io/api/core/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// circuitBreakerTracker remembers, per stream, that an operator asked to
+// resume payload creation despite an open acceptance circuit breaker. Like
+// the other in-memory advisory trackers in this package, losing this on a
+// controller restart just means the operator has to click resume again.
+type circuitBreakerTracker struct {
+	lock    sync.Mutex
+	resumed map[string]bool
+}
+
+func newCircuitBreakerTracker() *circuitBreakerTracker {
+	return &circuitBreakerTracker{resumed: make(map[string]bool)}
+}
+
+// resume grants one bypass of the breaker for name.
+func (t *circuitBreakerTracker) resume(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.resumed[name] = true
+}
+
+// consume reports and clears whether name currently has a bypass pending.
+func (t *circuitBreakerTracker) consume(name string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.resumed[name] {
+		return false
+	}
+	delete(t.resumed, name)
+	return true
+}
+
+// consecutiveRejections counts release's own tags, newest first, that are
+// Rejected, stopping at the first tag that is not.
+func consecutiveRejections(release *Release) int {
+	tags := make([]*imagev1.TagReference, 0, len(release.Target.Spec.Tags))
+	for i := range release.Target.Spec.Tags {
+		tags = append(tags, &release.Target.Spec.Tags[i])
+	}
+	sort.Sort(tagReferencesByAge(tags))
+
+	var count int
+	for _, tag := range tags {
+		if tag.Annotations[releaseAnnotationName] != release.Config.Name {
+			continue
+		}
+		if tag.Annotations[releaseAnnotationPhase] != releasePhaseRejected {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// acceptanceCircuitOpen reports whether release's acceptance circuit breaker
+// is configured and tripped by its most recent payloads.
+func acceptanceCircuitOpen(release *Release) (open bool, count int) {
+	breaker := release.Config.AcceptanceCircuitBreaker
+	if breaker == nil || breaker.ConsecutiveRejections <= 0 {
+		return false, 0
+	}
+	count = consecutiveRejections(release)
+	return count >= breaker.ConsecutiveRejections, count
+}
+
+// checkAcceptanceCircuitBreaker reports whether sync should skip creating a
+// new payload for release because its acceptance circuit breaker is open. A
+// pending resume request from httpResumeCircuitBreaker bypasses it exactly
+// once; if that one payload is rejected too, the breaker trips again.
+func (c *Controller) checkAcceptanceCircuitBreaker(release *Release) bool {
+	open, count := acceptanceCircuitOpen(release)
+	if !open {
+		return false
+	}
+	if c.circuitBreaker.consume(release.Config.Name) {
+		glog.V(2).Infof("Acceptance circuit breaker for %s bypassed by resume request", release.Config.Name)
+		return false
+	}
+	c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "AcceptanceCircuitBreakerOpen",
+		"%d consecutive payloads have been rejected, pausing new payload creation until resumed via POST /api/v1/releasestream/%s/circuitbreaker/resume%s",
+		count, release.Config.Name, ownerSuffix(release.Config.Owners))
+	return true
+}
+
+// httpResumeCircuitBreaker grants one bypass of an open acceptance circuit
+// breaker for {release}, allowing exactly one more payload to be created
+// even though the breaker would otherwise still be open.
+func (c *Controller) httpResumeCircuitBreaker(w http.ResponseWriter, req *http.Request) {
+	release := mux.Vars(req)["release"]
+	if len(release) == 0 {
+		http.Error(w, "release is required", http.StatusBadRequest)
+		return
+	}
+	c.circuitBreaker.resume(release)
+	w.WriteHeader(http.StatusNoContent)
+}
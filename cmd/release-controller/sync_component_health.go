@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// componentHealthRecheckInterval is how long sync() waits before re-checking
+// component health after deferring release creation because of it.
+const componentHealthRecheckInterval = 5 * time.Minute
+
+var componentHealthClient = &http.Client{Timeout: 10 * time.Second}
+
+// unhealthyComponents returns the names of critical components that are
+// currently reported unhealthy for release, consulting
+// releaseAnnotationComponentHealth on the source image stream first and
+// falling back to ComponentHealthGate.Endpoint. It returns an error only if
+// a configured health source could not be read; callers should treat that as
+// "unknown" and not block creation on it indefinitely.
+func (c *Controller) unhealthyComponents(release *Release) ([]string, error) {
+	gate := release.Config.ComponentHealthGate
+	if gate == nil {
+		return nil, nil
+	}
+
+	health, err := componentHealthFromAnnotation(release.Source)
+	if err != nil {
+		return nil, err
+	}
+	if health == nil {
+		if len(gate.Endpoint) == 0 {
+			return nil, nil
+		}
+		health, err = componentHealthFromEndpoint(gate.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	critical := sets.NewString(gate.CriticalComponents...)
+	var unhealthy []string
+	for name, healthy := range health {
+		if healthy {
+			continue
+		}
+		if critical.Len() > 0 && !critical.Has(name) {
+			continue
+		}
+		unhealthy = append(unhealthy, name)
+	}
+	sort.Strings(unhealthy)
+	return unhealthy, nil
+}
+
+func componentHealthFromAnnotation(source *imagev1.ImageStream) (map[string]bool, error) {
+	data := source.Annotations[releaseAnnotationComponentHealth]
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var health map[string]bool
+	if err := json.Unmarshal([]byte(data), &health); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", releaseAnnotationComponentHealth, err)
+	}
+	return health, nil
+}
+
+func componentHealthFromEndpoint(endpoint string) (map[string]bool, error) {
+	resp, err := componentHealthClient.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not query component health endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("component health endpoint returned %s", resp.Status)
+	}
+	var health map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("could not decode component health response: %v", err)
+	}
+	return health, nil
+}
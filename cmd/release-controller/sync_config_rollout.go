@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// rolloutCheckInterval is how often rolloutLoop looks for canary bake windows
+// whose deadline has passed and are ready to roll out to the rest of their
+// streams.
+const rolloutCheckInterval = 30 * time.Second
+
+// ConfigRolloutSpec describes an admin-triggered rollout of a ReleaseConfig
+// change across every release stream whose Config.Name has NamePrefix,
+// applied as an RFC 7396 JSON merge patch (see applyJSONMergePatch). The
+// patch is applied to one canary stream first; only after it has run for
+// BakeTime without the rollout being cancelled is it applied to the rest.
+//
+// This only rolls out to streams whose ReleaseConfig is read from the
+// releaseAnnotationConfig annotation. Streams backed by a ReleaseConfig
+// custom resource (see release_config_crd.go) are skipped, since there is no
+// well-defined way to merge-patch an arbitrary custom resource spec.
+type ConfigRolloutSpec struct {
+	NamePrefix string          `json:"namePrefix"`
+	Patch      json.RawMessage `json:"patch"`
+	BakeTime   Duration        `json:"bakeTime"`
+}
+
+// ConfigRolloutStatus is the current state of an admin-triggered config
+// rollout, keyed by NamePrefix in rolloutTracker.
+type ConfigRolloutStatus struct {
+	Spec ConfigRolloutSpec `json:"spec"`
+
+	// Phase is one of "Canary" (patch applied to Canary and baking),
+	// "RollingOut" (bake complete, applying to Remaining), "Complete"
+	// (applied everywhere), or "Failed" (applying the patch to Canary or to a
+	// remaining stream returned an error; see Message).
+	Phase string `json:"phase"`
+
+	Canary    string    `json:"canary"`
+	Remaining []string  `json:"remaining,omitempty"`
+	Completed []string  `json:"completed,omitempty"`
+	Deadline  time.Time `json:"deadline,omitempty"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// rolloutTracker holds in-progress ConfigRollouts in memory, per the repo's
+// established advisory-tracker pattern (see canaryTracker). A rollout's bake
+// deadline is separately persisted via scheduleConfigRolloutDeadline so that,
+// unlike the rest of a rollout's in-memory state, it survives a controller
+// restart and the rollout still completes rather than stalling forever in
+// the Canary phase.
+type rolloutTracker struct {
+	lock     sync.Mutex
+	rollouts map[string]*ConfigRolloutStatus
+}
+
+func newRolloutTracker() *rolloutTracker {
+	return &rolloutTracker{rollouts: make(map[string]*ConfigRolloutStatus)}
+}
+
+func (t *rolloutTracker) start(prefix string, status *ConfigRolloutStatus) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.rollouts[prefix] = status
+}
+
+func (t *rolloutTracker) snapshot(prefix string) (ConfigRolloutStatus, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	status, ok := t.rollouts[prefix]
+	if !ok {
+		return ConfigRolloutStatus{}, false
+	}
+	return *status, true
+}
+
+func (t *rolloutTracker) cancel(prefix string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.rollouts[prefix]; !ok {
+		return false
+	}
+	delete(t.rollouts, prefix)
+	return true
+}
+
+// due returns a snapshot of every rollout still in the Canary phase whose
+// bake deadline has passed, marking each RollingOut so it is not returned
+// again on the next check.
+func (t *rolloutTracker) due(now time.Time) []ConfigRolloutStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var due []ConfigRolloutStatus
+	for _, status := range t.rollouts {
+		if status.Phase != "Canary" || now.Before(status.Deadline) {
+			continue
+		}
+		status.Phase = "RollingOut"
+		due = append(due, *status)
+	}
+	return due
+}
+
+// finish records the outcome of rolling out to a rollout's Remaining streams.
+func (t *rolloutTracker) finish(prefix string, completed []string, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	status, ok := t.rollouts[prefix]
+	if !ok {
+		return
+	}
+	status.Completed = completed
+	if err != nil {
+		status.Phase = "Failed"
+		status.Message = err.Error()
+		return
+	}
+	status.Phase = "Complete"
+	status.Remaining = nil
+}
+
+// configRolloutDeadline is the payload persisted via scheduleDelayedAction
+// for an open rollout's bake window, carrying everything needed to finish
+// the rollout even if the controller restarts and rolloutTracker's in-memory
+// state is lost.
+type configRolloutDeadline struct {
+	NamePrefix string          `json:"namePrefix"`
+	Remaining  []string        `json:"remaining,omitempty"`
+	Patch      json.RawMessage `json:"patch"`
+}
+
+// scheduleConfigRolloutDeadline persists the bake deadline for a newly
+// started rollout under the well-known delayed-action schedule.
+func (c *Controller) scheduleConfigRolloutDeadline(status *ConfigRolloutStatus) error {
+	payload, err := json.Marshal(configRolloutDeadline{
+		NamePrefix: status.Spec.NamePrefix,
+		Remaining:  status.Remaining,
+		Patch:      status.Spec.Patch,
+	})
+	if err != nil {
+		return err
+	}
+	return c.scheduleDelayedAction(configRolloutDeadlineID(status.Spec.NamePrefix), status.Deadline, string(payload))
+}
+
+func configRolloutDeadlineID(prefix string) string {
+	return "configrollout/" + prefix
+}
+
+// matchingConfigRolloutStreams returns every release whose Config.Name has
+// prefix, sorted by name so a rollout's choice of canary (the first result)
+// is deterministic.
+func (c *Controller) matchingConfigRolloutStreams(prefix string) ([]*Release, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var matched []*Release
+	for _, stream := range imageStreams {
+		r, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		if strings.HasPrefix(r.Config.Name, prefix) {
+			matched = append(matched, r)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Config.Name < matched[j].Config.Name })
+	return matched, nil
+}
+
+// applyConfigRolloutPatch merge-patches release's releaseAnnotationConfig
+// annotation with patch, validates the result via c.parseReleaseConfig, and
+// persists it to release.Source if valid.
+func (c *Controller) applyConfigRolloutPatch(release *Release, patch []byte) error {
+	original, ok := release.Source.Annotations[releaseAnnotationConfig]
+	if !ok {
+		return fmt.Errorf("release %s has no %s annotation to patch (its ReleaseConfig may be defined by a custom resource instead)", release.Config.Name, releaseAnnotationConfig)
+	}
+	merged, err := applyJSONMergePatch([]byte(original), patch)
+	if err != nil {
+		return fmt.Errorf("unable to apply config rollout patch to %s: %v", release.Config.Name, err)
+	}
+	if _, err := c.parseReleaseConfig(string(merged)); err != nil {
+		return fmt.Errorf("patched config for %s is invalid: %v", release.Config.Name, err)
+	}
+	target := release.Source.DeepCopy()
+	target.Annotations[releaseAnnotationConfig] = string(merged)
+	_, err = c.imageClient.ImageStreams(target.Namespace).Update(target)
+	return err
+}
+
+// applyJSONMergePatch applies an RFC 7396 JSON Merge Patch to original. The
+// repo vendors no library that applies (as opposed to creates) a merge
+// patch, so this is a small hand-written implementation of the standard's
+// merge algorithm: an object is merged key by key, a null value deletes the
+// key, and any other value (including an array) replaces it wholesale.
+func applyJSONMergePatch(original, patch []byte) ([]byte, error) {
+	var originalValue map[string]interface{}
+	if err := json.Unmarshal(original, &originalValue); err != nil {
+		return nil, fmt.Errorf("unable to parse original document: %v", err)
+	}
+	var patchValue map[string]interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("unable to parse patch: %v", err)
+	}
+	return json.Marshal(mergeJSONMergePatch(originalValue, patchValue))
+}
+
+func mergeJSONMergePatch(original, patch map[string]interface{}) map[string]interface{} {
+	if original == nil {
+		original = make(map[string]interface{})
+	}
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(original, key)
+			continue
+		}
+		patchChild, patchIsObject := patchValue.(map[string]interface{})
+		originalChild, originalIsObject := original[key].(map[string]interface{})
+		if patchIsObject && originalIsObject {
+			original[key] = mergeJSONMergePatch(originalChild, patchChild)
+			continue
+		}
+		original[key] = patchValue
+	}
+	return original
+}
+
+// rollOutRemaining applies patch to every named stream found in streams,
+// stopping at the first failure, and returns the names it successfully
+// patched.
+func rollOutRemaining(c *Controller, streams []*Release, remaining []string, patch []byte) ([]string, error) {
+	byName := make(map[string]*Release, len(streams))
+	for _, r := range streams {
+		byName[r.Config.Name] = r
+	}
+	var completed []string
+	for _, name := range remaining {
+		r, ok := byName[name]
+		if !ok {
+			// stream was deleted since the rollout started
+			continue
+		}
+		if err := c.applyConfigRolloutPatch(r, patch); err != nil {
+			return completed, fmt.Errorf("failed on stream %s: %v", name, err)
+		}
+		completed = append(completed, name)
+	}
+	return completed, nil
+}
+
+// rolloutLoop periodically rolls out a config rollout's patch to its
+// Remaining streams once the canary's bake deadline has passed.
+func (c *Controller) rolloutLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		now := time.Now()
+		for _, status := range c.rollouts.due(now) {
+			prefix := status.Spec.NamePrefix
+			if err := c.cancelDelayedAction(configRolloutDeadlineID(prefix)); err != nil {
+				glog.Errorf("Unable to clear persisted config rollout deadline for %s: %v", prefix, err)
+			}
+			streams, err := c.matchingConfigRolloutStreams(prefix)
+			if err != nil {
+				glog.Errorf("Unable to roll out config change for %s: %v", prefix, err)
+				c.rollouts.finish(prefix, nil, err)
+				continue
+			}
+			completed, rolloutErr := rollOutRemaining(c, streams, status.Remaining, status.Spec.Patch)
+			c.rollouts.finish(prefix, completed, rolloutErr)
+			if rolloutErr != nil {
+				glog.Errorf("Config rollout for %s stopped partway through: %v", prefix, rolloutErr)
+			} else {
+				glog.V(2).Infof("Config rollout for %s completed across %d streams", prefix, len(completed)+1)
+			}
+		}
+
+		// Finish rollouts whose bake deadline outlived a controller restart
+		// and whose in-memory status was lost; the persisted payload carries
+		// everything needed to still complete them.
+		due, err := c.dueDelayedActions(now)
+		if err != nil {
+			glog.Errorf("Unable to check for persisted config rollout deadlines: %v", err)
+			return
+		}
+		for id, payload := range due {
+			if !strings.HasPrefix(id, "configrollout/") {
+				continue
+			}
+			var deadline configRolloutDeadline
+			if err := json.Unmarshal([]byte(payload), &deadline); err != nil {
+				glog.Errorf("Unable to decode persisted delayed action %s: %v", id, err)
+				continue
+			}
+			streams, err := c.matchingConfigRolloutStreams(deadline.NamePrefix)
+			if err != nil {
+				glog.Errorf("Unable to roll out config change for %s after a controller restart: %v", deadline.NamePrefix, err)
+				continue
+			}
+			completed, rolloutErr := rollOutRemaining(c, streams, deadline.Remaining, deadline.Patch)
+			if rolloutErr != nil {
+				glog.Errorf("Config rollout for %s (resumed after a controller restart) stopped partway through: %v", deadline.NamePrefix, rolloutErr)
+				continue
+			}
+			glog.V(2).Infof("Config rollout for %s (resumed after a controller restart) completed across %d streams", deadline.NamePrefix, len(completed)+1)
+		}
+	}, rolloutCheckInterval, stopCh)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// payloadFreezeConfigMapName is the well-known ConfigMap, in the controller's
+// job namespace, that release captains use to coordinate a payload freeze: a
+// minor version family (e.g. "4.15") whose new stable promotions and tagRef
+// publish steps are held while verification keeps running as normal, so a
+// captain doesn't have to track the freeze in chat with no enforcement behind
+// it. Like delayedActionConfigMapName, persistence is optional: if no
+// ConfigMap client is configured, freezing becomes a no-op.
+const payloadFreezeConfigMapName = "release-controller-freeze"
+
+// minorVersionKey returns the payloadFreezeConfigMapName data key for a
+// release's major.minor version family.
+func minorVersionKey(major, minor uint64) string {
+	return fmt.Sprintf("%d.%d", major, minor)
+}
+
+// minorVersionFreezeReason returns the freeze reason a release captain
+// recorded for major.minor, or "" if that version family is not frozen. If no
+// ConfigMap client is configured it always returns unfrozen.
+func (c *Controller) minorVersionFreezeReason(major, minor uint64) (string, error) {
+	if c.configMapClient == nil {
+		return "", nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(payloadFreezeConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return cm.Data[minorVersionKey(major, minor)], nil
+}
+
+// activeFreezes returns every currently frozen major.minor version family and
+// its reason, for the admin freeze listing endpoint.
+func (c *Controller) activeFreezes() (map[string]string, error) {
+	if c.configMapClient == nil {
+		return nil, nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(payloadFreezeConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm.Data, nil
+}
+
+// setMinorVersionFreeze records reason as why minorVersion (e.g. "4.15") is
+// frozen, blocking new stable promotions and tagRef publish steps for that
+// version family until clearMinorVersionFreeze is called.
+func (c *Controller) setMinorVersionFreeze(minorVersion, reason string) error {
+	return c.updateFreezeConfigMap(func(cm *corev1.ConfigMap) {
+		cm.Data[minorVersion] = reason
+	})
+}
+
+// clearMinorVersionFreeze lifts a freeze previously recorded by
+// setMinorVersionFreeze. Clearing a version that isn't frozen is a no-op.
+func (c *Controller) clearMinorVersionFreeze(minorVersion string) error {
+	return c.updateFreezeConfigMap(func(cm *corev1.ConfigMap) {
+		delete(cm.Data, minorVersion)
+	})
+}
+
+// updateFreezeConfigMap fetches or creates payloadFreezeConfigMapName, applies
+// mutate, and writes it back. If no ConfigMap client is configured, freezing
+// is a no-op, matching the read-path behavior in minorVersionFreezeReason and
+// activeFreezes.
+func (c *Controller) updateFreezeConfigMap(mutate func(cm *corev1.ConfigMap)) error {
+	if c.configMapClient == nil {
+		return nil
+	}
+	client := c.configMapClient.ConfigMaps(c.jobNamespace)
+	cm, err := client.Get(payloadFreezeConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: payloadFreezeConfigMapName, Namespace: c.jobNamespace},
+			Data:       make(map[string]string),
+		}
+		mutate(cm)
+		_, err := client.Create(cm)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	mutate(cm)
+	_, err = client.Update(cm)
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestMinorVersionKey(t *testing.T) {
+	if got, want := minorVersionKey(4, 15), "4.15"; got != want {
+		t.Errorf("minorVersionKey(4, 15) = %q, want %q", got, want)
+	}
+}
+
+func TestMinorVersionFreezeReason_NilClient(t *testing.T) {
+	c := &Controller{}
+	reason, err := c.minorVersionFreezeReason(4, 15)
+	if err != nil {
+		t.Fatalf("minorVersionFreezeReason() error = %v, want nil", err)
+	}
+	if reason != "" {
+		t.Errorf("minorVersionFreezeReason() = %q, want \"\" when no configmap client is configured", reason)
+	}
+}
+
+func TestActiveFreezes_NilClient(t *testing.T) {
+	c := &Controller{}
+	freezes, err := c.activeFreezes()
+	if err != nil {
+		t.Fatalf("activeFreezes() error = %v, want nil", err)
+	}
+	if freezes != nil {
+		t.Errorf("activeFreezes() = %v, want nil when no configmap client is configured", freezes)
+	}
+}
+
+func TestSetMinorVersionFreeze_NilClient(t *testing.T) {
+	c := &Controller{}
+	if err := c.setMinorVersionFreeze("4.15", "investigating a regression"); err != nil {
+		t.Errorf("setMinorVersionFreeze() error = %v, want nil when no configmap client is configured", err)
+	}
+}
+
+func TestClearMinorVersionFreeze_NilClient(t *testing.T) {
+	c := &Controller{}
+	if err := c.clearMinorVersionFreeze("4.15"); err != nil {
+		t.Errorf("clearMinorVersionFreeze() error = %v, want nil when no configmap client is configured", err)
+	}
+}
@@ -1,42 +1,126 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"time"
 
 	"github.com/golang/glog"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// GCCandidateKind identifies the kind of object a GCCandidate refers to.
+type GCCandidateKind string
+
+const (
+	GCCandidateJob       GCCandidateKind = "Job"
+	GCCandidateMirror    GCCandidateKind = "Mirror"
+	GCCandidateConfigMap GCCandidateKind = "ConfigMap"
+)
+
+// configMapJanitorKindLabel is the label a subsystem sets on every ConfigMap it
+// owns, so the janitor can find and independently retain each kind of
+// ConfigMap it's registered a ConfigMapRetentionPolicy for. See
+// Controller.RegisterConfigMapRetention.
+const configMapJanitorKindLabel = "release.openshift.io/janitor-kind"
+
+// ConfigMapRetentionPolicy bounds how many release.openshift.io/janitor-kind
+// labelled ConfigMaps of one kind, and for how long, the janitor keeps before
+// the oldest become GC candidates. A zero MaxAge or MaxCount means that bound
+// is not enforced; leaving both zero keeps every ConfigMap of that kind
+// forever.
+type ConfigMapRetentionPolicy struct {
+	MaxAge   time.Duration
+	MaxCount int
+}
+
+// GCCandidate describes a single object the next garbage collection cycle would
+// delete and why, so it can be reported by /api/v1/gc/preview before anything is
+// actually removed.
+type GCCandidate struct {
+	Kind      GCCandidateKind `json:"kind"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Reason    string          `json:"reason"`
+}
+
 // garbageCollectSync checks for unreferenced objects and deletes them. Because this can run
 // concurrently with the main sync loop, we rely on generational markers on resources to
-// know whether to delete the objects.
+// know whether to delete the objects. If c.gcDryRun is set, candidates are logged but
+// nothing is deleted.
 func (c *Controller) garbageCollectSync() error {
 	defer func() {
 		err := recover()
 		panic(err)
 	}()
 
-	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	candidates, err := c.planGarbageCollection()
 	if err != nil {
 		return err
 	}
+
+	if c.gcDryRun {
+		if len(candidates) > 0 {
+			glog.V(2).Infof("gc-dry-run: would delete %d objects: %v", len(candidates), candidates)
+		}
+		return nil
+	}
+
+	for _, candidate := range candidates {
+		switch candidate.Kind {
+		case GCCandidateJob:
+			glog.V(2).Infof("Removing release job %s: %s", candidate.Name, candidate.Reason)
+			if err := c.jobClient.Jobs(candidate.Namespace).Delete(candidate.Name, nil); err != nil && !errors.IsNotFound(err) {
+				utilruntime.HandleError(fmt.Errorf("can't delete release job %s: %v", candidate.Name, err))
+			}
+		case GCCandidateMirror:
+			glog.V(2).Infof("Removing release mirror %s: %s", candidate.Name, candidate.Reason)
+			if err := c.imageClient.ImageStreams(candidate.Namespace).Delete(candidate.Name, nil); err != nil && !errors.IsNotFound(err) {
+				utilruntime.HandleError(fmt.Errorf("can't delete release mirror %s: %v", candidate.Name, err))
+			}
+		case GCCandidateConfigMap:
+			if c.configMapClient == nil {
+				continue
+			}
+			glog.V(2).Infof("Removing janitor-owned configmap %s: %s", candidate.Name, candidate.Reason)
+			if err := c.configMapClient.ConfigMaps(candidate.Namespace).Delete(candidate.Name, nil); err != nil && !errors.IsNotFound(err) {
+				utilruntime.HandleError(fmt.Errorf("can't delete configmap %s: %v", candidate.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// planGarbageCollection computes the jobs, image stream mirrors and janitor-owned
+// ConfigMaps the next garbage collection cycle would delete, without deleting anything. It is the shared logic
+// behind garbageCollectSync and httpGCPreview, so the preview can never drift from
+// what GC actually does.
+func (c *Controller) planGarbageCollection() ([]GCCandidate, error) {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
 	jobs, err := c.jobLister.List(labels.Everything())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	mirrors, err := c.imageStreamLister.List(labels.Everything())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// find all valid releases and targets
 	active := sets.NewString()
 	targets := make(map[string]int64)
+	configs := make(map[string]*ReleaseConfig)
 	for _, imageStream := range imageStreams {
 		if _, ok := imageStream.Annotations[releaseAnnotationHasReleases]; ok {
 			for _, tag := range imageStream.Spec.Tags {
@@ -54,6 +138,14 @@ func (c *Controller) garbageCollectSync() error {
 		if err != nil {
 			continue
 		}
+		// key by the target image stream, since that's what mirrors record in
+		// releaseAnnotationTarget; for Integration/Hotfix streams that's config.To,
+		// for Stable streams the source and target are the same stream.
+		targetKey := fmt.Sprintf("%s/%s", imageStream.Namespace, imageStream.Name)
+		if config.As != releaseConfigModeStable && len(config.To) > 0 {
+			targetKey = fmt.Sprintf("%s/%s", imageStream.Namespace, config.To)
+		}
+		configs[targetKey] = config
 		if config.As == releaseConfigModeStable {
 			for _, tag := range imageStream.Spec.Tags {
 				active.Insert(tag.Name)
@@ -62,6 +154,8 @@ func (c *Controller) garbageCollectSync() error {
 		}
 	}
 
+	var candidates []GCCandidate
+
 	// all jobs created for a release that no longer exists should be deleted
 	for _, job := range jobs {
 		if active.Has(job.Annotations[releaseAnnotationReleaseTag]) {
@@ -76,23 +170,28 @@ func (c *Controller) garbageCollectSync() error {
 			continue
 		}
 		if generation < targetGeneration {
-			glog.V(2).Infof("Removing orphaned release job %s", job.Name)
-			if err := c.jobClient.Jobs(job.Namespace).Delete(job.Name, nil); err != nil && !errors.IsNotFound(err) {
-				utilruntime.HandleError(fmt.Errorf("can't delete orphaned release job %s: %v", job.Name, err))
-			}
+			candidates = append(candidates, GCCandidate{Kind: GCCandidateJob, Namespace: job.Namespace, Name: job.Name, Reason: "orphaned: release target has moved to a newer generation"})
 			continue
 		}
 		if job.Status.CompletionTime != nil && job.Status.CompletionTime.Time.Before(time.Now().Add(-2*time.Hour)) {
-			glog.V(2).Infof("Removing old completed release job %s", job.Name)
-			if err := c.jobClient.Jobs(job.Namespace).Delete(job.Name, nil); err != nil && !errors.IsNotFound(err) {
-				utilruntime.HandleError(fmt.Errorf("can't delete old release job %s: %v", job.Name, err))
-			}
+			candidates = append(candidates, GCCandidate{Kind: GCCandidateJob, Namespace: job.Namespace, Name: job.Name, Reason: "completed more than 2h ago"})
 			continue
 		}
 	}
 
 	// all image mirrors created for a release that no longer exists should be deleted
 	for _, mirror := range mirrors {
+		// a mirror whose release has configured a MirrorTTL is collected once that TTL
+		// elapses, even if its release tag is still active, so long-lived Accepted tags
+		// don't pin mirrored content in the registry forever.
+		if config, ok := configs[mirror.Annotations[releaseAnnotationTarget]]; ok {
+			if ttl := config.MirrorTTL.Duration(); ttl > 0 && !mirror.CreationTimestamp.IsZero() {
+				if age := time.Since(mirror.CreationTimestamp.Time); age > ttl {
+					candidates = append(candidates, GCCandidate{Kind: GCCandidateMirror, Namespace: mirror.Namespace, Name: mirror.Name, Reason: fmt.Sprintf("exceeded configured mirror TTL of %s", ttl)})
+					continue
+				}
+			}
+		}
 		if active.Has(mirror.Annotations[releaseAnnotationReleaseTag]) {
 			continue
 		}
@@ -105,11 +204,124 @@ func (c *Controller) garbageCollectSync() error {
 			continue
 		}
 		if generation < targetGeneration {
-			glog.V(2).Infof("Removing orphaned release mirror %s", mirror.Name)
-			if err := c.imageClient.ImageStreams(mirror.Namespace).Delete(mirror.Name, nil); err != nil && !errors.IsNotFound(err) {
-				utilruntime.HandleError(fmt.Errorf("can't delete orphaned release mirror %s: %v", mirror.Name, err))
+			candidates = append(candidates, GCCandidate{Kind: GCCandidateMirror, Namespace: mirror.Namespace, Name: mirror.Name, Reason: "orphaned: release target has moved to a newer generation"})
+		}
+	}
+
+	cmCandidates, err := c.planConfigMapGarbageCollection()
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, cmCandidates...)
+
+	return candidates, nil
+}
+
+// JanitorKindReport summarizes one ConfigMapRetentionPolicy-registered kind of
+// janitor-owned ConfigMap, for /api/v1/admin/janitor: how many currently exist,
+// the policy retaining them, and which ones the next garbage collection cycle
+// would delete.
+type JanitorKindReport struct {
+	Kind       string                   `json:"kind"`
+	Policy     ConfigMapRetentionPolicy `json:"policy"`
+	Count      int                      `json:"count"`
+	Candidates []GCCandidate            `json:"candidates,omitempty"`
+}
+
+// configMapJanitorReport computes, for every kind a subsystem has registered a
+// ConfigMapRetentionPolicy for via RegisterConfigMapRetention, the live count of
+// that kind's ConfigMaps and which of them the next garbage collection cycle
+// would delete. It is the shared logic behind planConfigMapGarbageCollection and
+// httpJanitorReport, so the report can never drift from what the janitor
+// actually does, the same way planGarbageCollection backs httpGCPreview. It also
+// updates configMapJanitorObjectsTotal, so that metric stays current even
+// between full garbage collection passes.
+func (c *Controller) configMapJanitorReport() ([]JanitorKindReport, error) {
+	if c.configMapClient == nil {
+		return nil, nil
+	}
+	kinds := make([]string, 0, len(c.configMapRetention))
+	for kind := range c.configMapRetention {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	reports := make([]JanitorKindReport, 0, len(kinds))
+	for _, kind := range kinds {
+		policy := c.configMapRetention[kind]
+		list, err := c.configMapClient.ConfigMaps(c.jobNamespace).List(metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", configMapJanitorKindLabel, kind),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s configmaps for janitor: %v", kind, err)
+		}
+		configMapJanitorObjectsTotal.WithLabelValues(kind).Set(float64(len(list.Items)))
+
+		items := list.Items
+		sort.Slice(items, func(i, j int) bool { return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp) })
+		report := JanitorKindReport{Kind: kind, Policy: policy, Count: len(items)}
+		for i, cm := range items {
+			switch {
+			case policy.MaxAge > 0 && !cm.CreationTimestamp.IsZero() && time.Since(cm.CreationTimestamp.Time) > policy.MaxAge:
+				report.Candidates = append(report.Candidates, GCCandidate{Kind: GCCandidateConfigMap, Namespace: cm.Namespace, Name: cm.Name, Reason: fmt.Sprintf("%s configmap exceeded max age of %s", kind, policy.MaxAge)})
+			// items is oldest-first, so the oldest len(items)-MaxCount entries are
+			// the ones over the retained count.
+			case policy.MaxCount > 0 && i < len(items)-policy.MaxCount:
+				report.Candidates = append(report.Candidates, GCCandidate{Kind: GCCandidateConfigMap, Namespace: cm.Namespace, Name: cm.Name, Reason: fmt.Sprintf("%s configmap count exceeds retained max of %d", kind, policy.MaxCount)})
 			}
 		}
+		reports = append(reports, report)
 	}
-	return nil
+	return reports, nil
+}
+
+// planConfigMapGarbageCollection computes the janitor-owned ConfigMaps the next
+// garbage collection cycle would delete, across every registered kind.
+func (c *Controller) planConfigMapGarbageCollection() ([]GCCandidate, error) {
+	reports, err := c.configMapJanitorReport()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []GCCandidate
+	for _, report := range reports {
+		candidates = append(candidates, report.Candidates...)
+	}
+	return candidates, nil
+}
+
+// httpJanitorReport reports, for every kind of ConfigMap a subsystem has
+// registered via RegisterConfigMapRetention, how many currently exist and which
+// ones the next garbage collection cycle would delete, so an operator can see
+// namespace object growth -- and the janitor's effect on it -- without waiting
+// for a garbage collection cycle to run.
+func (c *Controller) httpJanitorReport(w http.ResponseWriter, req *http.Request) {
+	reports, err := c.configMapJanitorReport()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// httpGCPreview reports everything the next garbage collection cycle would delete,
+// without deleting anything.
+func (c *Controller) httpGCPreview(w http.ResponseWriter, req *http.Request) {
+	candidates, err := c.planGarbageCollection()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.MarshalIndent(candidates, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
 }
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestConfigMapJanitorReport_NilClient(t *testing.T) {
+	c := &Controller{
+		configMapRetention: map[string]ConfigMapRetentionPolicy{
+			changelogConfigMapKind: {MaxCount: 200},
+		},
+	}
+	reports, err := c.configMapJanitorReport()
+	if err != nil {
+		t.Fatalf("configMapJanitorReport() error = %v, want nil", err)
+	}
+	if reports != nil {
+		t.Errorf("configMapJanitorReport() = %v, want nil when no configmap client is configured", reports)
+	}
+}
+
+func TestPlanConfigMapGarbageCollection_NilClient(t *testing.T) {
+	c := &Controller{
+		configMapRetention: map[string]ConfigMapRetentionPolicy{
+			changelogConfigMapKind: {MaxCount: 200},
+		},
+	}
+	candidates, err := c.planConfigMapGarbageCollection()
+	if err != nil {
+		t.Fatalf("planConfigMapGarbageCollection() error = %v, want nil", err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("planConfigMapGarbageCollection() = %v, want no candidates when no configmap client is configured", candidates)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+var imageSignatureClient = &http.Client{Timeout: 10 * time.Second}
+
+// maxSignaturesPerImage bounds how many signature-<n> lookups
+// untrustedComponents tries against a single component image before giving
+// up and reporting it unsigned, so a misbehaving sigstore can't hang release
+// creation indefinitely.
+const maxSignaturesPerImage = 8
+
+// simpleSigningManifest is the subset of an atomic container signature's
+// signed payload this check needs. See
+// https://github.com/containers/image/blob/main/docs/atomic-signature.md.
+type simpleSigningManifest struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// untrustedComponents returns the names of source component images that are
+// either unsigned or whose signature does not verify against
+// ImageSignatureGate.TrustedKeys, consulting release.Source's own resolved
+// image digests. It returns an error only if the trust roots themselves
+// could not be parsed; callers should treat a per-image lookup failure as
+// untrusted rather than an error, since a release shouldn't be waved through
+// because its sigstore is unreachable.
+func (c *Controller) untrustedComponents(release *Release) ([]string, error) {
+	gate := release.Config.ImageSignatureGate
+	if gate == nil || len(gate.SignatureBaseURL) == 0 {
+		return nil, nil
+	}
+
+	var keyring openpgp.EntityList
+	for _, armored := range gate.TrustedKeys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse imageSignatureGate trusted key: %v", err)
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("imageSignatureGate is configured with no usable trustedKeys")
+	}
+
+	var untrusted []string
+	for _, tag := range release.Source.Status.Tags {
+		if len(tag.Items) == 0 {
+			continue
+		}
+		// tag.Items[0].Image is the resolved "sha256:<hex>" manifest digest;
+		// DockerImageReference is a pull spec (registry/repo@sha256:<hex> or
+		// registry/repo:tag), not a bare digest, so it cannot be substituted
+		// here. If the digest hasn't been resolved yet there's no way to look
+		// up its signature, so report it untrusted rather than guessing.
+		digest := tag.Items[0].Image
+		if len(digest) == 0 || !verifyImageSignature(gate.SignatureBaseURL, digest, keyring) {
+			untrusted = append(untrusted, tag.Tag)
+		}
+	}
+	sort.Strings(untrusted)
+	return untrusted, nil
+}
+
+// verifyImageSignature reports whether digest (a "sha256:<hex>" reference)
+// has at least one simple-signing signature under baseURL that verifies
+// against keyring and whose signed manifest claims the same digest.
+func verifyImageSignature(baseURL, digest string, keyring openpgp.EntityList) bool {
+	parts := strings.SplitN(digest, ":", 2)
+	// a pull spec (registry/repo@algo:hex) is not a bare digest
+	if len(parts) != 2 || strings.ContainsAny(parts[0], "/@") {
+		return false
+	}
+	algo, hex := parts[0], parts[1]
+
+	for n := 1; n <= maxSignaturesPerImage; n++ {
+		url := fmt.Sprintf("%s/%s=%s/signature-%d", strings.TrimSuffix(baseURL, "/"), algo, hex, n)
+		resp, err := imageSignatureClient.Get(url)
+		if err != nil {
+			return false
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			// no more signatures to try; if n == 1 the image is unsigned
+			return false
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		md, err := openpgp.ReadMessage(bytes.NewReader(body), keyring, nil, nil)
+		if err != nil {
+			continue
+		}
+		plaintext, err := ioutil.ReadAll(md.UnverifiedBody)
+		if err != nil || md.SignatureError != nil || !md.IsSigned || md.SignedBy == nil {
+			continue
+		}
+		var manifest simpleSigningManifest
+		if err := json.Unmarshal(plaintext, &manifest); err != nil {
+			continue
+		}
+		if manifest.Critical.Image.DockerManifestDigest == digest {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+)
+
+// testSigningConfig forces SHA-256 throughout, since this build doesn't
+// compile in RIPEMD160 and the openpgp package otherwise defaults new
+// identities to preferring it.
+var testSigningConfig = &packet.Config{RSABits: 1024, DefaultHash: crypto.SHA256}
+
+// testSigningEntity returns a throwaway openpgp entity and its armored public
+// key, suitable for signing and verifying fixtures in this file's tests.
+func testSigningEntity(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", testSigningConfig)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to open armor writer: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("failed to serialize public key: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close armor writer: %v", err)
+	}
+	return entity, buf.String()
+}
+
+// signManifest signs a simple-signing manifest claiming digest and returns
+// the raw (non-armored) OpenPGP message, as served by a sigstore.
+func signManifest(t *testing.T, entity *openpgp.Entity, digest string) []byte {
+	t.Helper()
+	plaintext := fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":%q}}}`, digest)
+	var buf bytes.Buffer
+	w, err := openpgp.Sign(&buf, entity, nil, testSigningConfig)
+	if err != nil {
+		t.Fatalf("failed to open signer: %v", err)
+	}
+	if _, err := w.Write([]byte(plaintext)); err != nil {
+		t.Fatalf("failed to write signed payload: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close signer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyImageSignature(t *testing.T) {
+	entity, armoredKey := testSigningEntity(t)
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader([]byte(armoredKey)))
+	if err != nil {
+		t.Fatalf("failed to read back armored key: %v", err)
+	}
+	const digest = "sha256:" + "a1b2c3"
+	signature := signManifest(t, entity, digest)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sha256=a1b2c3/signature-1":
+			w.Write(signature)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if !verifyImageSignature(server.URL, digest, keyring) {
+		t.Errorf("verifyImageSignature() = false, want true for a correctly signed digest")
+	}
+	if verifyImageSignature(server.URL, "sha256:deadbeef", keyring) {
+		t.Errorf("verifyImageSignature() = true, want false for a digest with no signature")
+	}
+	// A pull spec is not a bare "algo:hex" digest; verifyImageSignature must
+	// fail closed rather than construct a bogus lookup URL from it.
+	if verifyImageSignature(server.URL, "registry.example.com/repo@"+digest, keyring) {
+		t.Errorf("verifyImageSignature() = true, want false for a pull spec rather than a bare digest")
+	}
+}
+
+func TestUntrustedComponents_UnresolvedDigestIsUntrusted(t *testing.T) {
+	_, armoredKey := testSigningEntity(t)
+	release := &Release{
+		Config: &ReleaseConfig{
+			ImageSignatureGate: &ImageSignatureGateConfig{
+				SignatureBaseURL: "http://unused.example.com",
+				TrustedKeys:      []string{armoredKey},
+			},
+		},
+		Source: &imagev1.ImageStream{
+			Status: imagev1.ImageStreamStatus{
+				Tags: []imagev1.NamedTagEventList{
+					{
+						Tag: "a-component",
+						Items: []imagev1.TagEvent{
+							{
+								// Image (the resolved digest) isn't set yet;
+								// DockerImageReference is a pull spec, not a
+								// digest, and must not be used as one.
+								DockerImageReference: "registry.example.com/repo@sha256:ffffffff",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	untrusted, err := (&Controller{}).untrustedComponents(release)
+	if err != nil {
+		t.Fatalf("untrustedComponents() error = %v", err)
+	}
+	if len(untrusted) != 1 || untrusted[0] != "a-component" {
+		t.Errorf("untrustedComponents() = %v, want [a-component]", untrusted)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// externalImportDefaultSchedule is used when a Stable stream's ExternalImport does
+// not set Schedule.
+const externalImportDefaultSchedule = 15 * time.Minute
+
+// externalImportLoop periodically imports new tags from the repository configured
+// on any Stable stream's ExternalImport into that stream, so that releases published
+// externally (e.g. to quay.io) are adopted without a human running `oc tag`. The
+// import itself only populates target.Spec.Tags; syncAdopted is what actually turns
+// an untagged import into a release once its phase/source annotations are missing.
+func (c *Controller) externalImportLoop(stopCh <-chan struct{}) {
+	lastImport := make(map[string]time.Time)
+	wait.Until(func() {
+		imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+		if err != nil {
+			glog.V(4).Infof("Unable to list image streams for external import: %v", err)
+			return
+		}
+		now := time.Now()
+		for _, stream := range imageStreams {
+			r, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok {
+				continue
+			}
+			if r.Config.As != releaseConfigModeStable || r.Config.ExternalImport == nil {
+				continue
+			}
+			cfg := r.Config.ExternalImport
+			if len(cfg.Repository) == 0 {
+				continue
+			}
+			schedule := cfg.Schedule.Duration()
+			if schedule <= 0 {
+				schedule = externalImportDefaultSchedule
+			}
+			key := stream.Namespace + "/" + stream.Name
+			if last, ok := lastImport[key]; ok && now.Sub(last) < schedule {
+				continue
+			}
+			lastImport[key] = now
+			if err := c.importExternalRepository(stream.Namespace, stream.Name, cfg.Repository); err != nil {
+				glog.Errorf("Unable to import external repository %s into %s: %v", cfg.Repository, key, err)
+			}
+		}
+	}, time.Minute, stopCh)
+}
+
+// importExternalRepository asks the API server to import any new tags from repository
+// into the named image stream. Tags that already exist are left untouched; new tags
+// land in target.Spec.Tags with no release.openshift.io annotations, which is exactly
+// the state syncAdopted expects for a Stable stream to pick them up.
+func (c *Controller) importExternalRepository(namespace, name, repository string) error {
+	isi := &imagev1.ImageStreamImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: imagev1.ImageStreamImportSpec{
+			Import: true,
+			Repository: &imagev1.RepositoryImportSpec{
+				From: corev1.ObjectReference{
+					Kind: "DockerImage",
+					Name: repository,
+				},
+			},
+		},
+	}
+	_, err := c.imageClient.ImageStreamImports(namespace).Create(isi)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -36,6 +36,12 @@ func (c *Controller) ensureReleaseMirror(release *Release, releaseTagName, input
 				releaseAnnotationImageHash:  inputImageHash,
 				releaseAnnotationGeneration: strconv.FormatInt(release.Target.Generation, 10),
 			},
+			Labels: map[string]string{
+				"release.openshift.io/mirror": "true",
+
+				"release.openshift.io/name": release.Config.Name,
+			},
+			OwnerReferences: mirrorOwnerReferences(release),
 		},
 	}
 
@@ -64,6 +70,30 @@ func (c *Controller) getMirror(release *Release, releaseTagName string) (*imagev
 	return c.imageStreamLister.ImageStreams(c.releaseNamespace).Get(mirrorName(release, releaseTagName))
 }
 
+// mirrorOwnerReferences returns an OwnerReference tying a mirror image stream back
+// to the release's target image stream, so the Kubernetes garbage collector cascades
+// deletion of mirrors if the target is ever removed directly. There is no standalone
+// API object for an individual release tag to own the mirror instead, since a tag is
+// just an entry in the target's Spec.Tags; the target image stream is the closest
+// real object available, so ownership is scoped to it rather than the tag. If the
+// target has no UID yet (for example in tests using a fake client), no owner
+// reference is set, since Kubernetes requires a UID to resolve one.
+func mirrorOwnerReferences(release *Release) []metav1.OwnerReference {
+	if len(release.Target.UID) == 0 {
+		return nil
+	}
+	controller := true
+	return []metav1.OwnerReference{
+		{
+			APIVersion: "image.openshift.io/v1",
+			Kind:       "ImageStream",
+			Name:       release.Target.Name,
+			UID:        release.Target.UID,
+			Controller: &controller,
+		},
+	}
+}
+
 func mirrorName(release *Release, releaseTagName string) string {
 	switch release.Config.As {
 	case releaseConfigModeStable:
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// imageContentSourcePolicy is a minimal representation of the
+// operator.openshift.io/v1alpha1 ImageContentSourcePolicy type, defined locally so
+// this package doesn't need to vendor the operator API just to emit a manifest.
+type imageContentSourcePolicy struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   metav1.ObjectMeta      `json:"metadata"`
+	Spec       imageContentSourceSpec `json:"spec"`
+}
+
+type imageContentSourceSpec struct {
+	RepositoryDigestMirrors []repositoryDigestMirror `json:"repositoryDigestMirrors"`
+}
+
+type repositoryDigestMirror struct {
+	Source  string   `json:"source"`
+	Mirrors []string `json:"mirrors"`
+}
+
+// ensureMirrorBundle writes a mapping file (source=local-mirror pullspec, one per
+// component image, in the format `oc adm release mirror` expects) and an
+// ImageContentSourcePolicy manifest for tagName into cfg.ArtifactDir, so
+// disconnected-install tooling can re-mirror the release without a human
+// reconstructing the source-to-local mapping by hand.
+func (c *Controller) ensureMirrorBundle(release *Release, tagName string, cfg *PublishMirrorBundle) error {
+	if len(cfg.ArtifactDir) == 0 {
+		return fmt.Errorf("mirrorBundle publish step requires artifactDir to be set")
+	}
+	mirror, err := c.getMirror(release, tagName)
+	if err != nil {
+		return err
+	}
+	localRepo := mirror.Status.PublicDockerImageRepository
+	if len(localRepo) == 0 {
+		return fmt.Errorf("mirror image stream %s has no public image repository yet", mirror.Name)
+	}
+
+	var mappings []string
+	mirrorsBySource := make(map[string]struct{})
+	for _, tag := range mirror.Spec.Tags {
+		if tag.From == nil {
+			continue
+		}
+		var source string
+		switch tag.From.Kind {
+		case "DockerImage":
+			source = tag.From.Name
+		case "ImageStreamImage":
+			// calculateMirrorImageStream uses <source stream>@<digest> for images
+			// in the source stream's public repository
+			parts := strings.SplitN(tag.From.Name, "@", 2)
+			if len(parts) != 2 || len(release.Source.Status.PublicDockerImageRepository) == 0 {
+				continue
+			}
+			source = release.Source.Status.PublicDockerImageRepository + "@" + parts[1]
+		default:
+			continue
+		}
+		mappings = append(mappings, fmt.Sprintf("%s=%s:%s", source, localRepo, tag.Name))
+		if repo := repositoryFromPullSpec(source); len(repo) > 0 {
+			mirrorsBySource[repo] = struct{}{}
+		}
+	}
+	sort.Strings(mappings)
+
+	sources := make([]string, 0, len(mirrorsBySource))
+	for source := range mirrorsBySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	icsp := imageContentSourcePolicy{
+		APIVersion: "operator.openshift.io/v1alpha1",
+		Kind:       "ImageContentSourcePolicy",
+		Metadata: metav1.ObjectMeta{
+			Name: fmt.Sprintf("release-%s", tagName),
+		},
+	}
+	for _, source := range sources {
+		icsp.Spec.RepositoryDigestMirrors = append(icsp.Spec.RepositoryDigestMirrors, repositoryDigestMirror{
+			Source:  source,
+			Mirrors: []string{repositoryFromPullSpec(localRepo)},
+		})
+	}
+	icspData, err := yaml.Marshal(icsp)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cfg.ArtifactDir, 0755); err != nil {
+		return err
+	}
+	mappingPath := filepath.Join(cfg.ArtifactDir, fmt.Sprintf("%s-mapping.txt", tagName))
+	if err := os.WriteFile(mappingPath, []byte(strings.Join(mappings, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	icspPath := filepath.Join(cfg.ArtifactDir, fmt.Sprintf("%s-icsp.yaml", tagName))
+	return os.WriteFile(icspPath, icspData, 0644)
+}
+
+// repositoryFromPullSpec strips the tag or digest suffix from a pull spec, leaving
+// just the repository (registry/namespace/name) portion.
+func repositoryFromPullSpec(pullSpec string) string {
+	if idx := strings.LastIndex(pullSpec, "@"); idx != -1 {
+		return pullSpec[:idx]
+	}
+	// only treat the last ":" as a tag separator if it comes after the last "/",
+	// otherwise it's part of a registry host:port
+	if idx := strings.LastIndex(pullSpec, "/"); idx != -1 {
+		if colon := strings.LastIndex(pullSpec[idx:], ":"); colon != -1 {
+			return pullSpec[:idx+colon]
+		}
+		return pullSpec
+	}
+	return pullSpec
+}
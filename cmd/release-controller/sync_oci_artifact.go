@@ -0,0 +1,164 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultOCIArtifactType = "application/vnd.openshift.release-controller.acceptance.v1+json"
+
+// ociDescriptor is a subset of the OCI content descriptor
+// (https://github.com/opencontainers/image-spec/blob/main/descriptor.md).
+type ociDescriptor struct {
+	MediaType    string            `json:"mediaType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is a subset of the OCI image manifest
+// (https://github.com/opencontainers/image-spec/blob/main/manifest.md),
+// including the subject field the Referrers API uses to attach this
+// manifest to the release payload it describes.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+	Subject       *ociDescriptor  `json:"subject,omitempty"`
+}
+
+// ociIndex is a subset of the OCI image layout's index.json
+// (https://github.com/opencontainers/image-spec/blob/main/image-index.md).
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+// releaseAcceptanceArtifact is the content of the acceptance-metadata layer
+// attached to the release payload by ensureOCIArtifact.
+type releaseAcceptanceArtifact struct {
+	Release      string                `json:"release"`
+	Tag          string                `json:"tag"`
+	Verification VerificationStatusMap `json:"verification,omitempty"`
+	Changelog    string                `json:"changelog"`
+}
+
+// ensureOCIArtifact writes an OCI Image Layout directory under
+// cfg.ArtifactDir containing an acceptance-metadata artifact for tagName,
+// attached to the release payload's digest via the subject field so
+// registry-native tooling can discover it via the Referrers API once the
+// layout is pushed to a registry (e.g. with oras or skopeo - this package
+// does not push to a registry itself, since no registry client is vendored
+// in this tree).
+func (c *Controller) ensureOCIArtifact(release *Release, tagName string, cfg *PublishOCIArtifact) error {
+	if len(cfg.ArtifactDir) == 0 {
+		return fmt.Errorf("ociArtifact publish step requires artifactDir to be set")
+	}
+	payloadDigest := findImageIDForTag(release.Target, tagName)
+	if len(payloadDigest) == 0 {
+		return fmt.Errorf("release tag %s has no image digest yet", tagName)
+	}
+
+	var verification VerificationStatusMap
+	if releaseTag := findTagReference(release.Target, tagName); releaseTag != nil {
+		if data := releaseTag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+			if err := json.Unmarshal([]byte(data), &verification); err != nil {
+				return fmt.Errorf("unable to parse verification status for %s: %v", tagName, err)
+			}
+		}
+	}
+
+	artifactType := cfg.ArtifactType
+	if len(artifactType) == 0 {
+		artifactType = defaultOCIArtifactType
+	}
+
+	artifact := releaseAcceptanceArtifact{
+		Release:      release.Config.Name,
+		Tag:          tagName,
+		Verification: verification,
+		Changelog:    fmt.Sprintf("/changelog?to=%s", tagName),
+	}
+	layerData, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return err
+	}
+	// An empty config is conventional for artifact manifests that carry no
+	// runnable image, per the OCI image-spec guidance for artifacts.
+	configData := []byte("{}")
+
+	layoutDir := filepath.Join(cfg.ArtifactDir, tagName)
+	blobsDir := filepath.Join(layoutDir, "blobs", "sha256")
+	if err := os.MkdirAll(blobsDir, 0755); err != nil {
+		return err
+	}
+
+	configDescriptor, err := writeOCIBlob(blobsDir, "application/vnd.oci.empty.v1+json", configData)
+	if err != nil {
+		return err
+	}
+	layerDescriptor, err := writeOCIBlob(blobsDir, artifactType, layerData)
+	if err != nil {
+		return err
+	}
+	layerDescriptor.Annotations = map[string]string{"org.opencontainers.image.title": tagName + "-acceptance.json"}
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     "application/vnd.oci.image.manifest.v1+json",
+		ArtifactType:  artifactType,
+		Config:        configDescriptor,
+		Layers:        []ociDescriptor{layerDescriptor},
+		Subject: &ociDescriptor{
+			MediaType: "application/vnd.oci.image.manifest.v1+json",
+			Digest:    payloadDigest,
+			// Size is unknown here: computing it would require pulling the
+			// release payload's manifest from the registry, and this tree has
+			// no vendored registry client. Tooling that pushes this layout
+			// must resolve the real size before uploading.
+			Size: 0,
+		},
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifestDescriptor, err := writeOCIBlob(blobsDir, manifest.MediaType, manifestData)
+	if err != nil {
+		return err
+	}
+
+	index := ociIndex{
+		SchemaVersion: 2,
+		MediaType:     "application/vnd.oci.image.index.v1+json",
+		Manifests:     []ociDescriptor{manifestDescriptor},
+	}
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(layoutDir, "index.json"), indexData, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(layoutDir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644)
+}
+
+// writeOCIBlob writes data to blobsDir keyed by its sha256 digest and returns
+// the descriptor for it.
+func writeOCIBlob(blobsDir, mediaType string, data []byte) (ociDescriptor, error) {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	path := filepath.Join(blobsDir, hex.EncodeToString(sum[:]))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return ociDescriptor{}, err
+	}
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(data))}, nil
+}
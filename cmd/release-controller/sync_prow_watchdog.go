@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+const (
+	// prowJobWatchdogInterval is how often prowJobWatchdogLoop reconciles
+	// Pending verification steps against the prow namespace.
+	prowJobWatchdogInterval = 5 * time.Minute
+	// prowJobWatchdogGracePeriod is how long a Pending step's ProwJob may be
+	// absent from the informer cache before it is presumed dropped, rather
+	// than just not yet visible because it was created moments ago.
+	prowJobWatchdogGracePeriod = 10 * time.Minute
+)
+
+// prowJobWatchdogTracker remembers, per verification step, the first time its
+// ProwJob was observed missing, so reconcileDroppedProwJobs can apply
+// prowJobWatchdogGracePeriod instead of acting on a single missed
+// observation. Like the other in-memory advisory trackers in this package,
+// losing this on a controller restart just means the grace period restarts.
+type prowJobWatchdogTracker struct {
+	lock     sync.Mutex
+	observed map[string]time.Time
+}
+
+func newProwJobWatchdogTracker() *prowJobWatchdogTracker {
+	return &prowJobWatchdogTracker{observed: make(map[string]time.Time)}
+}
+
+// missingSince records key as missing as of now if it wasn't already, and
+// returns how long it has been continuously observed missing.
+func (t *prowJobWatchdogTracker) missingSince(key string, now time.Time) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	first, ok := t.observed[key]
+	if !ok {
+		t.observed[key] = now
+		return 0
+	}
+	return now.Sub(first)
+}
+
+// clear forgets key, because its ProwJob was found again or it was just
+// remediated.
+func (t *prowJobWatchdogTracker) clear(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.observed, key)
+}
+
+func (t *prowJobWatchdogTracker) snapshot() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	keys := make([]string, 0, len(t.observed))
+	for key := range t.observed {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func prowJobWatchdogKey(release, tag, verification string) string {
+	return fmt.Sprintf("%s/%s/%s", release, tag, verification)
+}
+
+// prowJobWatchdogLoop periodically reconciles every release stream's Ready
+// tags against the prow namespace, detecting verification steps whose
+// ProwJob the controller believes is still Pending but has actually been
+// deleted (by prow's own GC, or by hand) without ever reaching a terminal
+// state.
+func (c *Controller) prowJobWatchdogLoop(stopCh <-chan struct{}) {
+	wait.Until(c.reconcileDroppedProwJobs, prowJobWatchdogInterval, stopCh)
+}
+
+func (c *Controller) reconcileDroppedProwJobs() {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		glog.V(4).Infof("Unable to list image streams for prow job watchdog: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, stream := range imageStreams {
+		release, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		for _, tag := range findTagReferencesByPhase(release, releasePhaseReady) {
+			if err := c.reconcileDroppedProwJobsForTag(release, tag, now); err != nil {
+				glog.Errorf("Unable to reconcile dropped prow jobs for %s %s: %v", release.Config.Name, tag.Name, err)
+			}
+		}
+	}
+}
+
+// reconcileDroppedProwJobsForTag checks every Pending, single-ProwJob
+// verification step on tag against the prow namespace. This is scoped to the
+// single-ProwJob path: AggregatedProwJob and UpgradeFromSources steps manage
+// a set of their own per-copy jobs each sync rather than one job tracked by
+// name, so a missing copy among them is already detected and recreated the
+// next time ensureVerificationJobs runs for the tag.
+func (c *Controller) reconcileDroppedProwJobsForTag(release *Release, tag *imagev1.TagReference, now time.Time) error {
+	status, err := decodeVerificationStatus(tag)
+	if err != nil || len(status) == 0 {
+		return nil
+	}
+	verify, _ := effectiveVerifyForTag(release, tag)
+
+	var dropped []string
+	for name, s := range status {
+		if s.State != releaseVerificationStatePending {
+			continue
+		}
+		verifyType, ok := verify[name]
+		if !ok || verifyType.ProwJob == nil || verifyType.AggregatedProwJob != nil || len(verifyType.UpgradeFromSources) > 0 {
+			continue
+		}
+
+		key := prowJobWatchdogKey(release.Config.Name, tag.Name, name)
+		prowJobName := fmt.Sprintf("%s-%s", tag.Name, name)
+		if _, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName)); err == nil && exists {
+			c.prowJobWatchdog.clear(key)
+			continue
+		}
+
+		if c.prowJobWatchdog.missingSince(key, now) < prowJobWatchdogGracePeriod {
+			continue
+		}
+		c.prowJobWatchdog.clear(key)
+		dropped = append(dropped, name)
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	for _, name := range dropped {
+		status[name] = &VerificationStatus{State: releaseVerificationStateUnknown}
+		c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "ProwJobDropped",
+			"Verification step %s for %s vanished from the prow namespace without a result; it will be recreated", name, tag.Name)
+	}
+	return c.setReleaseAnnotation(release, releasePhaseReady, map[string]string{
+		releaseAnnotationVerify: limitAnnotationSize(releaseAnnotationVerify, toJSONString(status)),
+	}, tag.Name)
+}
+
+// httpProwJobWatchdog serves the set of verification steps prowJobWatchdog is
+// currently timing out the grace period on before declaring their ProwJob
+// dropped.
+func (c *Controller) httpProwJobWatchdog(w http.ResponseWriter, req *http.Request) {
+	data, err := json.MarshalIndent(c.prowJobWatchdog.snapshot(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -6,13 +6,16 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/blang/semver"
 	"github.com/golang/glog"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
 
 	imagev1 "github.com/openshift/api/image/v1"
+	imageclient "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
 )
 
 func (c *Controller) ensureTagPointsToRelease(release *Release, to, from string) error {
@@ -42,6 +45,22 @@ func (c *Controller) ensureTagPointsToRelease(release *Release, to, from string)
 	toTag.From = &corev1.ObjectReference{Kind: "ImageStreamTag", Name: from}
 	toTag.ImportPolicy = imagev1.TagImportPolicy{}
 
+	if version, err := semver.Parse(from); err == nil {
+		reason, err := c.minorVersionFreezeReason(version.Major, version.Minor)
+		if err != nil {
+			return err
+		}
+		if len(reason) > 0 {
+			glog.V(4).Infof("Not advancing image stream tag %s/%s:%s to %s: %d.%d is frozen: %s", release.Target.Namespace, release.Target.Name, to, from, version.Major, version.Minor, reason)
+			return nil
+		}
+	}
+
+	if c.dryRun {
+		glog.Infof("[dry-run] would update image stream tag %s/%s:%s to point to %s", release.Target.Namespace, release.Target.Name, to, from)
+		return nil
+	}
+
 	is, err := c.imageClient.ImageStreams(target.Namespace).Update(target)
 	if errors.IsNotFound(err) {
 		return nil
@@ -54,7 +73,38 @@ func (c *Controller) ensureTagPointsToRelease(release *Release, to, from string)
 	return nil
 }
 
-func (c *Controller) ensureImageStreamMatchesRelease(release *Release, toNamespace, toName, from string, tags, excludeTags []string) error {
+// publishImageClient returns the client that should be used to update toNamespace
+// on behalf of ref. When ref.ServiceAccount is set and the controller was given a
+// cluster config (see SetClusterConfig), it returns a client impersonating
+// system:serviceaccount:<toNamespace>:<ref.ServiceAccount> so the target namespace
+// only needs to grant RBAC to that service account instead of the controller's own
+// identity. Otherwise it returns the controller's own client.
+func (c *Controller) publishImageClient(toNamespace string, ref *PublishStreamReference) (imageclient.ImageV1Interface, error) {
+	if len(ref.ServiceAccount) == 0 {
+		return c.imageClient, nil
+	}
+	if c.clusterConfig == nil {
+		return nil, fmt.Errorf("publish target %s/%s requests serviceAccount %q but the controller has no cluster config to impersonate with", toNamespace, ref.Name, ref.ServiceAccount)
+	}
+	impersonated := rest.CopyConfig(c.clusterConfig)
+	impersonated.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", toNamespace, ref.ServiceAccount),
+	}
+	client, err := imageclient.NewForConfig(impersonated)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build client impersonating system:serviceaccount:%s:%s: %v", toNamespace, ref.ServiceAccount, err)
+	}
+	return client, nil
+}
+
+func (c *Controller) ensureImageStreamMatchesRelease(release *Release, ref *PublishStreamReference, from string) error {
+	toNamespace := ref.Namespace
+	if len(toNamespace) == 0 {
+		toNamespace = release.Target.Namespace
+	}
+	toName := ref.Name
+	tags, excludeTags := ref.Tags, ref.ExcludeTags
+
 	if len(tags) == 0 {
 		glog.V(4).Infof("Ensure image stream %s/%s has contents of %s", toNamespace, toName, from)
 	} else {
@@ -63,6 +113,15 @@ func (c *Controller) ensureImageStreamMatchesRelease(release *Release, toNamespa
 	if toNamespace == release.Source.Namespace && toName == release.Source.Name {
 		return nil
 	}
+	// Every replica of the controller watches the same release, so without a
+	// leader check they would all race to write the same cross-namespace tag.
+	// This applies regardless of whether the publish step impersonates a
+	// service account -- impersonation only changes which identity performs
+	// the write, not how many replicas attempt it.
+	if !c.IsLeader() {
+		glog.V(4).Infof("Not the leader, deferring cross-namespace publish of %s to %s/%s", from, toNamespace, toName)
+		return nil
+	}
 	fromTag := findTagReference(release.Target, from)
 	if fromTag == nil {
 		// tag was deleted
@@ -75,6 +134,11 @@ func (c *Controller) ensureImageStreamMatchesRelease(release *Release, toNamespa
 		return nil
 	}
 
+	imgClient, err := c.publishImageClient(toNamespace, ref)
+	if err != nil {
+		return err
+	}
+
 	target, err := c.imageStreamLister.ImageStreams(toNamespace).Get(toName)
 	if errors.IsNotFound(err) {
 		// TODO: create it?
@@ -155,10 +219,18 @@ func (c *Controller) ensureImageStreamMatchesRelease(release *Release, toNamespa
 		target = copied
 	}
 
-	_, err = c.imageClient.ImageStreams(target.Namespace).Update(target)
+	if c.dryRun {
+		glog.Infof("[dry-run] would publish %s to image stream %s/%s", from, toNamespace, toName)
+		return nil
+	}
+
+	_, err = imgClient.ImageStreams(target.Namespace).Update(target)
 	if errors.IsNotFound(err) {
 		return nil
 	}
+	if errors.IsForbidden(err) {
+		return fmt.Errorf("publish target %s/%s is missing RBAC to update image streams: %v", toNamespace, toName, err)
+	}
 	if err != nil {
 		return err
 	}
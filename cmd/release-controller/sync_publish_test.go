@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnsureImageStreamMatchesRelease_NotLeader(t *testing.T) {
+	release := &Release{
+		Source: &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{Namespace: "source-ns", Name: "source"}},
+		Target: &imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "source-ns", Name: "source"},
+			Spec:       imagev1.ImageStreamSpec{Tags: []imagev1.TagReference{{Name: "4.1.0"}}},
+		},
+	}
+	c := &Controller{leaderElection: &controllerLeaderElection{}}
+	atomic.StoreInt32(&c.leaderElection.isLeader, 0)
+
+	tests := []struct {
+		name string
+		ref  *PublishStreamReference
+	}{
+		{
+			name: "cross-namespace without impersonation",
+			ref:  &PublishStreamReference{Name: "target", Namespace: "other-ns"},
+		},
+		{
+			name: "cross-namespace with impersonation",
+			ref:  &PublishStreamReference{Name: "target", Namespace: "other-ns", ServiceAccount: "publisher"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A non-leader must defer regardless of whether impersonation is
+			// configured; if it doesn't, this panics on the nil
+			// imageStreamLister/imageClient below, which is exactly the race
+			// the leader check exists to prevent.
+			if err := c.ensureImageStreamMatchesRelease(release, tt.ref, "4.1.0"); err != nil {
+				t.Fatalf("ensureImageStreamMatchesRelease() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestEnsureImageStreamMatchesRelease_SameStreamSkipsLeaderCheck(t *testing.T) {
+	release := &Release{
+		Source: &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{Namespace: "source-ns", Name: "source"}},
+		Target: &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{Namespace: "source-ns", Name: "source"}},
+	}
+	c := &Controller{leaderElection: &controllerLeaderElection{}}
+	atomic.StoreInt32(&c.leaderElection.isLeader, 0)
+
+	ref := &PublishStreamReference{Name: "source", Namespace: "source-ns"}
+	if err := c.ensureImageStreamMatchesRelease(release, ref, "4.1.0"); err != nil {
+		t.Fatalf("ensureImageStreamMatchesRelease() = %v, want nil", err)
+	}
+}
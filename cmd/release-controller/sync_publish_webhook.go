@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// publishWebhookDefaultMaxRetries is applied to a PublishWebhook that does not
+// set MaxRetries itself.
+const publishWebhookDefaultMaxRetries = 3
+
+var publishWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// PublishWebhookPayload is the JSON body POSTed to a PublishWebhook's URL when
+// a release becomes Accepted.
+type PublishWebhookPayload struct {
+	Release       string                `json:"release"`
+	Tag           string                `json:"tag"`
+	PullSpec      string                `json:"pullSpec"`
+	Phase         string                `json:"phase"`
+	Verifications VerificationStatusMap `json:"verifications,omitempty"`
+}
+
+// ensureWebhookPublish POSTs a PublishWebhookPayload describing releaseTag to
+// hook.URL, retrying on a non-2xx response or a transport error up to
+// hook.MaxRetries additional times with a short linear backoff between
+// attempts. Unlike emitCloudEvent, this runs synchronously on the caller's
+// goroutine and its error is recorded in c.publishHistory like any other
+// publish step, since a downstream pipeline trigger is expected to actually
+// happen, not merely be attempted.
+func (c *Controller) ensureWebhookPublish(release *Release, releaseTag *imagev1.TagReference, hook *PublishWebhook) error {
+	status, err := decodeVerificationStatus(releaseTag)
+	if err != nil {
+		return fmt.Errorf("unable to decode verification status: %v", err)
+	}
+	body, err := json.Marshal(PublishWebhookPayload{
+		Release:       release.Config.Name,
+		Tag:           releaseTag.Name,
+		PullSpec:      findPublicImagePullSpec(release.Target, releaseTag.Name),
+		Phase:         releaseTag.Annotations[releaseAnnotationPhase],
+		Verifications: status,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode webhook payload: %v", err)
+	}
+
+	var signature string
+	if len(hook.SecretName) > 0 {
+		secret, err := c.webhookPublishSecret(hook.SecretName)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	maxRetries := hook.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = publishWebhookDefaultMaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if lastErr = c.postWebhookPublish(hook.URL, body, signature); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook delivery to %s failed after %d attempts: %v", hook.URL, maxRetries+1, lastErr)
+}
+
+// postWebhookPublish makes a single POST attempt of body to url, signed with
+// signature if non-empty.
+func (c *Controller) postWebhookPublish(url string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to build webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(signature) > 0 {
+		req.Header.Set("X-Release-Controller-Signature", signature)
+	}
+	resp, err := publishWebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookPublishSecret reads the "hmacSecret" key of the Secret named
+// secretName in the controller's job namespace.
+func (c *Controller) webhookPublishSecret(secretName string) ([]byte, error) {
+	if c.secretClient == nil {
+		return nil, fmt.Errorf("webhook publish step references secret %s but no secret client is configured", secretName)
+	}
+	secret, err := c.secretClient.Secrets(c.jobNamespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get webhook secret %s: %v", secretName, err)
+	}
+	key, ok := secret.Data["hmacSecret"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no hmacSecret key", secretName)
+	}
+	return key, nil
+}
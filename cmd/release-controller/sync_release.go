@@ -17,6 +17,62 @@ import (
 	imagev1 "github.com/openshift/api/image/v1"
 )
 
+// releasePayloadEngineImport is the ReleaseConfig.PayloadEngine value selecting
+// importPayloadEngine. The default engine, oc adm release new run in a Job, has
+// no name of its own and is selected by leaving PayloadEngine empty.
+const releasePayloadEngineImport = "import"
+
+// payloadEngine builds or imports name's release payload for release from mirror,
+// which ensureReleaseMirror has already populated with the tag's mirrored input
+// images. complete is false while the work is still in progress, in which case
+// the caller should simply return and wait for the next sync; once complete,
+// success reports whether the payload is usable. job, if non-nil, is the Job
+// that did the work, so a failure can retrieve its termination log.
+//
+// This is the extension point for ReleaseConfig.PayloadEngine: everything
+// downstream of a payload's completion -- phase management, verification, and
+// publishing -- operates only on the release tag, never on how its payload was
+// produced, so a stream can opt into a different engine without touching any of
+// that. See payloadEngineFor.
+type payloadEngine interface {
+	ensurePayload(c *Controller, release *Release, name string, mirror *imagev1.ImageStream) (complete, success bool, job *batchv1.Job, err error)
+}
+
+// payloadEngineFor returns the payloadEngine that config.PayloadEngine selects.
+func payloadEngineFor(config *ReleaseConfig) payloadEngine {
+	switch config.PayloadEngine {
+	case releasePayloadEngineImport:
+		return importPayloadEngine{}
+	default:
+		return ocPayloadEngine{}
+	}
+}
+
+// ocPayloadEngine is the default payloadEngine: it assembles the release payload
+// by running `oc adm release new` in a Job, exactly as release-controller always
+// has.
+type ocPayloadEngine struct{}
+
+func (ocPayloadEngine) ensurePayload(c *Controller, release *Release, name string, mirror *imagev1.ImageStream) (complete, success bool, job *batchv1.Job, err error) {
+	job, err = c.ensureReleaseJob(release, name, mirror)
+	if err != nil || job == nil {
+		return false, false, nil, err
+	}
+	success, complete = jobIsComplete(job)
+	return complete, success, job, nil
+}
+
+// importPayloadEngine is the "import" payloadEngine: it treats mirror itself as
+// the finished release payload, for streams whose payload is actually built by
+// an external pipeline that pushes the result into the source image stream
+// rather than having this controller assemble it with `oc adm release new`. It
+// never creates a Job.
+type importPayloadEngine struct{}
+
+func (importPayloadEngine) ensurePayload(c *Controller, release *Release, name string, mirror *imagev1.ImageStream) (complete, success bool, job *batchv1.Job, err error) {
+	return true, true, nil, nil
+}
+
 func (c *Controller) ensureReleaseJob(release *Release, name string, mirror *imagev1.ImageStream) (*batchv1.Job, error) {
 	return c.ensureJob(name, nil, func() (*batchv1.Job, error) {
 		toImage := fmt.Sprintf("%s:%s", release.Target.Status.PublicDockerImageRepository, name)
@@ -25,7 +81,7 @@ func (c *Controller) ensureReleaseJob(release *Release, name string, mirror *ima
 			cliImage = release.Config.OverrideCLIImage
 		}
 
-		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName)
+		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName, release.Config.PayloadJob)
 
 		job.Spec.Template.Spec.Containers[0].Command = []string{
 			"/bin/bash", "-c",
@@ -59,7 +115,7 @@ func (c *Controller) ensureRewriteJob(release *Release, name string, mirror *ima
 			cliImage = release.Config.OverrideCLIImage
 		}
 
-		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName)
+		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName, release.Config.PayloadJob)
 
 		container := job.Spec.Template.Spec.Containers[0]
 
@@ -128,7 +184,7 @@ func (c *Controller) ensureImportJob(release *Release, name string, mirror *imag
 			cliImage = release.Config.OverrideCLIImage
 		}
 
-		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName)
+		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName, release.Config.PayloadJob)
 
 		container := job.Spec.Template.Spec.Containers[0]
 
@@ -272,7 +328,12 @@ func findContainerStatus(statuses []corev1.ContainerStatus, name string) *corev1
 	return nil
 }
 
-func newReleaseJobBase(name, cliImage, pullSecretName string) (*batchv1.Job, string) {
+// payloadJobEgressProfileLabel is stamped onto payload creation Job pods when
+// PayloadJobConfig.EgressPolicyProfile is set, for a cluster-admin-managed
+// NetworkPolicy to select on. See PayloadJobConfig.
+const payloadJobEgressProfileLabel = "release.openshift.io/egress-profile"
+
+func newReleaseJobBase(name, cliImage, pullSecretName string, jobConfig *PayloadJobConfig) (*batchv1.Job, string) {
 	var prefix string
 	if len(pullSecretName) > 0 {
 		prefix = `
@@ -334,6 +395,14 @@ func newReleaseJobBase(name, cliImage, pullSecretName string) (*batchv1.Job, str
 			},
 		}
 	}
+	if jobConfig != nil {
+		job.Spec.Template.Spec.Containers[0].Resources = jobConfig.Resources
+		job.Spec.Template.Spec.NodeSelector = jobConfig.NodeSelector
+		job.Spec.Template.Spec.Tolerations = jobConfig.Tolerations
+		if len(jobConfig.EgressPolicyProfile) > 0 {
+			job.Spec.Template.Labels = map[string]string{payloadJobEgressProfileLabel: jobConfig.EgressPolicyProfile}
+		}
+	}
 	return job, prefix
 }
 
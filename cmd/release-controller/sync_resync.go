@@ -0,0 +1,103 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// streamClass groups release streams by how often they need to be resynced. Stable
+// streams change rarely (a human tags a new release), Integration streams change
+// continuously (every successful input build), and Archived streams essentially
+// never change once marked.
+type streamClass string
+
+const (
+	streamClassIntegration streamClass = "Integration"
+	streamClassStable      streamClass = "Stable"
+	streamClassArchived    streamClass = "Archived"
+)
+
+// streamClassFor classifies a release config for the purposes of resync scheduling.
+func streamClassFor(cfg *ReleaseConfig) streamClass {
+	switch {
+	case cfg.Archived:
+		return streamClassArchived
+	case cfg.As == releaseConfigModeStable:
+		return streamClassStable
+	default:
+		return streamClassIntegration
+	}
+}
+
+// resyncIntervals holds the per-class interval used by resyncClassesLoop. Get
+// returns 10 minutes for a class with no interval set; the zero value has no
+// intervals at all, so Get returns 10 minutes for every class and Set panics.
+type resyncIntervals struct {
+	lock      sync.RWMutex
+	intervals map[streamClass]time.Duration
+}
+
+// defaultResyncIntervals favors the existing global 10m behavior for Integration
+// streams while giving Stable and Archived streams much longer periods, since tag
+// changes on those streams are already picked up immediately by the image stream
+// event handlers; periodic resync here only exists as a safety net against missed
+// events.
+func defaultResyncIntervals() *resyncIntervals {
+	return &resyncIntervals{
+		intervals: map[streamClass]time.Duration{
+			streamClassIntegration: 10 * time.Minute,
+			streamClassStable:      30 * time.Minute,
+			streamClassArchived:    6 * time.Hour,
+		},
+	}
+}
+
+func (r *resyncIntervals) Set(class streamClass, interval time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals[class] = interval
+}
+
+func (r *resyncIntervals) Get(class streamClass) time.Duration {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if d, ok := r.intervals[class]; ok {
+		return d
+	}
+	return 10 * time.Minute
+}
+
+// resyncClassesLoop is a safety-net requeue that nudges each release input image
+// stream back onto the work queue at a cadence appropriate to its class, as a
+// fallback for the (event-driven) informer handlers which normally requeue on any
+// visible change. It checks at a fine enough grain (1 minute) to honor short
+// intervals without needing a dedicated timer per stream.
+func (c *Controller) resyncClassesLoop(stopCh <-chan struct{}) {
+	lastResync := make(map[string]time.Time)
+	wait.Until(func() {
+		imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+		if err != nil {
+			glog.V(4).Infof("Unable to list image streams for class resync: %v", err)
+			return
+		}
+		now := time.Now()
+		for _, stream := range imageStreams {
+			r, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok {
+				continue
+			}
+			class := streamClassFor(r.Config)
+			interval := c.resyncIntervals.Get(class)
+			key := stream.Namespace + "/" + stream.Name
+			if last, ok := lastResync[key]; ok && now.Sub(last) < interval {
+				continue
+			}
+			lastResync[key] = now
+			c.addQueueKey(queueKey{namespace: stream.Namespace, name: stream.Name})
+		}
+	}, time.Minute, stopCh)
+}
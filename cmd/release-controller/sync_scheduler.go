@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// delayedActionConfigMapName is the well-known ConfigMap, in the controller's
+// job namespace, used to persist pending delayed actions (backoffs, soak
+// deadlines, expiries) so they survive a controller restart instead of being
+// recomputed from scratch or silently dropped. Persistence is optional: if no
+// ConfigMap client has been configured via SetConfigMapClient, scheduling
+// becomes a no-op and callers fall back to their prior behavior.
+const delayedActionConfigMapName = "release-controller-schedule"
+
+// scheduledAction is the persisted record of a single delayed action: run
+// something identified by Payload no earlier than RunAt.
+type scheduledAction struct {
+	RunAt   time.Time `json:"runAt"`
+	Payload string    `json:"payload"`
+}
+
+// scheduleDelayedAction persists a one-shot action under id, replacing any
+// existing action with the same id, so its RunAt deadline survives a
+// controller restart. If no ConfigMap client is configured this is a no-op.
+func (c *Controller) scheduleDelayedAction(id string, runAt time.Time, payload string) error {
+	if c.configMapClient == nil {
+		return nil
+	}
+	data, err := json.Marshal(scheduledAction{RunAt: runAt, Payload: payload})
+	if err != nil {
+		return err
+	}
+	return c.updateDelayedActionConfigMap(func(cm *corev1.ConfigMap) {
+		cm.Data[id] = string(data)
+	})
+}
+
+// cancelDelayedAction removes a previously scheduled action, e.g. because it
+// fired through its normal in-memory path and no longer needs to be
+// reconstructed on restart. If no ConfigMap client is configured this is a
+// no-op.
+func (c *Controller) cancelDelayedAction(id string) error {
+	if c.configMapClient == nil {
+		return nil
+	}
+	return c.updateDelayedActionConfigMap(func(cm *corev1.ConfigMap) {
+		delete(cm.Data, id)
+	})
+}
+
+// dueDelayedActions returns the payloads of every persisted action whose
+// RunAt has passed, removing them from the ConfigMap so they are not
+// returned again. If no ConfigMap client is configured it returns nil.
+func (c *Controller) dueDelayedActions(now time.Time) (map[string]string, error) {
+	if c.configMapClient == nil {
+		return nil, nil
+	}
+	cm, err := c.configMapClient.ConfigMaps(c.jobNamespace).Get(delayedActionConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	due := make(map[string]string)
+	for id, raw := range cm.Data {
+		var action scheduledAction
+		if err := json.Unmarshal([]byte(raw), &action); err != nil {
+			continue
+		}
+		if !now.Before(action.RunAt) {
+			due[id] = action.Payload
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	err = c.updateDelayedActionConfigMap(func(cm *corev1.ConfigMap) {
+		for id := range due {
+			delete(cm.Data, id)
+		}
+	})
+	return due, err
+}
+
+// updateDelayedActionConfigMap fetches or creates the delayed-action
+// ConfigMap, applies mutate, and writes it back.
+func (c *Controller) updateDelayedActionConfigMap(mutate func(cm *corev1.ConfigMap)) error {
+	client := c.configMapClient.ConfigMaps(c.jobNamespace)
+	cm, err := client.Get(delayedActionConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: delayedActionConfigMapName, Namespace: c.jobNamespace},
+			Data:       make(map[string]string),
+		}
+		mutate(cm)
+		_, err := client.Create(cm)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	mutate(cm)
+	_, err = client.Update(cm)
+	return err
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// evaluateAcceptance applies the same policy syncReady uses to decide what should
+// happen to a Ready tag once its verification jobs have been considered, without
+// mutating anything. It is shared by syncReady and the acceptance simulation API so
+// the two can never drift apart.
+func evaluateAcceptance(verify map[string]ReleaseVerification, status VerificationStatusMap) (phase, reason, rejectReason, message string) {
+	if names, ok := status.Incomplete(verify); ok {
+		return releasePhaseReady, "", "", fmt.Sprintf("still waiting on: %s", strings.Join(names, ", "))
+	}
+	if names, ok := status.Failures(); ok && !allOptional(verify, names...) {
+		return releasePhaseRejected, "VerificationFailed", classifyRejectReason(verify, status, names), fmt.Sprintf("release verification step failed: %s", strings.Join(names, ", "))
+	}
+	return releasePhaseAccepted, "", "", ""
+}
+
+// classifyRejectReason buckets a verification-driven rejection into one of
+// the RejectReason* categories from the same verify/status data
+// evaluateAcceptance used to decide on Rejected, so releaseAnnotationRejectReason
+// can be computed without any extra queries. A failed step configured as an
+// upgrade check takes priority, since an otherwise-passing release that only
+// fails its upgrade test is a meaningfully different signal than a failed
+// blocking job.
+func classifyRejectReason(verify map[string]ReleaseVerification, status VerificationStatusMap, names []string) string {
+	timedOut := false
+	for _, name := range names {
+		if verify[name].Upgrade {
+			return RejectReasonUpgradeRegression
+		}
+		if s := status[name]; s != nil && s.TimedOut {
+			timedOut = true
+		}
+	}
+	if timedOut {
+		return RejectReasonVerificationTimeout
+	}
+	return RejectReasonBlockingJobFailed
+}
+
+// SimulationRequest describes a hypothetical change to a tag's verification
+// results or policy, to be evaluated without altering the tag.
+type SimulationRequest struct {
+	// StatusOverrides replaces the recorded state of the named verification step
+	// (e.g. "Succeeded", "Failed", "Pending") before evaluating acceptance.
+	StatusOverrides map[string]string `json:"statusOverrides,omitempty"`
+	// OptionalOverrides replaces the configured Optional flag of the named
+	// verification step.
+	OptionalOverrides map[string]bool `json:"optionalOverrides,omitempty"`
+}
+
+// SimulationResult is the outcome of evaluating a SimulationRequest against a tag.
+type SimulationResult struct {
+	Tag    string `json:"tag"`
+	Phase  string `json:"phase"`
+	Reason string `json:"reason,omitempty"`
+	// RejectReason is one of the RejectReason* constants, set only when Phase
+	// is Rejected.
+	RejectReason string `json:"rejectReason,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// httpSimulateAcceptance lets a release manager see what phase a tag would end up
+// in under a hypothetical verification outcome or policy change (e.g. "what if job
+// X were optional"), without having to actually change the config and wait for a
+// real run.
+func (c *Controller) httpSimulateAcceptance(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	streamName, tagName := vars["release"], vars["tag"]
+
+	var simReq SimulationRequest
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&simReq); err != nil && err.Error() != "EOF" {
+			http.Error(w, fmt.Sprintf("invalid simulation request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	source, err := c.releaseStreamSource(streamName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	release := source.release
+
+	tag := findTagReference(release.Target, tagName)
+	if tag == nil {
+		http.Error(w, fmt.Sprintf("no tag %q in release stream %q", tagName, streamName), http.StatusNotFound)
+		return
+	}
+
+	status := make(VerificationStatusMap)
+	if data := tag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			http.Error(w, fmt.Sprintf("tag has invalid verification status: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	for name, state := range simReq.StatusOverrides {
+		status[name] = &VerificationStatus{State: state}
+	}
+
+	verify := make(map[string]ReleaseVerification, len(release.Config.Verify))
+	for name, v := range release.Config.Verify {
+		verify[name] = v
+	}
+	for name, optional := range simReq.OptionalOverrides {
+		v := verify[name]
+		v.Optional = optional
+		verify[name] = v
+	}
+
+	phase, reason, rejectReason, message := evaluateAcceptance(verify, status)
+
+	data, err := json.MarshalIndent(SimulationResult{Tag: tagName, Phase: phase, Reason: reason, RejectReason: rejectReason, Message: message}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+const (
+	// stuckReleaseThreshold is how long a tag may remain Pending with no progress
+	// before stuckReleaseLoop classifies and acts on it.
+	stuckReleaseThreshold = 30 * time.Minute
+	// stuckReleaseMaxRetries bounds how many times a Pending tag whose pod is
+	// unschedulable will have its create job deleted and retried before it is
+	// given up on and marked Failed.
+	stuckReleaseMaxRetries = 3
+)
+
+// StuckReleaseReason classifies why a Pending tag has not made progress.
+type StuckReleaseReason string
+
+const (
+	// StuckReasonJobMissing means no create job exists for the tag despite it
+	// having been Pending longer than stuckReleaseThreshold.
+	StuckReasonJobMissing StuckReleaseReason = "JobMissing"
+	// StuckReasonPodUnschedulable means the create job's pod cannot be scheduled.
+	StuckReasonPodUnschedulable StuckReleaseReason = "PodUnschedulable"
+	// StuckReasonUnknown covers any other cause of a long-Pending tag.
+	StuckReasonUnknown StuckReleaseReason = "Unknown"
+)
+
+// StuckRelease describes one Pending tag that has exceeded stuckReleaseThreshold.
+type StuckRelease struct {
+	Stream string
+	Tag    string
+	Since  time.Time
+	Reason StuckReleaseReason
+}
+
+// stuckReleaseLoop periodically scans Pending tags across all release streams for
+// ones that have stopped making progress, classifies the cause, and either retries
+// the stuck create job or marks the tag Failed with a precise reason so operators
+// don't have to discover it hours later from a user report.
+func (c *Controller) stuckReleaseLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		for _, stuck := range c.findStuckReleases() {
+			if err := c.remediateStuckRelease(stuck); err != nil {
+				glog.Errorf("Unable to remediate stuck release %s:%s: %v", stuck.Stream, stuck.Tag, err)
+			}
+		}
+	}, 5*time.Minute, stopCh)
+}
+
+// findStuckReleases scans every release-managed image stream for Pending tags
+// that are older than stuckReleaseThreshold and classifies each.
+func (c *Controller) findStuckReleases() []StuckRelease {
+	imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+	if err != nil {
+		glog.V(4).Infof("Unable to list image streams for stuck release detection: %v", err)
+		return nil
+	}
+	now := time.Now()
+	var stuck []StuckRelease
+	for _, stream := range imageStreams {
+		release, ok, err := c.releaseDefinition(stream)
+		if err != nil || !ok {
+			continue
+		}
+		for _, tag := range findTagReferencesByPhase(release, releasePhasePending) {
+			created, err := time.Parse(time.RFC3339, tag.Annotations[releaseAnnotationCreationTimestamp])
+			if err != nil || now.Sub(created) < stuckReleaseThreshold {
+				continue
+			}
+			stuck = append(stuck, StuckRelease{
+				Stream: release.Source.Name,
+				Tag:    tag.Name,
+				Since:  created,
+				Reason: c.classifyStuckRelease(release, tag.Name),
+			})
+		}
+	}
+	return stuck
+}
+
+func (c *Controller) classifyStuckRelease(release *Release, tagName string) StuckReleaseReason {
+	job, err := c.jobLister.Jobs(c.jobNamespace).Get(tagName)
+	if errors.IsNotFound(err) {
+		return StuckReasonJobMissing
+	}
+	if err != nil {
+		return StuckReasonUnknown
+	}
+	pods, err := c.podClient.Pods(job.Namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"controller-uid": string(job.UID)}).String(),
+	})
+	if err != nil {
+		return StuckReasonUnknown
+	}
+	for _, pod := range pods.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable {
+				return StuckReasonPodUnschedulable
+			}
+		}
+	}
+	return StuckReasonUnknown
+}
+
+// remediateStuckRelease either retries the create job (for a bounded number of
+// attempts) or marks the tag Failed with a reason precise enough that an operator
+// doesn't need to dig through job logs to understand what happened.
+func (c *Controller) remediateStuckRelease(stuck StuckRelease) error {
+	release, tag, err := c.findReleaseTag(stuck.Stream, stuck.Tag)
+	if err != nil || release == nil || tag == nil {
+		return err
+	}
+
+	switch stuck.Reason {
+	case StuckReasonPodUnschedulable:
+		retries, _ := strconv.Atoi(tag.Annotations[releaseAnnotationStuckRetries])
+		if retries < stuckReleaseMaxRetries {
+			glog.V(2).Infof("Retrying stuck release %s (attempt %d) because its pod could not be scheduled", stuck.Tag, retries+1)
+			if err := c.jobClient.Jobs(c.jobNamespace).Delete(stuck.Tag, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return c.setReleaseAnnotation(release, releasePhasePending, map[string]string{
+				releaseAnnotationStuckRetries: strconv.Itoa(retries + 1),
+			}, stuck.Tag)
+		}
+		return c.transitionReleasePhaseFailure(release, []string{releasePhasePending}, releasePhaseFailed,
+			reasonAndMessage("PodUnschedulable", "The release creation pod could not be scheduled after repeated retries"), stuck.Tag)
+	case StuckReasonJobMissing:
+		return c.transitionReleasePhaseFailure(release, []string{releasePhasePending}, releasePhaseFailed,
+			reasonAndMessage("CreateJobMissing", "No release creation job exists for this tag"), stuck.Tag)
+	default:
+		return c.transitionReleasePhaseFailure(release, []string{releasePhasePending}, releasePhaseFailed,
+			reasonAndMessage("Stuck", "The release has made no progress for longer than expected"), stuck.Tag)
+	}
+}
+
+// findReleaseTag re-resolves a release and its current tag by name, since
+// findStuckReleases runs against a point-in-time lister snapshot that may be stale
+// by the time remediation runs.
+func (c *Controller) findReleaseTag(streamName, tagName string) (*Release, *imagev1.TagReference, error) {
+	stream, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).Get(streamName)
+	if errors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	release, ok, err := c.releaseDefinition(stream)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	tag := findTagReference(release.Target, tagName)
+	return release, tag, nil
+}
+
+// httpStuck serves the list of Pending tags currently considered stuck, exposing
+// the same classification stuckReleaseLoop uses to drive remediation so operators
+// can see a stuck payload as soon as it's detected instead of waiting on a report.
+func (c *Controller) httpStuck(w http.ResponseWriter, req *http.Request) {
+	data, err := json.MarshalIndent(c.findStuckReleases(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
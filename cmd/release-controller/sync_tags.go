@@ -39,7 +39,12 @@ func (c *Controller) createReleaseTag(release *Release, now time.Time, inputImag
 	}
 	updateReleaseTarget(release, is)
 
-	return &is.Spec.Tags[len(is.Spec.Tags)-1], nil
+	created := &is.Spec.Tags[len(is.Spec.Tags)-1]
+	c.emitCloudEvent(cloudEventTagCreated, created.Name, map[string]string{
+		"release": release.Config.Name,
+		"tag":     created.Name,
+	})
+	return created, nil
 }
 
 func (c *Controller) replaceReleaseTagWithNext(release *Release, tag *imagev1.TagReference) error {
@@ -105,6 +110,49 @@ func (c *Controller) removeReleaseTags(release *Release, removeTags []*imagev1.T
 	return nil
 }
 
+// setTagKeep sets or clears releaseAnnotationKeep on the named tags,
+// regardless of their current phase. Unlike setReleaseAnnotation this is not
+// gated on the tag already being in a specific phase - pinning a tag against
+// GC is meant to work no matter what stage of its lifecycle it's in.
+func (c *Controller) setTagKeep(release *Release, keep bool, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	changes := 0
+	target := release.Target.DeepCopy()
+	for _, name := range names {
+		tag := findTagReference(target, name)
+		if tag == nil {
+			return fmt.Errorf("release %s no longer exists", name)
+		}
+		_, hadKeep := tag.Annotations[releaseAnnotationKeep]
+		if keep == hadKeep {
+			continue
+		}
+		if keep {
+			if tag.Annotations == nil {
+				tag.Annotations = make(map[string]string)
+			}
+			tag.Annotations[releaseAnnotationKeep] = "true"
+		} else {
+			delete(tag.Annotations, releaseAnnotationKeep)
+		}
+		changes++
+	}
+
+	if changes == 0 {
+		return nil
+	}
+
+	is, err := c.imageClient.ImageStreams(target.Namespace).Update(target)
+	if err != nil {
+		return err
+	}
+	updateReleaseTarget(release, is)
+	return nil
+}
+
 func (c *Controller) setReleaseAnnotation(release *Release, phase string, annotations map[string]string, names ...string) error {
 	is := release.Target
 
@@ -168,7 +216,7 @@ func (c *Controller) ensureReleaseTagPhase(release *Release, preconditionPhases
 		return nil
 	}
 
-	changes := 0
+	var changedNames []string
 	target := release.Target.DeepCopy()
 	for _, name := range names {
 		tag := findTagReference(target, name)
@@ -199,11 +247,11 @@ func (c *Controller) ensureReleaseTagPhase(release *Release, preconditionPhases
 				continue
 			}
 		}
-		changes++
+		changedNames = append(changedNames, name)
 		glog.V(2).Infof("Marking release %s %s", name, phase)
 	}
 
-	if changes == 0 {
+	if len(changedNames) == 0 {
 		return nil
 	}
 
@@ -212,12 +260,20 @@ func (c *Controller) ensureReleaseTagPhase(release *Release, preconditionPhases
 		return err
 	}
 	updateReleaseTarget(release, is)
+	for _, name := range changedNames {
+		c.emitCloudEvent(cloudEventTagPhase, name, map[string]string{
+			"release": release.Config.Name,
+			"tag":     name,
+			"phase":   phase,
+		})
+		c.notifyPhaseTransition(release, c.notificationEventForTag(release, name, phase))
+	}
 	return nil
 }
 
 func (c *Controller) transitionReleasePhaseFailure(release *Release, preconditionPhases []string, phase string, annotations map[string]string, names ...string) error {
 	target := release.Target.DeepCopy()
-	changed := 0
+	var changedNames []string
 	for _, name := range names {
 		if tag := findTagReference(target, name); tag != nil {
 			if current := tag.Annotations[releaseAnnotationPhase]; !containsString(preconditionPhases, current) {
@@ -231,10 +287,10 @@ func (c *Controller) transitionReleasePhaseFailure(release *Release, preconditio
 				tag.Annotations[k] = v
 			}
 			glog.V(2).Infof("Marking release %s failed: %v", name, annotations)
-			changed++
+			changedNames = append(changedNames, name)
 		}
 	}
-	if changed == 0 {
+	if len(changedNames) == 0 {
 		// release tags have all been deleted
 		return nil
 	}
@@ -247,6 +303,14 @@ func (c *Controller) transitionReleasePhaseFailure(release *Release, preconditio
 		return err
 	}
 	updateReleaseTarget(release, is)
+	for _, name := range changedNames {
+		c.emitCloudEvent(cloudEventTagPhase, name, map[string]string{
+			"release": release.Config.Name,
+			"tag":     name,
+			"phase":   phase,
+		})
+		c.notifyPhaseTransition(release, c.notificationEventForTag(release, name, phase))
+	}
 	return nil
 }
 
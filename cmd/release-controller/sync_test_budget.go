@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// testBudgetDefaultWindowDays is used when a TestBudgetConfig doesn't specify
+// WindowDays.
+const testBudgetDefaultWindowDays = 30
+
+// testBudgetWindow returns cfg's budget window as a duration, applying
+// testBudgetDefaultWindowDays when WindowDays is unset.
+func testBudgetWindow(cfg *TestBudgetConfig) time.Duration {
+	days := cfg.WindowDays
+	if days <= 0 {
+		days = testBudgetDefaultWindowDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// testBudgetRecord is one completed ProwJob verification's contribution to a
+// stream's CI spend: how long it ran, and whether it was later found to be one
+// of the non-optional, Failed steps behind its tag's rejection.
+type testBudgetRecord struct {
+	step       string
+	tag        string
+	seconds    float64
+	rejected   bool
+	recordedAt time.Time
+}
+
+// testBudgetTracker retains recent ProwJob verification completions per
+// stream, to power the budget utilization suggestions served at
+// /api/v1/releasestream/{release}/budget. It is in-memory only: a controller
+// restart loses history, which only delays suggestions until the window
+// refills rather than producing incorrect ones, an acceptable tradeoff since
+// the feature is advisory and has no effect on acceptance.
+type testBudgetTracker struct {
+	lock    sync.Mutex
+	records map[string][]testBudgetRecord
+}
+
+func newTestBudgetTracker() *testBudgetTracker {
+	return &testBudgetTracker{records: make(map[string][]testBudgetRecord)}
+}
+
+// record appends a completed step's contribution for stream, pruning entries
+// older than window so memory use stays bounded to the configured window
+// rather than growing for the life of the process.
+func (t *testBudgetTracker) record(stream string, rec testBudgetRecord, window time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	cutoff := time.Now().Add(-window)
+	kept := t.records[stream][:0]
+	for _, r := range t.records[stream] {
+		if r.recordedAt.After(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	t.records[stream] = append(kept, rec)
+}
+
+// markRejected flags the most recent record for stream/tag/step, if any, as
+// having contributed to that tag's rejection.
+func (t *testBudgetTracker) markRejected(stream, tag, step string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	records := t.records[stream]
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].tag == tag && records[i].step == step {
+			records[i].rejected = true
+			return
+		}
+	}
+}
+
+// snapshot returns a copy of stream's records still within window.
+func (t *testBudgetTracker) snapshot(stream string, window time.Duration) []testBudgetRecord {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	cutoff := time.Now().Add(-window)
+	var out []testBudgetRecord
+	for _, r := range t.records[stream] {
+		if r.recordedAt.After(cutoff) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// upgradeMatrixInterval controls how often upgradeMatrixLoop looks for missing
+// upgrade edges. Coverage gaps close slowly relative to release cadence, so this
+// runs far less often than the sync loop.
+const upgradeMatrixInterval = 30 * time.Minute
+
+// upgradeMatrixGap is a missing upgrade edge the planner wants a prow job run for.
+type upgradeMatrixGap struct {
+	From *imagev1.TagReference
+	To   *imagev1.TagReference
+}
+
+// upgradeMatrixBudget tracks how many synthetic upgrade jobs each stream has
+// scheduled today, so planning can honor Config.UpgradeMatrix.MaxPerDay without a
+// full scheduler. Counts reset the first time a new day is observed.
+type upgradeMatrixBudget struct {
+	lock  sync.Mutex
+	spent map[string]int
+	day   int
+}
+
+func newUpgradeMatrixBudget() *upgradeMatrixBudget {
+	return &upgradeMatrixBudget{spent: make(map[string]int)}
+}
+
+// take reports whether stream may schedule one more synthetic job today, and
+// reserves it if so.
+func (b *upgradeMatrixBudget) take(stream string, max int, now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if day := now.Year()*366 + now.YearDay(); day != b.day {
+		b.day = day
+		b.spent = make(map[string]int)
+	}
+	if b.spent[stream] >= max {
+		return false
+	}
+	b.spent[stream]++
+	return true
+}
+
+// upgradeMatrixLoop periodically schedules prow jobs to fill missing upgrade edges
+// for every stream that configures UpgradeMatrix.
+func (c *Controller) upgradeMatrixLoop(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		imageStreams, err := c.imageStreamLister.ImageStreams(c.releaseNamespace).List(labels.Everything())
+		if err != nil {
+			glog.V(4).Infof("Unable to list image streams for upgrade matrix planning: %v", err)
+			return
+		}
+		now := time.Now()
+		for _, stream := range imageStreams {
+			release, ok, err := c.releaseDefinition(stream)
+			if err != nil || !ok || release.Config.UpgradeMatrix == nil {
+				continue
+			}
+			for _, gap := range c.planUpgradeMatrixGaps(release) {
+				if !c.upgradeMatrixBudget.take(release.Config.Name, release.Config.UpgradeMatrix.MaxPerDay, now) {
+					glog.V(4).Infof("Upgrade matrix budget exhausted for %s, deferring %s->%s until tomorrow", release.Config.Name, gap.From.Name, gap.To.Name)
+					break
+				}
+				if _, err := c.scheduleSyntheticUpgrade(release, gap); err != nil {
+					glog.Errorf("Unable to schedule synthetic upgrade job %s->%s for %s: %v", gap.From.Name, gap.To.Name, release.Config.Name, err)
+				}
+			}
+		}
+	}, upgradeMatrixInterval, stopCh)
+}
+
+// planUpgradeMatrixGaps implements the "every rally point to latest" policy: the
+// latest Accepted tag of every earlier minor version should have a recorded upgrade
+// edge to the stream's current latest Accepted tag. It only detects edges with no
+// recorded results at all - UpgradeResult carries no timestamp, so staleness of an
+// edge that already has results can't be determined from the graph and is out of
+// scope here.
+func (c *Controller) planUpgradeMatrixGaps(release *Release) []upgradeMatrixGap {
+	accepted := tagsForRelease(release, releasePhaseAccepted)
+	if len(accepted) == 0 {
+		return nil
+	}
+	latest := accepted[0]
+	latestVersion, err := parseStreamVersion(release.Config.VersionScheme, latest.Name)
+	if err != nil {
+		return nil
+	}
+
+	type minor struct {
+		major, minor uint64
+	}
+	rallyPoints := make(map[minor]*imagev1.TagReference)
+	for _, tag := range accepted {
+		v, err := parseStreamVersion(release.Config.VersionScheme, tag.Name)
+		if err != nil {
+			continue
+		}
+		key := minor{v.Major, v.Minor}
+		if _, ok := rallyPoints[key]; !ok {
+			rallyPoints[key] = tag
+		}
+	}
+	delete(rallyPoints, minor{latestVersion.Major, latestVersion.Minor})
+
+	existingEdges := make(map[string]bool)
+	for _, h := range c.graph.UpgradesTo(latest.Name) {
+		if h.Total > 0 {
+			existingEdges[h.From] = true
+		}
+	}
+
+	var gaps []upgradeMatrixGap
+	for _, tag := range rallyPoints {
+		if existingEdges[tag.Name] {
+			continue
+		}
+		gaps = append(gaps, upgradeMatrixGap{From: tag, To: latest})
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i].From.Name < gaps[j].From.Name })
+	return gaps
+}
+
+// scheduleSyntheticUpgrade triggers a prow job for gap using the same machinery a
+// configured Upgrade verification step uses, so the existing prow informer loop
+// that watches for release.openshift.io/verify jobs picks up its result and records
+// it into the upgrade graph without any additional wiring.
+func (c *Controller) scheduleSyntheticUpgrade(release *Release, gap upgradeMatrixGap) (*unstructured.Unstructured, error) {
+	verifyName := "synthetic-upgrade-from-" + gap.From.Name
+	verifyType := ReleaseVerification{
+		Optional: true,
+		Upgrade:  true,
+		ProwJob:  &ProwJobVerification{Name: release.Config.UpgradeMatrix.ProwJob},
+	}
+	previousPullSpec := findPublicImagePullSpec(release.Target, gap.From.Name)
+	glog.V(2).Infof("Scheduling synthetic upgrade job for %s: %s -> %s", release.Config.Name, gap.From.Name, gap.To.Name)
+	return c.ensureProwJobForReleaseTag(release, verifyName, verifyType, gap.To, gap.From.Name, previousPullSpec)
+}
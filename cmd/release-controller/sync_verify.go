@@ -3,17 +3,134 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
 	"sort"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/golang/glog"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
 	imagev1 "github.com/openshift/api/image/v1"
+
+	prowapiv1 "github.com/openshift/release-controller/pkg/prow/apiv1"
 )
 
-func (c *Controller) ensureVerificationJobs(release *Release, releaseTag *imagev1.TagReference) (VerificationStatusMap, error) {
+// resolveVerificationProfile picks the VerificationProfiles entry
+// ReleaseConfig.ProfileSchedule selects for a tag created at createdAt, or
+// ("", nil) if VerificationProfiles or ProfileSchedule is unset, or no rule
+// matches, meaning every enabled Verify step should run unrestricted.
+func resolveVerificationProfile(config *ReleaseConfig, createdAt time.Time) (string, *VerificationProfile) {
+	if len(config.VerificationProfiles) == 0 || len(config.ProfileSchedule) == 0 {
+		return "", nil
+	}
+	createdAt = createdAt.UTC()
+	weekday := createdAt.Weekday().String()
+	hour := createdAt.Hour()
+	for _, rule := range config.ProfileSchedule {
+		if len(rule.Days) > 0 && !containsString(rule.Days, weekday) {
+			continue
+		}
+		if len(rule.Hours) > 0 && !containsInt(rule.Hours, hour) {
+			continue
+		}
+		if profile, ok := config.VerificationProfiles[rule.Profile]; ok {
+			return rule.Profile, &profile
+		}
+	}
+	return "", nil
+}
+
+func containsInt(arr []int, i int) bool {
+	for _, v := range arr {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveVerifyForTag returns the Verify map and applied profile name to use
+// for releaseTag. If releaseTag already has a profile recorded on
+// releaseAnnotationVerificationProfile that profile is reused so the applied
+// profile stays fixed for the life of the tag; otherwise one is resolved fresh
+// from ReleaseConfig.ProfileSchedule and releaseTag's creation time. When a
+// profile applies, every Verify step not in its Only list is treated as
+// Disabled for this tag, without altering release.Config.Verify itself.
+func effectiveVerifyForTag(release *Release, releaseTag *imagev1.TagReference) (map[string]ReleaseVerification, string) {
+	var profileName string
+	var profile *VerificationProfile
+	if recorded := releaseTag.Annotations[releaseAnnotationVerificationProfile]; len(recorded) > 0 {
+		profileName = recorded
+		if p, ok := release.Config.VerificationProfiles[recorded]; ok {
+			profile = &p
+		}
+	} else {
+		createdAt, _ := time.Parse(time.RFC3339, releaseTag.Annotations[releaseAnnotationCreationTimestamp])
+		profileName, profile = resolveVerificationProfile(release.Config, createdAt)
+	}
+	if profile == nil {
+		return release.Config.Verify, profileName
+	}
+	effective := make(map[string]ReleaseVerification, len(release.Config.Verify))
+	for name, verify := range release.Config.Verify {
+		if !containsString(profile.Only, name) {
+			verify.Disabled = true
+		}
+		effective[name] = verify
+	}
+	return effective, profileName
+}
+
+// runSynchronousVerification runs the shared bookkeeping for a verification
+// type the controller evaluates itself rather than delegating to a job or an
+// external report: skip re-running a step that already reached a terminal
+// state, otherwise call checkFn, record its result into *verifyStatus
+// (allocating the map if this is its first entry), and emit a
+// cloudEventVerificationState event. It returns nil if the step was already
+// terminal and the caller should continue to the next verification step, or
+// the computed status otherwise, for checks that need to react to it further
+// (e.g. recording a Kubernetes event on a Blocked result).
+func (c *Controller) runSynchronousVerification(release *Release, releaseTag *imagev1.TagReference, verifyStatus *VerificationStatusMap, name string, checkFn func() *VerificationStatus) *VerificationStatus {
+	if status, ok := (*verifyStatus)[name]; ok {
+		switch status.State {
+		case releaseVerificationStateFailed, releaseVerificationStateSucceeded:
+			// we've already processed this, continue
+			return nil
+		}
+	}
+	if *verifyStatus == nil {
+		*verifyStatus = make(VerificationStatusMap)
+	}
+	status := checkFn()
+	(*verifyStatus)[name] = status
+	c.emitCloudEvent(cloudEventVerificationState, releaseTag.Name, map[string]string{
+		"release":      release.Config.Name,
+		"tag":          releaseTag.Name,
+		"verification": name,
+		"state":        status.State,
+	})
+	return status
+}
+
+func (c *Controller) ensureVerificationJobs(release *Release, releaseTag *imagev1.TagReference, verify map[string]ReleaseVerification) (VerificationStatusMap, error) {
 	var verifyStatus VerificationStatusMap
-	for name, verifyType := range release.Config.Verify {
+	if data := releaseTag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
+		verifyStatus = make(VerificationStatusMap)
+		start := time.Now()
+		err := json.Unmarshal([]byte(data), &verifyStatus)
+		annotationCodecDurationSeconds.WithLabelValues("decode").Observe(time.Since(start).Seconds())
+		if err != nil {
+			glog.Errorf("Release %s has invalid verification status, ignoring: %v", releaseTag.Name, err)
+		}
+	}
+
+	for name, verifyType := range verify {
 		if verifyType.Disabled {
 			glog.V(2).Infof("Release verification step %s is disabled, ignoring", name)
 			continue
@@ -21,75 +138,105 @@ func (c *Controller) ensureVerificationJobs(release *Release, releaseTag *imagev
 
 		switch {
 		case verifyType.ProwJob != nil:
-			if verifyStatus == nil {
-				if data := releaseTag.Annotations[releaseAnnotationVerify]; len(data) > 0 {
-					verifyStatus = make(VerificationStatusMap)
-					if err := json.Unmarshal([]byte(data), &verifyStatus); err != nil {
-						glog.Errorf("Release %s has invalid verification status, ignoring: %v", releaseTag.Name, err)
-					}
-				}
-			}
-
+			serializeHolder := fmt.Sprintf("%s/%s:%s", release.Config.Name, releaseTag.Name, name)
 			if status, ok := verifyStatus[name]; ok {
 				switch status.State {
 				case releaseVerificationStateFailed, releaseVerificationStateSucceeded:
 					// we've already processed this, continue
+					if len(verifyType.SerializeGroup) > 0 {
+						c.serializeGroups.release(verifyType.SerializeGroup, serializeHolder)
+					}
 					continue
-				case releaseVerificationStatePending:
+				case releaseVerificationStateErrored, releaseVerificationStateAborted:
+					retryable, exhausted, err := c.retryInfraFailure(release, releaseTag, name)
+					if err != nil {
+						return nil, err
+					}
+					if !retryable {
+						if !exhausted {
+							// still backing off: leave the status as-is and
+							// re-check on the next sync
+							if len(verifyType.SerializeGroup) > 0 {
+								c.serializeGroups.release(verifyType.SerializeGroup, serializeHolder)
+							}
+							continue
+						}
+						// exhausted retries, or deliberately aborted for supersession:
+						// record as a genuine failure so acceptance can proceed
+						verifyStatus[name] = &VerificationStatus{State: releaseVerificationStateFailed, URL: status.URL, TimedOut: status.TimedOut}
+						if len(verifyType.SerializeGroup) > 0 {
+							c.serializeGroups.release(verifyType.SerializeGroup, serializeHolder)
+						}
+						continue
+					}
+					delete(verifyStatus, name)
+				case releaseVerificationStatePending, releaseVerificationStateBlocked:
 					// we need to process this
 				default:
 					glog.V(2).Infof("Unrecognized verification status %q for type %s on release %s", status.State, name, releaseTag.Name)
 				}
 			}
 
+			if len(verifyType.SerializeGroup) > 0 && !c.serializeGroups.tryAcquire(verifyType.SerializeGroup, serializeHolder) {
+				glog.V(4).Infof("Release %s verification step %s is waiting for serialize group %s", releaseTag.Name, name, verifyType.SerializeGroup)
+				if verifyStatus == nil {
+					verifyStatus = make(VerificationStatusMap)
+				}
+				verifyStatus[name] = &VerificationStatus{State: releaseVerificationStateBlocked}
+				continue
+			}
+
+			if verifyType.AggregatedProwJob != nil {
+				status, err := c.ensureAggregatedVerificationJob(release, releaseTag, name, verifyType)
+				if err != nil {
+					return nil, err
+				}
+				if verifyStatus == nil {
+					verifyStatus = make(VerificationStatusMap)
+				}
+				verifyStatus[name] = status
+				continue
+			}
+
+			if len(verifyType.UpgradeFromSources) > 0 {
+				status, err := c.ensureVerificationJobSources(release, releaseTag, name, verifyType)
+				if err != nil {
+					return nil, err
+				}
+				if verifyStatus == nil {
+					verifyStatus = make(VerificationStatusMap)
+				}
+				verifyStatus[name] = status
+				continue
+			}
+
+			if status, ok := c.verificationStatusFromDuplicate(release, releaseTag, name, verifyType); ok {
+				glog.V(2).Infof("Reusing verification result for %s on release %s from a duplicate payload tag", name, releaseTag.Name)
+				if verifyStatus == nil {
+					verifyStatus = make(VerificationStatusMap)
+				}
+				verifyStatus[name] = status
+				if len(verifyType.SerializeGroup) > 0 {
+					c.serializeGroups.release(verifyType.SerializeGroup, serializeHolder)
+				}
+				continue
+			}
+
 			// if this is an upgrade job, find the appropriate source for the upgrade job
 			var previousTag, previousReleasePullSpec string
 			if verifyType.Upgrade {
-				upgradeType := releaseUpgradeFromPrevious
-				if release.Config.As == releaseConfigModeStable {
-					upgradeType = releaseUpgradeFromPreviousPatch
-				}
-				if len(verifyType.UpgradeFrom) > 0 {
-					upgradeType = verifyType.UpgradeFrom
-				}
-				switch upgradeType {
-				case releaseUpgradeFromPrevious:
-					if tags := tagsForRelease(release, releasePhaseAccepted); len(tags) > 0 {
-						previousTag = tags[0].Name
-						previousReleasePullSpec = release.Target.Status.PublicDockerImageRepository + ":" + previousTag
-					}
-				case releaseUpgradeFromPreviousMinor:
-					if version, err := semver.Parse(releaseTag.Name); err == nil && version.Minor > 0 {
-						version.Minor--
-						if ref, err := c.stableReleases(); err == nil {
-							for _, stable := range ref.Releases {
-								versions := semanticTagsForRelease(stable.Release, releasePhaseAccepted)
-								sort.Sort(versions)
-								if v := firstTagWithMajorMinorSemanticVersion(versions, version); v != nil {
-									previousTag = v.Tag.Name
-									previousReleasePullSpec = stable.Release.Target.Status.PublicDockerImageRepository + ":" + previousTag
-									break
-								}
-							}
-						}
-					}
-				case releaseUpgradeFromPreviousPatch:
-					if version, err := semver.Parse(releaseTag.Name); err == nil {
-						if ref, err := c.stableReleases(); err == nil {
-							for _, stable := range ref.Releases {
-								versions := semanticTagsForRelease(stable.Release, releasePhaseAccepted)
-								sort.Sort(versions)
-								if v := firstTagWithMajorMinorSemanticVersion(versions, version); v != nil {
-									previousTag = v.Tag.Name
-									previousReleasePullSpec = stable.Release.Target.Status.PublicDockerImageRepository + ":" + previousTag
-									break
-								}
-							}
-						}
-					}
-				default:
+				upgradeType := defaultUpgradeType(release, verifyType)
+				resolved, pullSpec, err := c.resolveUpgradeSource(release, releaseTag, upgradeType)
+				if err != nil {
 					return nil, fmt.Errorf("release %s has verify type %s which defines invalid upgradeFrom: %s", release.Config.Name, name, upgradeType)
 				}
+				previousTag, previousReleasePullSpec = resolved, pullSpec
+			}
+
+			if verifyType.Timeout > 0 {
+				if err := c.abortTimedOutVerificationJob(release, releaseTag, name, verifyType.Timeout.Duration()); err != nil {
+					return nil, err
+				}
 			}
 
 			job, err := c.ensureProwJobForReleaseTag(release, name, verifyType, releaseTag, previousTag, previousReleasePullSpec)
@@ -103,10 +250,90 @@ func (c *Controller) ensureVerificationJobs(release *Release, releaseTag *imagev
 			if status.State == releaseVerificationStateSucceeded {
 				glog.V(2).Infof("Prow job %s for release %s succeeded, logs at %s", name, releaseTag.Name, status.URL)
 			}
+			if status.State == releaseVerificationStateFailed {
+				if fingerprint, ok := c.computeFailureFingerprint(status.URL); ok {
+					status.Fingerprint = fingerprint
+				}
+			}
 			if verifyStatus == nil {
 				verifyStatus = make(VerificationStatusMap)
 			}
 			verifyStatus[name] = status
+			switch status.State {
+			case releaseVerificationStateSucceeded, releaseVerificationStateFailed, releaseVerificationStateErrored, releaseVerificationStateAborted:
+				c.emitCloudEvent(cloudEventVerificationState, releaseTag.Name, map[string]string{
+					"release":      release.Config.Name,
+					"tag":          releaseTag.Name,
+					"verification": name,
+					"state":        status.State,
+				})
+				if release.Config.TestBudget != nil {
+					if duration, ok := prowJobDuration(job); ok {
+						c.testBudget.record(release.Config.Name, testBudgetRecord{
+							step:       name,
+							tag:        releaseTag.Name,
+							seconds:    duration.Seconds(),
+							recordedAt: time.Now(),
+						}, testBudgetWindow(release.Config.TestBudget))
+					}
+				}
+			}
+
+		case verifyType.External != nil:
+			// Results for this step are reported either by ImportExternalVerificationResult
+			// (a signed webhook, which writes directly onto releaseTag's verify
+			// annotation) or by the external system writing a well-known ConfigMap,
+			// which is picked up here. There is nothing for the controller to run.
+			if status, ok := verifyStatus[name]; !ok || status.State == releaseVerificationStatePending {
+				if imported, ok := c.importExternalVerificationConfigMap(releaseTag.Name, name); ok {
+					if verifyStatus == nil {
+						verifyStatus = make(VerificationStatusMap)
+					}
+					verifyStatus[name] = imported
+					if imported.State == releaseVerificationStateSucceeded || imported.State == releaseVerificationStateFailed {
+						c.emitCloudEvent(cloudEventVerificationState, releaseTag.Name, map[string]string{
+							"release":      release.Config.Name,
+							"tag":          releaseTag.Name,
+							"verification": name,
+							"state":        imported.State,
+						})
+					}
+				} else {
+					glog.V(4).Infof("Waiting on externally reported verification result for %s on release %s", name, releaseTag.Name)
+				}
+			}
+
+		case verifyType.Metadata != nil:
+			// This check is synchronous: the controller evaluates it itself, so
+			// there is no pending job or external report to wait on.
+			if c.runSynchronousVerification(release, releaseTag, &verifyStatus, name, func() *VerificationStatus {
+				return c.checkReleaseMetadata(release, releaseTag)
+			}) == nil {
+				continue
+			}
+
+		case verifyType.MinComponentAge != nil:
+			// This check is synchronous: the controller evaluates it itself, so
+			// there is no pending job or external report to wait on.
+			ageStatus := c.runSynchronousVerification(release, releaseTag, &verifyStatus, name, func() *VerificationStatus {
+				return c.checkMinComponentAge(release, releaseTag, verifyType.MinComponentAge.MinimumAge.Duration())
+			})
+			if ageStatus == nil {
+				continue
+			}
+			if ageStatus.State == releaseVerificationStateBlocked {
+				c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "MinComponentAgeNotMet",
+					"Release %s verification step %s: %s", releaseTag.Name, name, ageStatus.Message)
+			}
+
+		case verifyType.IssueTracker != nil:
+			// This check is synchronous: the controller evaluates it itself, so
+			// there is no pending job or external report to wait on.
+			if c.runSynchronousVerification(release, releaseTag, &verifyStatus, name, func() *VerificationStatus {
+				return c.checkIssueTracker(releaseTag, verifyType.IssueTracker)
+			}) == nil {
+				continue
+			}
 
 		default:
 			// manual verification
@@ -114,3 +341,437 @@ func (c *Controller) ensureVerificationJobs(release *Release, releaseTag *imagev
 	}
 	return verifyStatus, nil
 }
+
+// defaultUpgradeType returns the UpgradeFrom value an upgrade verification
+// step resolves to when it does not set UpgradeFrom itself.
+func defaultUpgradeType(release *Release, verifyType ReleaseVerification) string {
+	upgradeType := releaseUpgradeFromPrevious
+	if release.Config.As == releaseConfigModeStable {
+		upgradeType = releaseUpgradeFromPreviousPatch
+	}
+	if len(verifyType.UpgradeFrom) > 0 {
+		upgradeType = verifyType.UpgradeFrom
+	}
+	return upgradeType
+}
+
+// resolveUpgradeSource resolves an UpgradeFrom value to the tag name and pull
+// spec an upgrade verification job should upgrade from. Both return values
+// are empty, with no error, if upgradeType has no matching target yet (the
+// job is then run as a no-op, per ReleaseVerification.UpgradeFrom).
+func (c *Controller) resolveUpgradeSource(release *Release, releaseTag *imagev1.TagReference, upgradeType string) (string, string, error) {
+	switch upgradeType {
+	case releaseUpgradeFromPrevious:
+		if tags := tagsForRelease(release, releasePhaseAccepted); len(tags) > 0 {
+			return tags[0].Name, release.Target.Status.PublicDockerImageRepository + ":" + tags[0].Name, nil
+		}
+		return "", "", nil
+	case releaseUpgradeFromPreviousMinor:
+		version, err := semver.Parse(releaseTag.Name)
+		if err != nil || version.Minor == 0 {
+			return "", "", nil
+		}
+		version.Minor--
+		return c.resolveStablePatch(version)
+	case releaseUpgradeFromPreviousPatch:
+		version, err := semver.Parse(releaseTag.Name)
+		if err != nil {
+			return "", "", nil
+		}
+		return c.resolveStablePatch(version)
+	default:
+		return "", "", fmt.Errorf("invalid upgradeFrom: %s", upgradeType)
+	}
+}
+
+// resolveStablePatch finds the latest accepted tag sharing version's major and
+// minor version among the controller's Stable release streams.
+func (c *Controller) resolveStablePatch(version semver.Version) (string, string, error) {
+	ref, err := c.stableReleases()
+	if err != nil {
+		return "", "", nil
+	}
+	for _, stable := range ref.Releases {
+		versions := semanticTagsForRelease(stable.Release, releasePhaseAccepted)
+		sort.Sort(versions)
+		if v := firstTagWithMajorMinorSemanticVersion(versions, version); v != nil {
+			return v.Tag.Name, stable.Release.Target.Status.PublicDockerImageRepository + ":" + v.Tag.Name, nil
+		}
+	}
+	return "", "", nil
+}
+
+// ensureVerificationJobSources runs name's verification step once per entry in
+// verifyType.UpgradeFromSources, fanning the single-source upgrade path out
+// across multiple upgrade sources. Each source gets its own ProwJob (named
+// "<name>-<source>") and its own entry in the returned status's Sources map;
+// the outer State is the aggregate of those entries per
+// ReleaseVerification.UpgradeAggregation. The step holds its SerializeGroup,
+// if any, as a single holder while every source's job runs. Unlike the
+// single-source path, a source's job is not retried after an infrastructure
+// failure; each runs to completion independently.
+func (c *Controller) ensureVerificationJobSources(release *Release, releaseTag *imagev1.TagReference, name string, verifyType ReleaseVerification) (*VerificationStatus, error) {
+	aggregate := &VerificationStatus{Sources: make(map[string]*VerificationStatus, len(verifyType.UpgradeFromSources))}
+
+	succeeded, failed, pending := 0, 0, 0
+	for _, source := range verifyType.UpgradeFromSources {
+		previousTag, previousReleasePullSpec, err := c.resolveUpgradeSource(release, releaseTag, source)
+		if err != nil {
+			return nil, fmt.Errorf("release %s has verify type %s which defines invalid upgradeFromSources entry: %s", release.Config.Name, name, source)
+		}
+		job, err := c.ensureProwJobForReleaseTag(release, fmt.Sprintf("%s-%s", name, source), verifyType, releaseTag, previousTag, previousReleasePullSpec)
+		if err != nil {
+			return nil, err
+		}
+		status, ok := prowJobVerificationStatus(job)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error accessing prow job definition")
+		}
+		aggregate.Sources[source] = status
+		switch status.State {
+		case releaseVerificationStateSucceeded:
+			succeeded++
+		case releaseVerificationStateFailed, releaseVerificationStateErrored, releaseVerificationStateAborted:
+			failed++
+		default:
+			pending++
+		}
+	}
+
+	switch verifyType.UpgradeAggregation {
+	case releaseUpgradeAggregationAny:
+		switch {
+		case succeeded > 0:
+			aggregate.State = releaseVerificationStateSucceeded
+		case pending > 0:
+			aggregate.State = releaseVerificationStatePending
+		default:
+			aggregate.State = releaseVerificationStateFailed
+		}
+	default:
+		// All: every source must succeed, and any source failing fails the step
+		// outright without waiting on the rest.
+		switch {
+		case failed > 0:
+			aggregate.State = releaseVerificationStateFailed
+		case pending > 0:
+			aggregate.State = releaseVerificationStatePending
+		default:
+			aggregate.State = releaseVerificationStateSucceeded
+		}
+	}
+
+	if aggregate.State == releaseVerificationStateSucceeded || aggregate.State == releaseVerificationStateFailed {
+		c.emitCloudEvent(cloudEventVerificationState, releaseTag.Name, map[string]string{
+			"release":      release.Config.Name,
+			"tag":          releaseTag.Name,
+			"verification": name,
+			"state":        aggregate.State,
+		})
+	}
+	return aggregate, nil
+}
+
+// ensureAggregatedVerificationJob runs name's verification step as
+// verifyType.AggregatedProwJob.Count independent copies of the same ProwJob
+// (named "<name>-<index>") instead of once, and accepts the step once at
+// least verifyType.AggregatedProwJob.Threshold of them have succeeded. The
+// outer status's Sources map holds each copy's individual result, keyed by
+// its index, and its Message holds the pass count. The release tag
+// annotation carries both; the status page shows the aggregate State and
+// Message but not the per-run results. The step holds its SerializeGroup, if
+// any, as a single holder while all copies run; a copy is not retried after
+// an infrastructure failure, and each runs to completion independently.
+func (c *Controller) ensureAggregatedVerificationJob(release *Release, releaseTag *imagev1.TagReference, name string, verifyType ReleaseVerification) (*VerificationStatus, error) {
+	var previousTag, previousReleasePullSpec string
+	if verifyType.Upgrade {
+		upgradeType := defaultUpgradeType(release, verifyType)
+		resolved, pullSpec, err := c.resolveUpgradeSource(release, releaseTag, upgradeType)
+		if err != nil {
+			return nil, fmt.Errorf("release %s has verify type %s which defines invalid upgradeFrom: %s", release.Config.Name, name, upgradeType)
+		}
+		previousTag, previousReleasePullSpec = resolved, pullSpec
+	}
+
+	count := verifyType.AggregatedProwJob.Count
+	aggregate := &VerificationStatus{Sources: make(map[string]*VerificationStatus, count)}
+
+	succeeded, pending := 0, 0
+	for i := 0; i < count; i++ {
+		runName := fmt.Sprintf("%d", i)
+		job, err := c.ensureProwJobForReleaseTag(release, fmt.Sprintf("%s-%s", name, runName), verifyType, releaseTag, previousTag, previousReleasePullSpec)
+		if err != nil {
+			return nil, err
+		}
+		status, ok := prowJobVerificationStatus(job)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error accessing prow job definition")
+		}
+		aggregate.Sources[runName] = status
+		switch status.State {
+		case releaseVerificationStateSucceeded:
+			succeeded++
+		case releaseVerificationStateFailed, releaseVerificationStateErrored, releaseVerificationStateAborted:
+			// counted implicitly: anything not succeeded or pending
+		default:
+			pending++
+		}
+	}
+
+	threshold := verifyType.AggregatedProwJob.Threshold
+	switch {
+	case succeeded >= threshold:
+		aggregate.State = releaseVerificationStateSucceeded
+	case succeeded+pending < threshold:
+		// even if every still-pending run succeeds, the threshold can't be met
+		aggregate.State = releaseVerificationStateFailed
+	default:
+		aggregate.State = releaseVerificationStatePending
+	}
+	aggregate.Message = fmt.Sprintf("%d/%d passed, %d required", succeeded, count, threshold)
+
+	if aggregate.State == releaseVerificationStateSucceeded || aggregate.State == releaseVerificationStateFailed {
+		c.emitCloudEvent(cloudEventVerificationState, releaseTag.Name, map[string]string{
+			"release":      release.Config.Name,
+			"tag":          releaseTag.Name,
+			"verification": name,
+			"state":        aggregate.State,
+		})
+	}
+	return aggregate, nil
+}
+
+// cancelVerificationJobs aborts any still-running prow jobs created for releaseTag's
+// verification steps, so CI capacity isn't held by a release that will never be
+// accepted because a newer tag has already superseded it.
+func (c *Controller) cancelVerificationJobs(release *Release, releaseTag *imagev1.TagReference) error {
+	for name, verifyType := range release.Config.Verify {
+		if verifyType.Disabled || verifyType.ProwJob == nil {
+			continue
+		}
+		if len(verifyType.SerializeGroup) > 0 {
+			c.serializeGroups.release(verifyType.SerializeGroup, fmt.Sprintf("%s/%s:%s", release.Config.Name, releaseTag.Name, name))
+		}
+		verifyNames := []string{name}
+		switch {
+		case verifyType.AggregatedProwJob != nil:
+			verifyNames = nil
+			for i := 0; i < verifyType.AggregatedProwJob.Count; i++ {
+				verifyNames = append(verifyNames, fmt.Sprintf("%s-%d", name, i))
+			}
+		case len(verifyType.UpgradeFromSources) > 0:
+			verifyNames = nil
+			for _, source := range verifyType.UpgradeFromSources {
+				verifyNames = append(verifyNames, fmt.Sprintf("%s-%s", name, source))
+			}
+		}
+		for _, verifyName := range verifyNames {
+			if err := c.cancelVerificationJob(release, releaseTag, verifyName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cancelVerificationJob aborts verifyName's still-running prow job for
+// releaseTag, if one exists.
+func (c *Controller) cancelVerificationJob(release *Release, releaseTag *imagev1.TagReference, verifyName string) error {
+	prowJobName := fmt.Sprintf("%s-%s", releaseTag.Name, verifyName)
+	obj, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName))
+	if err != nil || !exists {
+		return nil
+	}
+	job := obj.(*unstructured.Unstructured)
+	state, _, _ := unstructured.NestedString(job.Object, "status", "state")
+	switch prowapiv1.ProwJobState(state) {
+	case prowapiv1.SuccessState, prowapiv1.FailureState, prowapiv1.ErrorState, prowapiv1.AbortedState:
+		return nil
+	}
+	job = job.DeepCopy()
+	unstructured.SetNestedField(job.Object, string(prowapiv1.AbortedState), "status", "state")
+	unstructured.SetNestedField(job.Object, metav1.Now().Format(time.RFC3339), "status", "completionTime")
+	unstructured.SetNestedField(job.Object, prowJobSupersededDescription, "status", "description")
+	if _, err := c.prowClient.Update(job, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("could not abort superseded prow job %s: %v", prowJobName, err)
+	}
+	glog.V(2).Infof("Aborted superseded prow job %s for release %s", prowJobName, releaseTag.Name)
+	return nil
+}
+
+// prowJobTimedOutDescription marks a ProwJob that abortTimedOutVerificationJob
+// deliberately aborted because it ran longer than its
+// ReleaseVerification.Timeout. Unlike prowJobSupersededDescription,
+// retryInfraFailure does retry a job aborted for this reason, since a fresh
+// run may simply not hang the way this one did.
+const prowJobTimedOutDescription = "Aborted: exceeded configured timeout"
+
+// abortTimedOutVerificationJob aborts verifyName's still-running ProwJob for
+// releaseTag if it has been running longer than timeout, so a hung job
+// doesn't block the release indefinitely. The abort is observed the same way
+// any other Aborted result is: the next read of the ProwJob's status feeds it
+// into retryInfraFailure's existing retry/backoff logic.
+func (c *Controller) abortTimedOutVerificationJob(release *Release, releaseTag *imagev1.TagReference, verifyName string, timeout time.Duration) error {
+	prowJobName := fmt.Sprintf("%s-%s", releaseTag.Name, verifyName)
+	obj, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName))
+	if err != nil || !exists {
+		return nil
+	}
+	job := obj.(*unstructured.Unstructured)
+	state, _, _ := unstructured.NestedString(job.Object, "status", "state")
+	switch prowapiv1.ProwJobState(state) {
+	case prowapiv1.SuccessState, prowapiv1.FailureState, prowapiv1.ErrorState, prowapiv1.AbortedState:
+		return nil
+	}
+	startTimeValue, _, _ := unstructured.NestedString(job.Object, "status", "startTime")
+	startTime, err := time.Parse(time.RFC3339, startTimeValue)
+	if err != nil || time.Since(startTime) < timeout {
+		return nil
+	}
+
+	job = job.DeepCopy()
+	unstructured.SetNestedField(job.Object, string(prowapiv1.AbortedState), "status", "state")
+	unstructured.SetNestedField(job.Object, metav1.Now().Format(time.RFC3339), "status", "completionTime")
+	unstructured.SetNestedField(job.Object, prowJobTimedOutDescription, "status", "description")
+	if _, err := c.prowClient.Update(job, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("could not abort timed out prow job %s: %v", prowJobName, err)
+	}
+	c.eventRecorder.Eventf(release.Source, corev1.EventTypeWarning, "VerificationTimedOut",
+		"Verification step %s for %s exceeded its %s timeout and was aborted", verifyName, releaseTag.Name, timeout)
+	glog.V(2).Infof("Aborted timed out prow job %s for release %s after %s", prowJobName, releaseTag.Name, timeout)
+	return nil
+}
+
+// prowJobSupersededDescription marks a ProwJob that cancelVerificationJobs
+// deliberately aborted because a newer release tag superseded it. retryInfraFailure
+// recognizes this description and does not retry such a job, since retrying would
+// defeat the point of canceling it to reclaim CI capacity.
+const prowJobSupersededDescription = "Aborted: superseded by a newer release"
+
+// maxVerificationInfraRetries bounds how many times ensureVerificationJobs
+// retries a verification step whose ProwJob ended in Errored or Aborted, an
+// infrastructure failure rather than a genuine test failure, before giving up
+// and recording it as a real Failed result.
+const maxVerificationInfraRetries = 3
+
+// defaultRetryBackoffInitial, defaultRetryBackoffFactor, and
+// defaultRetryBackoffCap are the backoff parameters retryInfraFailure applies
+// when a stream doesn't configure ReleaseConfig.RetryBackoff.
+const (
+	defaultRetryBackoffInitial = time.Minute
+	defaultRetryBackoffFactor  = 2
+	defaultRetryBackoffCap     = 15 * time.Minute
+)
+
+// calculateBackoff returns how long retryInfraFailure should wait before the
+// (attempt+1)th retry, applying policy's overrides (or the package defaults
+// for any field policy leaves unset) and, if policy.Jitter is set,
+// randomizing the result by up to that fraction so releases sharing a
+// backoff schedule don't all retry in lockstep.
+func calculateBackoff(policy *RetryBackoff, attempt int) time.Duration {
+	initial, factor, cap := defaultRetryBackoffInitial, float64(defaultRetryBackoffFactor), defaultRetryBackoffCap
+	var jitter float64
+	if policy != nil {
+		if policy.Initial > 0 {
+			initial = policy.Initial.Duration()
+		}
+		if policy.Factor > 0 {
+			factor = policy.Factor
+		}
+		if policy.Cap > 0 {
+			cap = policy.Cap.Duration()
+		}
+		jitter = policy.Jitter
+	}
+	delay := float64(initial) * math.Pow(factor, float64(attempt))
+	if delay > float64(cap) {
+		delay = float64(cap)
+	}
+	if jitter > 0 {
+		delay += delay * jitter * (rand.Float64()*2 - 1)
+		if delay < 0 {
+			delay = 0
+		}
+	}
+	return time.Duration(delay)
+}
+
+// infraRetryState tracks the infra-failure retry history of a single
+// verification step.
+type infraRetryState struct {
+	Count       int    `json:"count"`
+	LastAttempt string `json:"lastAttempt,omitempty"`
+}
+
+// infraRetryInfo returns tag's recorded infra-failure retry state for
+// verification step name, or the zero value if none is recorded.
+func infraRetryInfo(tag *imagev1.TagReference, name string) infraRetryState {
+	data := tag.Annotations[releaseAnnotationInfraRetries]
+	if len(data) == 0 {
+		return infraRetryState{}
+	}
+	var states map[string]infraRetryState
+	if err := json.Unmarshal([]byte(data), &states); err != nil {
+		return infraRetryState{}
+	}
+	return states[name]
+}
+
+// retryInfraFailure decides whether a verification step whose ProwJob last
+// reported Errored or Aborted should be retried. retry is true if it deleted
+// the stale ProwJob, so ensureProwJobForReleaseTag creates a fresh one, and
+// recorded the attempt. When retry is false, exhausted distinguishes a
+// permanent stop (the job was deliberately aborted by cancelVerificationJobs,
+// or maxVerificationInfraRetries has been reached), which the caller should
+// record as a genuine Failed result, from a temporary one (the stream's
+// calculateBackoff delay since the last attempt hasn't elapsed yet), which
+// the caller should leave as-is to re-check on the next sync.
+func (c *Controller) retryInfraFailure(release *Release, releaseTag *imagev1.TagReference, name string) (retry, exhausted bool, err error) {
+	prowJobName := fmt.Sprintf("%s-%s", releaseTag.Name, name)
+	obj, exists, err := c.prowLister.GetByKey(fmt.Sprintf("%s/%s", c.prowNamespace, prowJobName))
+	if err != nil {
+		return false, false, err
+	}
+	if exists {
+		if description, _, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "status", "description"); description == prowJobSupersededDescription {
+			return false, true, nil
+		}
+	}
+
+	state := infraRetryInfo(releaseTag, name)
+	if state.Count >= maxVerificationInfraRetries {
+		return false, true, nil
+	}
+	if len(state.LastAttempt) > 0 {
+		if lastAttempt, err := time.Parse(time.RFC3339, state.LastAttempt); err == nil {
+			if wait := calculateBackoff(release.Config.RetryBackoff, state.Count); time.Since(lastAttempt) < wait {
+				return false, false, nil
+			}
+		}
+	}
+
+	if exists {
+		if err := c.prowClient.Delete(prowJobName, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return false, false, err
+		}
+	}
+
+	states := map[string]infraRetryState{}
+	if data := releaseTag.Annotations[releaseAnnotationInfraRetries]; len(data) > 0 {
+		json.Unmarshal([]byte(data), &states)
+	}
+	state.Count++
+	state.LastAttempt = time.Now().Format(time.RFC3339)
+	states[name] = state
+	encoded, err := json.Marshal(states)
+	if err != nil {
+		return false, false, err
+	}
+	if err := c.setReleaseAnnotation(release, releasePhaseReady, map[string]string{
+		releaseAnnotationInfraRetries: string(encoded),
+	}, releaseTag.Name); err != nil {
+		return false, false, err
+	}
+	glog.V(2).Infof("Retrying release %s verification step %s (infra attempt %d) after prow reported an infrastructure failure", releaseTag.Name, name, state.Count)
+	return true, false, nil
+}
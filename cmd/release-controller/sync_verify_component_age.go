@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// checkMinComponentAge verifies that every component image in releaseTag's
+// payload is at least minAge old, giving component-level CI time to flag a
+// bad build via the health source before this stream races to accept it. A
+// component that hasn't aged in yet is not a permanent failure, so it is
+// reported as Blocked rather than Failed: the step is retried on every sync
+// until the component ages past the threshold or the check otherwise
+// succeeds. It records the youngest (i.e. most likely to still be under CI)
+// offending component in the returned status's Message.
+func (c *Controller) checkMinComponentAge(release *Release, releaseTag *imagev1.TagReference, minAge time.Duration) *VerificationStatus {
+	pullSpec := findPublicImagePullSpec(release.Target, releaseTag.Name)
+	if len(pullSpec) == 0 {
+		glog.Errorf("Unable to verify minComponentAge for %s: no pull spec available for this tag", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	op, err := c.releaseInfo.ReleaseInfo(pullSpec)
+	if err != nil {
+		glog.Errorf("Unable to verify minComponentAge for %s: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	info := releaseInfoShort{}
+	if err := json.Unmarshal([]byte(op), &info); err != nil {
+		glog.Errorf("Unable to verify minComponentAge for %s: could not unmarshal release info: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	if info.References == nil || len(info.References.Spec.Tags) == 0 {
+		glog.Errorf("minComponentAge verification failed for %s: release payload has no component tags", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	now := time.Now()
+	var youngestComponent string
+	youngestAge := time.Duration(-1)
+	for _, tag := range info.References.Spec.Tags {
+		if tag.From == nil || len(tag.From.Name) == 0 {
+			continue
+		}
+		created, err := c.releaseInfo.ImageCreated(tag.From.Name)
+		if err != nil {
+			glog.V(4).Infof("Unable to check minComponentAge of %s component %s: %v", releaseTag.Name, tag.Name, err)
+			continue
+		}
+		if age := now.Sub(created); youngestAge < 0 || age < youngestAge {
+			youngestAge = age
+			youngestComponent = tag.Name
+		}
+	}
+	if youngestAge < 0 {
+		// No component's age could be determined (e.g. every lookup failed
+		// transiently); don't block acceptance on an inconclusive check.
+		glog.V(4).Infof("minComponentAge check for %s could not determine any component's age, treating as passed", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateSucceeded}
+	}
+	if youngestAge < minAge {
+		return &VerificationStatus{
+			State:   releaseVerificationStateBlocked,
+			Message: fmt.Sprintf("component %s is only %s old, younger than the required minimum age of %s", youngestComponent, youngestAge.Round(time.Minute), minAge),
+		}
+	}
+	return &VerificationStatus{State: releaseVerificationStateSucceeded}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+var issueTrackerClient = &http.Client{Timeout: 10 * time.Second}
+
+// bugzillaSeverityRank orders the severity values of a Bugzilla-compatible
+// tracker from least to most severe, so IssueTrackerVerification.Severity can
+// be treated as a minimum threshold rather than an exact match -- the
+// tracker's own REST API only supports filtering on an exact severity value.
+// A severity absent from this list (e.g. a site-specific custom value) is
+// treated as meeting any threshold, since there's no way to rank it: better
+// to surface an unrecognized-but-possibly-blocking bug than to silently drop it.
+var bugzillaSeverityRank = []string{"low", "medium", "high", "urgent"}
+
+func severityMeetsThreshold(severity, threshold string) bool {
+	severity, threshold = strings.ToLower(severity), strings.ToLower(threshold)
+	thresholdRank := -1
+	for i, s := range bugzillaSeverityRank {
+		if s == threshold {
+			thresholdRank = i
+		}
+	}
+	if thresholdRank < 0 {
+		// an unrecognized threshold can't be compared against, so don't filter
+		return true
+	}
+	for i, s := range bugzillaSeverityRank {
+		if s == severity {
+			return i >= thresholdRank
+		}
+	}
+	return true
+}
+
+// bugzillaBugList is the subset of a Bugzilla REST /rest/bug response this
+// check needs.
+type bugzillaBugList struct {
+	Bugs []struct {
+		ID       int    `json:"id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"bugs"`
+}
+
+// checkIssueTracker verifies that no open bug of at least cfg.Severity,
+// targeting cfg.TargetRelease (or releaseTag's own version, if unset), is
+// reported against cfg.Product in the tracker at cfg.URL. Like
+// checkMinComponentAge, a hit here is reported as Blocked rather than Failed:
+// the step is retried on every sync until the bug is closed or reprioritized
+// below threshold, since that is the tracker's call to make, not this
+// controller's.
+func (c *Controller) checkIssueTracker(releaseTag *imagev1.TagReference, cfg *IssueTrackerVerification) *VerificationStatus {
+	targetRelease := cfg.TargetRelease
+	if len(targetRelease) == 0 {
+		if version, err := semver.Parse(releaseTag.Name); err == nil {
+			targetRelease = fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+		} else {
+			targetRelease = releaseTag.Name
+		}
+	}
+
+	q := url.Values{}
+	q.Set("product", cfg.Product)
+	q.Set("target_release", targetRelease)
+	q.Set("include_fields", "id,summary,severity")
+	for _, status := range []string{"NEW", "ASSIGNED", "POST", "MODIFIED", "ON_DEV", "ON_QA"} {
+		q.Add("bug_status", status)
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/bug?%s", strings.TrimSuffix(cfg.URL, "/"), q.Encode())
+	resp, err := issueTrackerClient.Get(endpoint)
+	if err != nil {
+		glog.Errorf("Unable to verify issueTracker for %s: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		glog.Errorf("issueTracker verification for %s: tracker returned %s", releaseTag.Name, resp.Status)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	var list bugzillaBugList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		glog.Errorf("Unable to verify issueTracker for %s: could not decode tracker response: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	var ids []string
+	for _, bug := range list.Bugs {
+		if !severityMeetsThreshold(bug.Severity, cfg.Severity) {
+			continue
+		}
+		ids = append(ids, strconv.Itoa(bug.ID))
+	}
+	if len(ids) == 0 {
+		return &VerificationStatus{State: releaseVerificationStateSucceeded}
+	}
+	return &VerificationStatus{
+		State:   releaseVerificationStateBlocked,
+		Message: fmt.Sprintf("%d open blocker bug(s) of severity %s+ targeting %s: %s", len(ids), cfg.Severity, targetRelease, strings.Join(ids, ", ")),
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestSeverityMeetsThreshold(t *testing.T) {
+	tests := []struct {
+		severity, threshold string
+		want                bool
+	}{
+		{severity: "high", threshold: "medium", want: true},
+		{severity: "low", threshold: "medium", want: false},
+		{severity: "medium", threshold: "medium", want: true},
+		{severity: "urgent", threshold: "low", want: true},
+		{severity: "HIGH", threshold: "medium", want: true},
+		{severity: "unspecified", threshold: "medium", want: true},
+		{severity: "high", threshold: "custom-unranked", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity+"/"+tt.threshold, func(t *testing.T) {
+			if got := severityMeetsThreshold(tt.severity, tt.threshold); got != tt.want {
+				t.Errorf("severityMeetsThreshold(%q, %q) = %v, want %v", tt.severity, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/glog"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// checkReleaseMetadata implements the built-in Metadata verification step. It
+// reruns the same `oc adm release info -o json` call and releaseInfoShort
+// parsing already used by tagPromotedFrom, and validates:
+//
+//   - the version embedded in the payload matches the tag it was promoted to
+//   - the previous-release pointer, if present, is a well-formed
+//     <namespace>/<name> imagestream reference
+//   - every component tag in the payload names an image
+//
+// oc adm release info -o json does not expose per-component Docker labels or
+// update-service graph metadata beyond what's captured above, so deeper label
+// or update-key validation than this is out of scope.
+func (c *Controller) checkReleaseMetadata(release *Release, releaseTag *imagev1.TagReference) *VerificationStatus {
+	pullSpec := findPublicImagePullSpec(release.Target, releaseTag.Name)
+	if len(pullSpec) == 0 {
+		glog.Errorf("Unable to verify metadata for %s: no pull spec available for this tag", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	op, err := c.releaseInfo.ReleaseInfo(pullSpec)
+	if err != nil {
+		glog.Errorf("Unable to verify metadata for %s: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	info := releaseInfoShort{}
+	if err := json.Unmarshal([]byte(op), &info); err != nil {
+		glog.Errorf("Unable to verify metadata for %s: could not unmarshal release info: %v", releaseTag.Name, err)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	if info.References == nil {
+		glog.Errorf("Metadata verification failed for %s: release info has no embedded references", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	if info.References.Name != releaseTag.Name {
+		glog.Errorf("Metadata verification failed for %s: embedded version %q does not match the tag it was promoted to", releaseTag.Name, info.References.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+
+	if from := info.References.Annotations[releaseAnnotationFromImageStream]; len(from) > 0 {
+		parts := strings.Split(from, "/")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			glog.Errorf("Metadata verification failed for %s: %s annotation %q is not of the form <namespace>/<name>", releaseTag.Name, releaseAnnotationFromImageStream, from)
+			return &VerificationStatus{State: releaseVerificationStateFailed}
+		}
+	}
+
+	if len(info.References.Spec.Tags) == 0 {
+		glog.Errorf("Metadata verification failed for %s: release payload has no component tags", releaseTag.Name)
+		return &VerificationStatus{State: releaseVerificationStateFailed}
+	}
+	for _, tag := range info.References.Spec.Tags {
+		if len(tag.Name) == 0 || tag.From == nil || len(tag.From.Name) == 0 {
+			glog.Errorf("Metadata verification failed for %s: component tag %q is missing a name or image reference", releaseTag.Name, tag.Name)
+			return &VerificationStatus{State: releaseVerificationStateFailed}
+		}
+	}
+
+	return &VerificationStatus{State: releaseVerificationStateSucceeded}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// propagatedVerificationDecision looks up the matching tag (by
+// releaseAnnotationImageHash) in release.Config.VerificationPropagation.From
+// and, if it has reached a terminal phase, returns the decision to copy onto
+// releaseTag. ok is false if propagation is not configured, the parent tag
+// can't be found, or the parent tag has not yet reached a terminal phase -
+// any of which means releaseTag should simply keep waiting rather than
+// verifying itself.
+func (c *Controller) propagatedVerificationDecision(release *Release, releaseTag *imagev1.TagReference) (phase string, annotations map[string]string, ok bool) {
+	propagation := release.Config.VerificationPropagation
+	if propagation == nil || len(propagation.From) == 0 {
+		return "", nil, false
+	}
+	hash := releaseTag.Annotations[releaseAnnotationImageHash]
+	if len(hash) == 0 {
+		return "", nil, false
+	}
+
+	parents, found := c.findReleaseByName(false, propagation.From)
+	if !found || parents[propagation.From] == nil {
+		return "", nil, false
+	}
+	parentRelease := parents[propagation.From].Release
+
+	var parentTag *imagev1.TagReference
+	for i := range parentRelease.Target.Spec.Tags {
+		tag := &parentRelease.Target.Spec.Tags[i]
+		if tag.Annotations[releaseAnnotationImageHash] == hash {
+			parentTag = tag
+			break
+		}
+	}
+	if parentTag == nil {
+		return "", nil, false
+	}
+
+	switch parentTag.Annotations[releaseAnnotationPhase] {
+	case releasePhaseAccepted, releasePhaseRejected:
+	default:
+		return "", nil, false
+	}
+
+	annotations = map[string]string{
+		releaseAnnotationVerificationPropagatedFrom: fmt.Sprintf("%s/%s", propagation.From, parentTag.Name),
+	}
+	if data, ok := parentTag.Annotations[releaseAnnotationVerify]; ok {
+		annotations[releaseAnnotationVerify] = data
+	}
+	if parentTag.Annotations[releaseAnnotationPhase] == releasePhaseRejected {
+		annotations[releaseAnnotationReason] = parentTag.Annotations[releaseAnnotationReason]
+		annotations[releaseAnnotationMessage] = fmt.Sprintf("Propagated from %s/%s: %s", propagation.From, parentTag.Name, parentTag.Annotations[releaseAnnotationMessage])
+		// From this stream's perspective the rejection is a consequence of its
+		// VerificationPropagation policy gating on the parent's decision, not a
+		// verification job this stream itself ran.
+		annotations[releaseAnnotationRejectReason] = RejectReasonPolicyGate
+	}
+	return parentTag.Annotations[releaseAnnotationPhase], annotations, true
+}
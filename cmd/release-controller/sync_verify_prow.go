@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	imagev1 "github.com/openshift/api/image/v1"
 
@@ -44,9 +46,24 @@ func (c *Controller) ensureProwJobForReleaseTag(release *Release, verifyName str
 	}
 
 	spec := prowapiv1.ProwSpecForPeriodicConfig(periodicConfig)
+
+	if err := validateRequiredEnv(verifyType.RequiredEnv, spec); err != nil {
+		c.eventRecorder.Event(release.Source, corev1.EventTypeWarning, "ProwJobInvalid", err.Error())
+		return nil, terminalError{err}
+	}
+
 	mirror, _ := c.getMirror(release, releaseTag.Name)
 
-	ok, err = addReleaseEnvToProwJobSpec(spec, release, mirror, releaseTag, previousReleasePullSpec)
+	releaseImageLatest := release.Target.Status.PublicDockerImageRepository + ":" + releaseTag.Name
+	if arch := verifyType.TargetArch; len(arch) > 0 {
+		digest, err := c.releaseInfo.ArchDigest(releaseImageLatest, arch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %s digest for release %s: %v", arch, releaseTag.Name, err)
+		}
+		releaseImageLatest = release.Target.Status.PublicDockerImageRepository + "@" + digest
+	}
+
+	ok, err = addReleaseEnvToProwJobSpec(spec, release, mirror, releaseImageLatest, previousReleasePullSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +123,23 @@ func (c *Controller) ensureProwJobForReleaseTag(release *Release, verifyName str
 	if verifyType.Upgrade && len(previousTag) > 0 {
 		pj.Annotations[releaseAnnotationFromTag] = previousTag
 	}
+	if inputs := verificationJobInputsForSpec(spec); inputs != nil {
+		data, err := json.Marshal(inputs)
+		if err != nil {
+			return nil, err
+		}
+		pj.Annotations[releaseAnnotationVerifyInputs] = string(data)
+	}
+
+	if c.dryRun {
+		glog.Infof("[dry-run] would launch prow job %s (%s) for release tag %s", prowJobName, spec.Job, releaseTag.Name)
+		// Report the step as still pending rather than persisting anything, so
+		// dry-run never fabricates a Succeeded/Failed result for a job that
+		// never actually ran.
+		pj.Status = prowapiv1.ProwJobStatus{StartTime: metav1.Now(), State: prowapiv1.TriggeredState, Description: "dry-run: job was not launched"}
+		return objectToUnstructured(pj), nil
+	}
+
 	out, err := c.prowClient.Create(objectToUnstructured(pj), metav1.CreateOptions{})
 	if errors.IsAlreadyExists(err) {
 		// find a cached version or do a live call
@@ -141,7 +175,74 @@ func objectToUnstructured(obj runtime.Object) *unstructured.Unstructured {
 	return u
 }
 
-func addReleaseEnvToProwJobSpec(spec *prowapiv1.ProwJobSpec, release *Release, mirror *imagev1.ImageStream, releaseTag *imagev1.TagReference, previousReleasePullSpec string) (bool, error) {
+// VerificationJobInputs is a snapshot of the resolved image, args, and env a
+// verification ProwJob was created with, captured onto the job itself via
+// releaseAnnotationVerifyInputs so a result can still be interpreted
+// correctly after the periodic job config that produced it has since
+// changed.
+type VerificationJobInputs struct {
+	Containers []VerificationJobContainerInputs `json:"containers"`
+}
+
+// VerificationJobContainerInputs is the resolved image, args, and env of a
+// single container within a VerificationJobInputs snapshot.
+type VerificationJobContainerInputs struct {
+	Name  string            `json:"name"`
+	Image string            `json:"image"`
+	Args  []string          `json:"args,omitempty"`
+	Env   map[string]string `json:"env,omitempty"`
+}
+
+// verificationJobInputsForSpec builds a VerificationJobInputs snapshot from
+// the pod spec addReleaseEnvToProwJobSpec has already resolved, or nil if
+// spec has no pod spec to snapshot (e.g. a Jenkins job).
+func verificationJobInputsForSpec(spec *prowapiv1.ProwJobSpec) *VerificationJobInputs {
+	if spec.PodSpec == nil {
+		return nil
+	}
+	inputs := &VerificationJobInputs{}
+	for _, container := range spec.PodSpec.Containers {
+		env := make(map[string]string, len(container.Env))
+		for _, e := range container.Env {
+			env[e.Name] = e.Value
+		}
+		inputs.Containers = append(inputs.Containers, VerificationJobContainerInputs{
+			Name:  container.Name,
+			Image: container.Image,
+			Args:  container.Args,
+			Env:   env,
+		})
+	}
+	return inputs
+}
+
+// validateRequiredEnv checks that every env var name in required is defined
+// by some container in spec.PodSpec, returning an error naming the first one
+// that is missing. A periodic job that doesn't declare a variable the
+// release config's contract requires is a configuration error, not a
+// condition addReleaseEnvToProwJobSpec should quietly no-op on.
+func validateRequiredEnv(required []string, spec *prowapiv1.ProwJobSpec) error {
+	if len(required) == 0 {
+		return nil
+	}
+	if spec.PodSpec == nil {
+		return fmt.Errorf("prow job %s has no pod spec to define required env vars %v", spec.Job, required)
+	}
+	declared := sets.NewString()
+	for _, container := range spec.PodSpec.Containers {
+		for _, e := range container.Env {
+			declared.Insert(e.Name)
+		}
+	}
+	for _, name := range required {
+		if !declared.Has(name) {
+			return fmt.Errorf("prow job %s does not define required env var %s", spec.Job, name)
+		}
+	}
+	return nil
+}
+
+func addReleaseEnvToProwJobSpec(spec *prowapiv1.ProwJobSpec, release *Release, mirror *imagev1.ImageStream, releaseImageLatest string, previousReleasePullSpec string) (bool, error) {
 	if spec.PodSpec == nil {
 		// Jenkins jobs cannot be parameterized
 		return true, nil
@@ -151,7 +252,7 @@ func addReleaseEnvToProwJobSpec(spec *prowapiv1.ProwJobSpec, release *Release, m
 		for j := range c.Env {
 			switch name := c.Env[j].Name; {
 			case name == "RELEASE_IMAGE_LATEST":
-				c.Env[j].Value = release.Target.Status.PublicDockerImageRepository + ":" + releaseTag.Name
+				c.Env[j].Value = releaseImageLatest
 			case name == "RELEASE_IMAGE_INITIAL":
 				if len(previousReleasePullSpec) == 0 {
 					return false, nil
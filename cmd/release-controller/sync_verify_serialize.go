@@ -0,0 +1,40 @@
+package main
+
+import "sync"
+
+// serializeGroupTracker enforces ReleaseVerification.SerializeGroup: at most one
+// holder per group is allowed to have a job outstanding at a time. It is a plain
+// in-memory lock table, not persisted; on controller restart every group is
+// considered free again, which is safe because a restart also re-derives every
+// holder's state from the verify annotation on its tag.
+type serializeGroupTracker struct {
+	lock    sync.Mutex
+	holders map[string]string
+}
+
+func newSerializeGroupTracker() *serializeGroupTracker {
+	return &serializeGroupTracker{holders: make(map[string]string)}
+}
+
+// tryAcquire reports whether holder now owns (or already owned) group. It fails
+// only if a different holder currently owns the group.
+func (t *serializeGroupTracker) tryAcquire(group, holder string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if current, ok := t.holders[group]; ok && current != holder {
+		return false
+	}
+	t.holders[group] = holder
+	return true
+}
+
+// release gives up group if holder currently owns it. Releasing a group not
+// owned by holder is a no-op, so callers don't need to track whether they ever
+// successfully acquired it.
+func (t *serializeGroupTracker) release(group, holder string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.holders[group] == holder {
+		delete(t.holders, group)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+func TestRunSynchronousVerification(t *testing.T) {
+	release := &Release{Config: &ReleaseConfig{Name: "4.1"}}
+	releaseTag := &imagev1.TagReference{Name: "4.1.0"}
+	c := &Controller{}
+
+	t.Run("already succeeded is skipped", func(t *testing.T) {
+		verifyStatus := VerificationStatusMap{"step": {State: releaseVerificationStateSucceeded}}
+		called := false
+		if got := c.runSynchronousVerification(release, releaseTag, &verifyStatus, "step", func() *VerificationStatus {
+			called = true
+			return &VerificationStatus{State: releaseVerificationStateFailed}
+		}); got != nil {
+			t.Errorf("runSynchronousVerification() = %v, want nil for an already-terminal step", got)
+		}
+		if called {
+			t.Errorf("checkFn was called for an already-terminal step")
+		}
+	})
+
+	t.Run("first run allocates the map and records the result", func(t *testing.T) {
+		var verifyStatus VerificationStatusMap
+		want := &VerificationStatus{State: releaseVerificationStateBlocked, Message: "waiting"}
+		got := c.runSynchronousVerification(release, releaseTag, &verifyStatus, "step", func() *VerificationStatus {
+			return want
+		})
+		if got != want {
+			t.Errorf("runSynchronousVerification() = %v, want %v", got, want)
+		}
+		if verifyStatus["step"] != want {
+			t.Errorf("verifyStatus[%q] = %v, want %v", "step", verifyStatus["step"], want)
+		}
+	})
+
+	t.Run("pending step is re-run", func(t *testing.T) {
+		verifyStatus := VerificationStatusMap{"step": {State: releaseVerificationStatePending}}
+		want := &VerificationStatus{State: releaseVerificationStateSucceeded}
+		got := c.runSynchronousVerification(release, releaseTag, &verifyStatus, "step", func() *VerificationStatus {
+			return want
+		})
+		if got != want {
+			t.Errorf("runSynchronousVerification() = %v, want %v", got, want)
+		}
+	})
+}
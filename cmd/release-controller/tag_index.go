@@ -0,0 +1,49 @@
+package main
+
+import "sync"
+
+// tagIndexEntry is the cached, stably-ordered tag name index for one release
+// stream's target image stream at a particular generation.
+type tagIndexEntry struct {
+	generation int64
+	names      []string
+	position   map[string]int
+}
+
+// tagIndexCache caches the sorted tag name order tagsForRelease would produce
+// for a release stream, keyed by the target image stream's generation, so
+// that paging through a large tag history doesn't re-sort the full tag set on
+// every request -- only the first request after the target actually changes
+// pays that cost.
+type tagIndexCache struct {
+	lock    sync.Mutex
+	entries map[string]tagIndexEntry
+}
+
+func newTagIndexCache() *tagIndexCache {
+	return &tagIndexCache{entries: make(map[string]tagIndexEntry)}
+}
+
+// indexFor returns the cached tag index for release, rebuilding it from
+// tagsForRelease if the target's generation has moved on since it was last
+// computed.
+func (c *tagIndexCache) indexFor(release *Release) tagIndexEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	name := release.Config.Name
+	if entry, ok := c.entries[name]; ok && entry.generation == release.Target.Generation {
+		return entry
+	}
+
+	tags := tagsForRelease(release)
+	names := make([]string, len(tags))
+	position := make(map[string]int, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+		position[tag.Name] = i
+	}
+	entry := tagIndexEntry{generation: release.Target.Generation, names: names, position: position}
+	c.entries[name] = entry
+	return entry
+}
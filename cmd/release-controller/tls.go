@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// reloadableCertificate serves a TLS certificate out of a pair of files,
+// reloading them the next time GetCertificate is called after either file's
+// mtime changes, so an operator can rotate --tls-cert/--tls-key without
+// restarting the controller.
+type reloadableCertificate struct {
+	certFile, keyFile string
+
+	lock                    sync.Mutex
+	cert                    *tls.Certificate
+	certModTime, keyModTime time.Time
+}
+
+// newReloadableCertificate loads certFile/keyFile once, to fail fast on a
+// bad path or malformed pair before the server starts accepting connections.
+func newReloadableCertificate(certFile, keyFile string) (*reloadableCertificate, error) {
+	c := &reloadableCertificate{certFile: certFile, keyFile: keyFile}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *reloadableCertificate) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if !certInfo.ModTime().After(c.certModTime) && !keyInfo.ModTime().After(c.keyModTime) {
+		return c.cert, nil
+	}
+	if err := c.reloadLocked(certInfo.ModTime(), keyInfo.ModTime()); err != nil {
+		// keep serving the previously loaded certificate rather than fail the
+		// handshake because of a transient or partial rewrite of the files.
+		glog.Errorf("Unable to reload TLS certificate %s: %v", c.certFile, err)
+		return c.cert, nil
+	}
+	return c.cert, nil
+}
+
+func (c *reloadableCertificate) reload() error {
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.reloadLocked(certInfo.ModTime(), keyInfo.ModTime())
+}
+
+// reloadLocked must be called with c.lock held.
+func (c *reloadableCertificate) reloadLocked(certModTime, keyModTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert = &cert
+	c.certModTime = certModTime
+	c.keyModTime = keyModTime
+	return nil
+}
+
+// loadClientCAPool reads a PEM bundle of CA certificates used to verify
+// client certificates presented on mutating requests.
+func loadClientCAPool(caFile string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s does not contain any valid PEM-encoded certificates", caFile)
+	}
+	return pool, nil
+}
+
+// mutatingMethods are the HTTP methods requireClientCertForMutations guards;
+// everything else (GET, HEAD, OPTIONS) remains readable by any client the
+// TLS listener accepts, since ClientAuth is configured as
+// tls.VerifyClientCertIfGiven rather than tls.RequireAndVerifyClientCert.
+var mutatingMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// requireClientCertForMutations rejects mutating requests that did not
+// present a client certificate verified against the configured client CA.
+// It is only installed on the router when --tls-client-ca is set.
+func requireClientCertForMutations(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if mutatingMethods[req.Method] {
+			if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+				http.Error(w, "a client certificate verified against the configured CA is required for this request", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
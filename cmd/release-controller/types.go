@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/blang/semver"
 	imagev1 "github.com/openshift/api/image/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // Release holds information about the release used during processing.
@@ -38,6 +40,12 @@ type ReleaseConfig struct {
 	// and published elsewhere. When choosing Stable, a user will tag a candidate release
 	// image in as a new tag to this image stream and the controller will rebuild and
 	// update the image with the appropriate name, metadata, and content.
+	//
+	// "Hotfix" is a narrower variant of Integration for targeted fixes to an existing
+	// release: it requires Name to end in "-hotfix-{id}", requires at least one
+	// mandatory (non-Optional) Verify step, ignores Publish (a hotfix is promoted by
+	// hand, not automatically), and enforces a long minimum Expires so the fix remains
+	// available for as long as the incident it addresses is open.
 	As string `json:"as"`
 
 	// To is the image stream where release tags will be created when the As field is
@@ -58,20 +66,147 @@ type ReleaseConfig struct {
 	// MirrorPrefix is the name of the source image stream + the date.
 	MirrorPrefix string `json:"mirrorPrefix"`
 
+	// MirrorTTL is the amount of time as a golang duration before a release mirror
+	// image stream should be garbage collected, regardless of whether its release
+	// tag is still active. If unset, mirrors are only collected once their release
+	// tag is orphaned, as before. Use this to bound how long mirrored content for a
+	// long-lived Accepted tag continues to occupy registry storage.
+	MirrorTTL Duration `json:"mirrorTTL,omitempty"`
+
 	// OverrideCLIImage may be used to override the location where the CLI image is
 	// located for actions on this image stream. It is useful when a bug prevents a
 	// historical image from being used with newer functionality.
 	OverrideCLIImage string `json:"overrideCLIImage"`
 
+	// PayloadJob, if set, controls scheduling and resource usage of the Job pods
+	// that assemble this stream's release payloads (see newReleaseJobBase), for
+	// streams whose payload is large enough to need more than the cluster's
+	// defaults, or whose builds should avoid congested nodes. It has no effect
+	// unless PayloadEngine builds the payload with a Job.
+	PayloadJob *PayloadJobConfig `json:"payloadJob,omitempty"`
+
+	// PayloadEngine selects how this stream's release payloads are built from its
+	// mirrored input images. The default, "" (equivalent to "oc"), runs
+	// `oc adm release new` in a Job exactly as before. "import" instead treats the
+	// mirrored tag itself as the finished payload, for streams whose payload is
+	// actually assembled by an external pipeline (e.g. a Konflux/Tekton build)
+	// that pushes the result into the source image stream rather than having this
+	// controller assemble it. See payloadEngine.
+	PayloadEngine string `json:"payloadEngine,omitempty"`
+
 	// Expires is the amount of time as a golang duration before Accepted release tags
 	// should be expired and removed. If unset, tags are not expired.
 	Expires Duration `json:"expires"`
 
+	// VersionScheme declares how tag names in this stream should be parsed and
+	// compared for sorting, latest-tag computation, and upgrade-source selection.
+	// One of "semver" (the default), "date", or "numeric". Streams that don't use
+	// semantic versioning (e.g. date-stamped or build-numbered internal streams) can
+	// set this instead of having their tags silently excluded from version-ordered
+	// views whenever they fail to parse as semver.
+	VersionScheme string `json:"versionScheme,omitempty"`
+
+	// ExpectAcceptedEvery is a golang duration (e.g. "24h") declaring the freshness
+	// SLA for this stream: an Accepted tag is expected at least this often. If unset,
+	// no SLA is enforced. When the most recent Accepted tag is older than this window,
+	// the stream is considered in SLA breach, which is surfaced on the dashboard and
+	// exported as a metric.
+	ExpectAcceptedEvery Duration `json:"expectAcceptedEvery,omitempty"`
+
+	// StorageQuotaBytes, if set, declares the registry storage this stream's tags are
+	// expected to stay under. It is checked by the periodic storage reporter, which
+	// sums the distinct image layers referenced by the stream's tags; a stream over
+	// quota is surfaced on the dashboard and exported as a metric so it can be
+	// remediated before a payload push starts failing for lack of registry space.
+	StorageQuotaBytes int64 `json:"storageQuotaBytes,omitempty"`
+
+	// Certifications, if set, lists the external certification workflows (e.g.
+	// "fips", "conformance") that may be tracked for this stream's accepted tags
+	// via /api/v1/releasestream/{release}/certification/{tag}/{name}. If unset,
+	// no certification workflow can be recorded for tags in this stream.
+	Certifications []string `json:"certifications,omitempty"`
+
+	// TestBudget, if set, tracks this stream's ProwJob verification runtime against
+	// a configured budget and powers rebalancing suggestions (e.g. "job X consumed
+	// 34% of budget with 0 unique rejections in 30 days — consider optional")
+	// exposed at /api/v1/releasestream/{release}/budget.
+	TestBudget *TestBudgetConfig `json:"testBudget,omitempty"`
+
+	// UpgradeMatrix, if set, enables the synthetic upgrade-matrix planner for this
+	// stream: it schedules ProwJob to run whenever the latest Accepted tag has no
+	// recorded upgrade edge from the latest Accepted tag of an earlier minor version,
+	// so upgrade graph coverage doesn't depend solely on edges exercised by new tags.
+	UpgradeMatrix *UpgradeMatrixConfig `json:"upgradeMatrix,omitempty"`
+
 	// Verify is a map of short names to verification steps that must succeed before the
 	// release is Accepted. Failures for some job types will cause the release to be
 	// rejected.
 	Verify map[string]ReleaseVerification `json:"verify"`
 
+	// VerificationProfiles, if set, names subsets of Verify that ProfileSchedule can
+	// select between, so a stream can run a reduced blocking set off-hours while
+	// running the full set during the day. If unset, or if ProfileSchedule has no
+	// matching rule for a tag's creation time, every enabled Verify step runs.
+	VerificationProfiles map[string]VerificationProfile `json:"verificationProfiles,omitempty"`
+
+	// ProfileSchedule selects the VerificationProfiles entry a newly created release
+	// tag runs under, based on the tag's creation time. The first matching rule wins.
+	// The profile applied to a tag is fixed at creation time and recorded on
+	// releaseAnnotationVerificationProfile, so it does not change if the schedule or
+	// profile definitions are edited later.
+	ProfileSchedule []VerificationScheduleRule `json:"profileSchedule,omitempty"`
+
+	// ShareVerificationAcrossDuplicates, if true, allows a ProwJob verification step
+	// to be satisfied by reusing the terminal result already recorded for a tag in
+	// another stream that carries an identical payload image hash (as surfaced by
+	// /api/v1/releasestream/{release}/duplicates/{tag}), instead of running a
+	// redundant job, as long as that other stream also sets this field and defines a
+	// verify step of the same name backed by the same ProwJob. Both streams must opt
+	// in, since sharing a result implicitly trusts the other stream's step
+	// definition to mean the same thing.
+	ShareVerificationAcrossDuplicates bool `json:"shareVerificationAcrossDuplicates,omitempty"`
+
+	// VerificationPropagation, if set, declares this stream a pure mirror of
+	// another stream that always carries an identical payload: rather than
+	// running its own verify suite, a Ready tag here waits for the matching tag
+	// (by releaseAnnotationImageHash) in VerificationPropagation.From to reach a
+	// terminal phase, then copies that phase and verification summary onto this
+	// tag verbatim, stamped with a provenance marker (see
+	// releaseAnnotationVerificationPropagatedFrom). Unlike
+	// ShareVerificationAcrossDuplicates, which lets individual verify steps
+	// reuse another stream's result as one input among several, this replaces
+	// the whole acceptance decision, so Verify should normally be left empty
+	// when this is set.
+	VerificationPropagation *VerificationPropagationConfig `json:"verificationPropagation,omitempty"`
+
+	// RiskWeights, if set, overrides defaultRiskScoreWeights when computing a
+	// RiskScore for this stream's tags, letting a stream with unusually large
+	// payloads or flaky infra de-emphasize the signals that are noisy for it.
+	RiskWeights *RiskScoreWeights `json:"riskWeights,omitempty"`
+
+	// Architecture identifies the CPU architecture this stream publishes for
+	// (e.g. "amd64", "arm64", "s390x", "ppc64le"). It is substituted into
+	// DownloadURLTemplate's {arch} token. Defaults to "amd64" if unset.
+	Architecture string `json:"architecture,omitempty"`
+
+	// DownloadURLTemplate, if set, overrides the process-wide --artifacts-host
+	// download link construction for this stream's tags with an explicit
+	// pattern, since a stream that mirrors its tools to its own location (e.g. a
+	// per-architecture CDN) produces dead links under the single shared
+	// --artifacts-host pattern. The tokens {tag}, {version}, and {arch} are
+	// substituted with the release tag's name, its parsed semantic version (or
+	// the tag name again if it isn't one), and Architecture, respectively. Must
+	// resolve to an absolute http(s) URL; this is checked at config parse time.
+	DownloadURLTemplate string `json:"downloadURLTemplate,omitempty"`
+
+	// Paused, if true, tells the controller to stop creating new release tags for
+	// this stream. Tags that already exist continue through verification, publish,
+	// and acceptance normally - pausing only stops the stream from picking up new
+	// input images. This is surfaced as the Paused stream condition so automation
+	// doesn't need to poll the config to notice a stream has been quiesced, e.g.
+	// ahead of planned maintenance on its inputs.
+	Paused bool `json:"paused,omitempty"`
+
 	// Publish is a map of short names to publish steps that will be performed after
 	// the release is Accepted. Some publish steps are continuously maintained, others
 	// may only be performed once.
@@ -80,6 +215,245 @@ type ReleaseConfig struct {
 	// Check is a map of short names to check routines that report additional information
 	// about the health or quality of this stream to the user interface.
 	Check map[string]ReleaseCheck `json:"check"`
+
+	// Archived marks a stream as no longer actively produced. Archived streams are
+	// still served by the UI and API but are resynced far less aggressively than
+	// Integration or Stable streams, since their tags rarely change.
+	Archived bool `json:"archived,omitempty"`
+
+	// CancelSupersededJobs, if true, aborts the still-running verification prow jobs
+	// of a Ready tag as soon as a newer tag in the same stream reaches Ready, since
+	// the older tag will never be the newest accepted release. Useful on fast-moving
+	// Integration streams to reclaim CI capacity.
+	CancelSupersededJobs bool `json:"cancelSupersededJobs,omitempty"`
+
+	// RetryBackoff overrides how long retryInfraFailure waits between automatic
+	// retries of a verification step whose ProwJob ended in an infrastructure
+	// failure (Errored or Aborted). If unset, the package defaults (1 minute
+	// initial, factor 2, 15 minute cap) are used. Streams with long-running
+	// jobs may want a longer cap to avoid retrying into the same congestion;
+	// fast streams may want a shorter initial delay to recover quickly.
+	RetryBackoff *RetryBackoff `json:"retryBackoff,omitempty"`
+
+	// UpgradeExclusions lists ranges of source versions that should never be advertised
+	// as an UpgradeFrom candidate for this stream, even if the graph has recorded
+	// successful upgrade results for them (e.g. a known-bad edge). Each exclusion is
+	// echoed back, with its reason, by the candidate API so consumers understand why an
+	// otherwise-successful edge was withheld.
+	UpgradeExclusions []UpgradeExclusion `json:"upgradeExclusions,omitempty"`
+
+	// ExternalImport, when set on a Stable stream, causes the controller to
+	// periodically import newly published tags from an external container image
+	// repository directly into this image stream, in place of a human running
+	// `oc tag` against each new release. It has no effect on non-Stable streams.
+	ExternalImport *ExternalImportConfig `json:"externalImport,omitempty"`
+
+	// Owners identifies who is responsible for this stream, so it can be rendered on
+	// the dashboard, included in notification payloads (e.g. ReleaseFreshnessSLABreached
+	// and VerificationFailedComponentOwners events), and exposed through the API for
+	// automation to page when the stream breaches its SLA.
+	Owners *ReleaseOwners `json:"owners,omitempty"`
+
+	// ComponentHealthGate, if set, causes the controller to skip creating a new
+	// release tag while a critical component is unhealthy, rather than building
+	// a payload that is effectively guaranteed to fail verification. Has no
+	// effect on Stable streams, which do not create tags from an input stream.
+	ComponentHealthGate *ComponentHealthGateConfig `json:"componentHealthGate,omitempty"`
+
+	// ImageSignatureGate, if set, causes the controller to skip creating a new
+	// release tag while any source component image is unsigned or fails to
+	// verify against TrustedKeys, rather than assembling a payload that
+	// supply-chain policy would reject anyway. Has no effect on Stable streams,
+	// which do not create tags from an input stream.
+	ImageSignatureGate *ImageSignatureGateConfig `json:"imageSignatureGate,omitempty"`
+
+	// Notifications, if set, sends a chat message to an external channel
+	// whenever one of this stream's tags transitions into one of Phases (see
+	// NotificationConfig). This is a richer alternative to the Kubernetes
+	// Events recorded today (e.g. ReleaseFreshnessSLABreached) for teams that
+	// watch Slack or Teams rather than `oc get events`.
+	Notifications []NotificationConfig `json:"notifications,omitempty"`
+
+	// AcceptanceCircuitBreaker, if set, pauses creation of new release tags
+	// once this stream's most recent payloads have been Rejected
+	// ConsecutiveRejections times in a row, rather than continuing to spend
+	// CI capacity building payloads into a verification environment that is
+	// evidently broken. See sync_circuit_breaker.go.
+	AcceptanceCircuitBreaker *AcceptanceCircuitBreakerConfig `json:"acceptanceCircuitBreaker,omitempty"`
+}
+
+// AcceptanceCircuitBreakerConfig configures the stream's acceptance circuit
+// breaker. See ReleaseConfig.AcceptanceCircuitBreaker.
+type AcceptanceCircuitBreakerConfig struct {
+	// ConsecutiveRejections is how many consecutive Rejected payloads trip
+	// the breaker. Must be at least 1.
+	ConsecutiveRejections int `json:"consecutiveRejections"`
+}
+
+// NotificationConfig sends a chat message via an incoming webhook when one of
+// this stream's tags transitions to one of Phases. See notification.go for
+// the set of supported Providers and message formats.
+type NotificationConfig struct {
+	// Provider selects the message format to send. One of "slack" or "teams".
+	Provider string `json:"provider"`
+
+	// URL is the incoming webhook URL to POST the formatted message to.
+	// Exactly one of URL and SecretName must be set.
+	URL string `json:"url,omitempty"`
+
+	// SecretName, if set, names a Secret in the controller's job namespace
+	// whose "webhookUrl" key holds the incoming webhook URL, so it need not be
+	// stored in plaintext in this annotation. Exactly one of URL and
+	// SecretName must be set.
+	SecretName string `json:"secretName,omitempty"`
+
+	// Phases limits delivery to these phases. Defaults to Ready, Accepted,
+	// Rejected, and Failed if unset.
+	Phases []string `json:"phases,omitempty"`
+}
+
+// ComponentHealthGateConfig describes how to determine whether this stream's
+// components are healthy enough to start a new release.
+type ComponentHealthGateConfig struct {
+	// Endpoint, if set, is queried with an HTTP GET before creating a new
+	// release tag. It must respond with a JSON object mapping component name
+	// to boolean healthy state (true meaning healthy). It is only consulted
+	// when the source image stream does not carry releaseAnnotationComponentHealth.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CriticalComponents restricts which named components, reported unhealthy
+	// by Endpoint or releaseAnnotationComponentHealth, block release creation.
+	// If empty, any reported unhealthy component blocks creation.
+	CriticalComponents []string `json:"criticalComponents,omitempty"`
+}
+
+// ImageSignatureGateConfig describes how to verify source component images'
+// simple-signing signatures before a release payload is assembled from them.
+type ImageSignatureGateConfig struct {
+	// SignatureBaseURL is the base address of the atomic/simple-signing
+	// sigstore serving signatures. A component image's signatures are looked
+	// up at <SignatureBaseURL>/<digest-algorithm>=<digest-hex>/signature-<n>,
+	// trying n = 1, 2, ... until a lookup 404s.
+	SignatureBaseURL string `json:"signatureBaseURL"`
+
+	// TrustedKeys lists the ASCII-armored OpenPGP public keys a component
+	// image's signature must verify against; at least one signature matching
+	// one key is sufficient. A component with no signature that verifies
+	// against any of these is reported as untrusted.
+	TrustedKeys []string `json:"trustedKeys"`
+}
+
+// ReleaseOwners identifies who is responsible for a release stream.
+type ReleaseOwners struct {
+	// Names lists the individuals or team names responsible for this stream.
+	Names []string `json:"names,omitempty"`
+	// SlackChannel is the channel to notify about this stream's health, without
+	// the leading "#" (e.g. "release-controller-alerts").
+	SlackChannel string `json:"slackChannel,omitempty"`
+	// EscalationLink points to the on-call or escalation procedure for this stream
+	// (e.g. a PagerDuty service or runbook URL).
+	EscalationLink string `json:"escalationLink,omitempty"`
+}
+
+// ExternalImportConfig describes a container image repository that should be
+// periodically scanned for new tags to adopt into a Stable release stream.
+type ExternalImportConfig struct {
+	// Repository is the pull spec of the repository to import from, e.g.
+	// "quay.io/openshift-release-dev/ocp-release". Only the DockerImage kind is
+	// supported.
+	Repository string `json:"repository"`
+
+	// Schedule is the amount of time as a golang duration between import attempts.
+	// If unset, defaults to 15m.
+	Schedule Duration `json:"schedule,omitempty"`
+}
+
+// UpgradeExclusion describes a set of source versions that must not be offered as an
+// upgrade source, independent of their recorded success rate.
+type UpgradeExclusion struct {
+	// Range is a semantic version range (as accepted by the blang/semver package, e.g.
+	// "<4.5.16") matched against the candidate source version.
+	Range string `json:"range"`
+	// Reason is a short human-readable explanation shown alongside the exclusion.
+	Reason string `json:"reason"`
+}
+
+// UpgradeMatrixConfig configures the synthetic upgrade-matrix planner for a stream.
+// See ReleaseConfig.UpgradeMatrix.
+type UpgradeMatrixConfig struct {
+	// ProwJob is the name of a periodic prow job (as in ReleaseVerification.ProwJob.Name)
+	// to run for each missing edge. It is run the same way an Upgrade verification step
+	// would be, receiving RELEASE_IMAGE_INITIAL and RELEASE_IMAGE_LATEST.
+	ProwJob string `json:"prowJob"`
+	// MaxPerDay bounds how many synthetic upgrade jobs this stream may schedule per
+	// day, so backfilling gaps can't flood the CI cluster. Defaults to
+	// defaultUpgradeMatrixMaxPerDay if unset.
+	MaxPerDay int `json:"maxPerDay,omitempty"`
+}
+
+// VerificationPropagationConfig configures a stream to copy its acceptance
+// decision from a parent stream's matching tag instead of verifying its own.
+// See ReleaseConfig.VerificationPropagation.
+type VerificationPropagationConfig struct {
+	// From is the release stream name (ReleaseConfig.Name) whose decisions this
+	// stream mirrors.
+	From string `json:"from"`
+}
+
+// PayloadJobConfig controls scheduling and resource usage of a stream's
+// payload creation Job pods. See ReleaseConfig.PayloadJob.
+type PayloadJobConfig struct {
+	// Resources sets the resource requests/limits on the Job's build container.
+	// If unset, the cluster's defaults apply, which is what large payload builds
+	// currently get OOM-killed under.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector constrains which nodes the Job's pod may be scheduled to.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allows the Job's pod to be scheduled onto nodes with matching
+	// taints, e.g. a dedicated pool of build nodes.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// EgressPolicyProfile, if set, is stamped as the
+	// release.openshift.io/egress-profile label on the Job's pod, so a
+	// cluster-admin-managed NetworkPolicy can select and constrain its egress by
+	// profile name. This controller does not create NetworkPolicy objects
+	// itself; the label is the extension point a cluster's own policies key off.
+	EgressPolicyProfile string `json:"egressPolicyProfile,omitempty"`
+}
+
+// TestBudgetConfig configures a rolling CI-spend budget for a stream's ProwJob
+// verification steps.
+type TestBudgetConfig struct {
+	// WindowDays is how many days of verification history contribute to the
+	// budget calculation. Defaults to testBudgetDefaultWindowDays if unset.
+	WindowDays int `json:"windowDays,omitempty"`
+	// Seconds is the total ProwJob runtime, summed across all verification steps
+	// in the stream, allowed within WindowDays before the stream is considered
+	// over budget.
+	Seconds int64 `json:"seconds"`
+}
+
+// VerificationProfile names a reduced subset of ReleaseConfig.Verify. See
+// ReleaseConfig.VerificationProfiles.
+type VerificationProfile struct {
+	// Only lists the Verify step names that run for a tag selected into this
+	// profile. A Verify step whose name is not listed here does not run,
+	// regardless of its own Disabled setting, for tags selected into this
+	// profile.
+	Only []string `json:"only"`
+}
+
+// VerificationScheduleRule selects a VerificationProfiles entry for release
+// tags created at a time matching Days and Hours. See
+// ReleaseConfig.ProfileSchedule.
+type VerificationScheduleRule struct {
+	// Profile is the VerificationProfiles key this rule selects.
+	Profile string `json:"profile"`
+	// Days restricts this rule to these weekdays, spelled as time.Weekday.String()
+	// returns them (e.g. "Saturday", "Sunday"). Empty matches every day.
+	Days []string `json:"days,omitempty"`
+	// Hours restricts this rule to these hours of the day, in UTC, 0-23. Empty
+	// matches every hour.
+	Hours []int `json:"hours,omitempty"`
 }
 
 type ReleaseCheck struct {
@@ -102,6 +476,68 @@ type ReleasePublish struct {
 	TagRef *PublishTagReference `json:"tagRef"`
 	// ImageStreamRef copies all images to another image stream in one transaction.
 	ImageStreamRef *PublishStreamReference `json:"imageStreamRef"`
+	// MirrorBundle writes an oc adm release mirror compatible mapping file and an
+	// ImageContentSourcePolicy manifest for the release, for disconnected-install
+	// tooling to consume.
+	MirrorBundle *PublishMirrorBundle `json:"mirrorBundle"`
+	// OCIArtifact writes an OCI artifact containing acceptance metadata, a
+	// verification summary, and a changelog pointer for the release, as an OCI
+	// Image Layout directory referencing the release payload digest via the
+	// subject field, for registry-native discovery of release quality data by
+	// cluster tooling once the layout is pushed to a registry.
+	OCIArtifact *PublishOCIArtifact `json:"ociArtifact"`
+
+	// Webhook POSTs a JSON payload describing the release to an external URL when
+	// it becomes Accepted, for triggering downstream pipelines without polling
+	// the HTTP API.
+	Webhook *PublishWebhook `json:"webhook,omitempty"`
+
+	// Type selects a PublishProvider registered under that name with
+	// RegisterPublishProvider, for publish steps that don't fit one of the built-in
+	// kinds above (e.g. an S3 upload or an internal catalog push). CustomConfig is
+	// passed to the provider verbatim.
+	Type string `json:"type,omitempty"`
+	// CustomConfig holds the configuration for the provider named by Type. Its
+	// contents are defined entirely by that provider.
+	CustomConfig map[string]string `json:"customConfig,omitempty"`
+}
+
+// PublishMirrorBundle describes where to write the mapping file and
+// ImageContentSourcePolicy manifest that disconnected-install tooling needs to mirror
+// a release into an environment with no direct access to the source registries.
+type PublishMirrorBundle struct {
+	// ArtifactDir is a local directory the release controller can write to. The
+	// mapping file and ImageContentSourcePolicy manifest for a release are written
+	// as <tag>-mapping.txt and <tag>-icsp.yaml.
+	ArtifactDir string `json:"artifactDir"`
+}
+
+// PublishOCIArtifact describes where to write the OCI Image Layout directory
+// for the acceptance-metadata artifact a release produces.
+type PublishOCIArtifact struct {
+	// ArtifactDir is a local directory the release controller can write to. The
+	// OCI Image Layout for the release is written as <tag>/ within it, ready to
+	// be pushed to a registry with tooling such as oras or skopeo.
+	ArtifactDir string `json:"artifactDir"`
+	// ArtifactType sets the manifest's artifactType. Defaults to
+	// "application/vnd.openshift.release-controller.acceptance.v1+json".
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// PublishWebhook describes an external HTTP callback to invoke when a release
+// becomes Accepted.
+type PublishWebhook struct {
+	// URL is the endpoint the JSON payload is POSTed to.
+	URL string `json:"url"`
+	// SecretName, if set, is the name of a Secret in the controller's job
+	// namespace whose "hmacSecret" key is used to sign the payload body with
+	// HMAC-SHA256, hex-encoded in the X-Release-Controller-Signature header -
+	// the same scheme httpImportExternalVerificationResult validates on the
+	// inbound side. If unset the payload is sent unsigned.
+	SecretName string `json:"secretName,omitempty"`
+	// MaxRetries bounds how many additional attempts are made if the endpoint
+	// doesn't return a 2xx status. Defaults to publishWebhookDefaultMaxRetries.
+	MaxRetries int `json:"maxRetries,omitempty"`
 }
 
 // PublishTagReference ensures that the release image stream has a tag that points to
@@ -110,6 +546,37 @@ type PublishTagReference struct {
 	// Name is the name of the release image stream tag that will be updated to point to
 	// (reference) the release tag.
 	Name string `json:"name"`
+
+	// Canary, if set, gates on consumer health feedback collected for a window after
+	// this tag is advanced, instead of trusting the advance unconditionally. See
+	// CanaryFeedback.
+	Canary *CanaryConfig `json:"canary,omitempty"`
+}
+
+// CanaryConfig configures canary feedback collection for a PublishTagReference.
+type CanaryConfig struct {
+	// Window is how long after the tag advances that registered consumers may
+	// report health feedback before the window closes and is evaluated.
+	Window Duration `json:"window"`
+	// AutoRevert, if true, points the tag back at the previously published
+	// release if any consumer reports unhealthy feedback before Window elapses.
+	AutoRevert bool `json:"autoRevert,omitempty"`
+}
+
+// RetryBackoff configures the exponential backoff retryInfraFailure applies
+// between automatic retries of an infrastructure-failed verification step.
+// See ReleaseConfig.RetryBackoff.
+type RetryBackoff struct {
+	// Initial is the delay before the first retry. Defaults to 1 minute.
+	Initial Duration `json:"initial,omitempty"`
+	// Factor multiplies the delay after each subsequent retry. Defaults to 2.
+	Factor float64 `json:"factor,omitempty"`
+	// Cap bounds how large the delay may grow. Defaults to 15 minutes.
+	Cap Duration `json:"cap,omitempty"`
+	// Jitter, if set, randomizes each computed delay by up to this fraction
+	// (0-1) so releases sharing a backoff schedule don't all retry in
+	// lockstep.
+	Jitter float64 `json:"jitter,omitempty"`
 }
 
 // PublishStreamReference updates another image stream with spec tags that reference the
@@ -125,6 +592,13 @@ type PublishStreamReference struct {
 	// ExcludeTags if set will explicitly not publish these tags. Is applied after the
 	// tags field is checked.
 	ExcludeTags []string `json:"excludeTags"`
+	// ServiceAccount, if set, causes the controller to impersonate
+	// system:serviceaccount:<Namespace>:<ServiceAccount> when updating the target
+	// image stream instead of using its own identity. This allows a namespace owned
+	// by another team to grant the controller just enough RBAC, bound to that one
+	// service account, rather than cluster-wide edit access. Requires the controller
+	// to have impersonate permission for that service account.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
 }
 
 // ReleaseVerification is a task that must be completed before a release is marked
@@ -151,10 +625,104 @@ type ReleaseVerification struct {
 	// If no matching target exists the job will be a no-op.
 	UpgradeFrom string `json:"upgradeFrom"`
 
+	// UpgradeFromSources, if set, fans this upgrade verification step out across
+	// multiple upgrade sources instead of the single one UpgradeFrom resolves:
+	// one ProwJob is run per entry (each an UpgradeFrom value: Previous,
+	// PreviousMinor, or PreviousPatch), and its result is tracked individually
+	// in VerificationStatus.Sources, combined per UpgradeAggregation. UpgradeFrom
+	// is ignored when this is set.
+	UpgradeFromSources []string `json:"upgradeFromSources,omitempty"`
+
+	// UpgradeAggregation controls how the per-source results in
+	// VerificationStatus.Sources combine into this step's overall State when
+	// UpgradeFromSources is set. One of:
+	//
+	// All - every source must succeed for the step to succeed (the default)
+	// Any - at least one source succeeding is enough
+	UpgradeAggregation string `json:"upgradeAggregation,omitempty"`
+
 	// ProwJob requires that the named ProwJob from the prow config pass before the
 	// release is accepted. The job is run only one time and if it fails the release
 	// is rejected.
 	ProwJob *ProwJobVerification `json:"prowJob"`
+
+	// Timeout, if set, bounds how long this step's ProwJob may run before the
+	// controller aborts it and treats the step the same as an infrastructure
+	// failure (Aborted), feeding into the same retry/backoff logic as any
+	// other Aborted job rather than blocking the release indefinitely. Has no
+	// effect on AggregatedProwJob or UpgradeFromSources copies, or on steps
+	// that aren't ProwJob-backed.
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// TargetArch, if set, causes RELEASE_IMAGE_LATEST to be resolved to the
+	// architecture-specific manifest digest for this verification, instead of the
+	// (possibly multi-arch) release tag. Use this to run the same verification job
+	// against several architectures from a single manifest-listed release.
+	TargetArch string `json:"targetArch,omitempty"`
+
+	// External marks this verification as reported by a system other than prow,
+	// such as Jenkins or GitLab CI. The controller does not run anything for this
+	// step; it waits for a result to be reported via ImportExternalVerificationResult
+	// (a signed webhook) or a well-known ConfigMap, and treats the step as
+	// Incomplete until one arrives.
+	External *ExternalVerification `json:"external,omitempty"`
+
+	// Metadata requires that the release payload's embedded metadata (version
+	// string, previous-release pointer, component tag completeness) pass a
+	// built-in check before the release is accepted. Unlike ProwJob or External,
+	// the controller evaluates this itself via `oc adm release info`; no job is
+	// created and no external report is waited for.
+	Metadata *MetadataVerification `json:"metadata,omitempty"`
+
+	// MinComponentAge requires every component image in the payload to be at
+	// least MinimumAge old before the release is accepted, giving
+	// component-level CI time to flag a bad build via the health source
+	// before this stream races to accept it. Like Metadata, this is a
+	// built-in check the controller evaluates itself; no job is created.
+	MinComponentAge *MinComponentAgeVerification `json:"minComponentAge,omitempty"`
+
+	// SerializeGroup, if set, names a resource group that this step's ProwJob
+	// contends for. At most one job across all tags and streams that share a
+	// SerializeGroup is ever started at a time; the rest are held at Blocked
+	// until the running one reaches a terminal state. Use this for jobs that
+	// depend on scarce, non-parallelizable external resources, such as a single
+	// bare-metal lab. Has no effect on steps that aren't ProwJob.
+	SerializeGroup string `json:"serializeGroup,omitempty"`
+
+	// AggregatedProwJob, if set, runs this step's ProwJob as AggregatedProwJob.Count
+	// independent copies instead of once, and accepts the step if at least
+	// AggregatedProwJob.Threshold of them succeed (e.g. 7 of 10). This suits jobs
+	// whose pass/fail is noisy enough that a single run is not a reliable signal.
+	// ProwJob must also be set; UpgradeFromSources is ignored when this is set.
+	AggregatedProwJob *AggregatedProwJobVerification `json:"aggregatedProwJob,omitempty"`
+
+	// RequiredEnv declares the environment variable names (e.g.
+	// RELEASE_IMAGE_LATEST, RELEASE_IMAGE_INITIAL, or a custom name) this
+	// step's periodic job is expected to define in its pod spec. It is
+	// validated against the periodic's actual pod spec the first time the
+	// verification job is created for a release tag: a periodic missing a
+	// declared variable is a configuration error and is reported as a
+	// terminal error instead of silently producing a misleading synthetic
+	// success job. A variable that is declared but whose value can't be
+	// resolved for this tag (e.g. RELEASE_IMAGE_INITIAL when no prior release
+	// exists yet) remains a no-op success, since that reflects a normal
+	// "nothing to verify yet" condition rather than a misconfiguration.
+	// Has no effect on steps that aren't ProwJob.
+	RequiredEnv []string `json:"requiredEnv,omitempty"`
+
+	// IssueTracker requires that no open blocker bug targeting this release's
+	// version is reported by an external issue tracker before the release is
+	// accepted. Like Metadata and MinComponentAge, this is a built-in check
+	// the controller evaluates itself; no job is created.
+	IssueTracker *IssueTrackerVerification `json:"issueTracker,omitempty"`
+}
+
+// ExternalVerification identifies a verification step whose result is reported by
+// a system outside the cluster rather than run by the controller.
+type ExternalVerification struct {
+	// Description is a human-readable note shown on the dashboard about where this
+	// result comes from (e.g. "Jenkins job acme-e2e"). Optional.
+	Description string `json:"description,omitempty"`
 }
 
 // ProwJobVerification identifies the name of a prow job that will be used to
@@ -164,13 +732,118 @@ type ProwJobVerification struct {
 	Name string `json:"name"`
 }
 
+// AggregatedProwJobVerification configures a verification step to launch
+// multiple parallel copies of the same ProwJob and accept the step based on
+// how many of them succeed, rather than on a single pass/fail run.
+type AggregatedProwJobVerification struct {
+	// Count is the number of parallel copies of the step's ProwJob to run.
+	// Must be greater than zero.
+	Count int `json:"count"`
+	// Threshold is the number of copies that must succeed for the step to be
+	// accepted as succeeded. Must be greater than zero and no greater than
+	// Count.
+	Threshold int `json:"threshold"`
+}
+
+// MetadataVerification enables the controller's built-in release metadata
+// check. It has no fields today; its presence on a verification step is what
+// selects the check, the same way a non-nil ProwJob or External selects those
+// mechanisms.
+type MetadataVerification struct{}
+
+// MinComponentAgeVerification configures the controller's built-in minimum
+// component age check. See ReleaseVerification.MinComponentAge.
+type MinComponentAgeVerification struct {
+	// MinimumAge is how old every component image in the payload must be.
+	MinimumAge Duration `json:"minimumAge"`
+}
+
+// IssueTrackerVerification configures the controller's built-in blocker bug
+// check. See ReleaseVerification.IssueTracker. The tracker is queried through
+// a Bugzilla-compatible REST API (e.g. bugzilla.redhat.com); a tracker with a
+// different query contract, such as Jira, is not supported by this check.
+type IssueTrackerVerification struct {
+	// URL is the base address of the tracker's REST API, e.g.
+	// "https://bugzilla.redhat.com".
+	URL string `json:"url"`
+	// Product is the tracker product to search, e.g. "OpenShift Container Platform".
+	Product string `json:"product"`
+	// TargetRelease is the target_release value bugs must match, e.g. "4.15.0".
+	// If empty, the release tag's name is used, reduced to major.minor.patch
+	// when it parses as a semantic version.
+	TargetRelease string `json:"targetRelease,omitempty"`
+	// Severity is the minimum bug severity that counts as a blocker (e.g.
+	// "urgent", "high"); bugs below this threshold are ignored. One of the
+	// tracker's own severity values, compared case-insensitively.
+	Severity string `json:"severity"`
+}
+
 type VerificationStatus struct {
 	State string `json:"state"`
 	URL   string `json:"url"`
+
+	// Message, if set, is a short human-readable explanation for this step's
+	// result beyond its State, such as which payload component failed a
+	// built-in check. Set by MinComponentAge and IssueTracker results and, as
+	// its pass count, by an AggregatedProwJob step; other ProwJob-backed
+	// results rely on State and URL (the job's own log) instead.
+	Message string `json:"message,omitempty"`
+
+	// TimedOut is true if this step's ProwJob was aborted by
+	// abortTimedOutVerificationJob for exceeding its configured Timeout,
+	// whether or not retryInfraFailure's retries of it were later exhausted
+	// into a terminal Failed result. See classifyRejectReason, which uses it
+	// to distinguish a rejection caused by a hung job from one caused by a
+	// genuine test failure.
+	TimedOut bool `json:"timedOut,omitempty"`
+
+	// Fingerprint is a short, stable signature of the failure signal found in
+	// this step's build log (see computeFailureFingerprint), set only when
+	// State is Failed and a log could be fetched. Two failures with the same
+	// Fingerprint are likely the same underlying issue, which
+	// consecutiveMatchingFailureFingerprints uses to surface a "same failure
+	// as the previous N tags" indicator.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Sources holds the per-source result when this step fans out across
+	// ReleaseVerification.UpgradeFromSources, keyed by source (the UpgradeFrom
+	// value that produced it, e.g. "PreviousMinor"), and the outer State is the
+	// aggregate of these per ReleaseVerification.UpgradeAggregation. For an
+	// AggregatedProwJob step it holds each copy's result keyed by its index
+	// ("0", "1", ...), and the outer State is decided by Threshold. Unset for a
+	// step that doesn't fan out.
+	Sources map[string]*VerificationStatus `json:"sources,omitempty"`
 }
 
 type VerificationStatusMap map[string]*VerificationStatus
 
+// Certification workflow states. A workflow starts at Submitted, may move to
+// InReview, and ends at either Certified or Rejected.
+const (
+	certificationStateSubmitted = "Submitted"
+	certificationStateInReview  = "InReview"
+	certificationStateCertified = "Certified"
+	certificationStateRejected  = "Rejected"
+)
+
+// CertificationEvent records one transition in a tag's certification workflow.
+type CertificationEvent struct {
+	State string `json:"state"`
+	At    string `json:"at"`
+	Note  string `json:"note,omitempty"`
+}
+
+// CertificationWorkflow tracks an external certification process (e.g. FIPS,
+// conformance) for a single accepted tag, opted into via ReleaseConfig.Certifications,
+// so status lives alongside the release record instead of a separate spreadsheet.
+type CertificationWorkflow struct {
+	State   string               `json:"state"`
+	History []CertificationEvent `json:"history,omitempty"`
+}
+
+// CertificationWorkflowMap is keyed by certification name, e.g. "fips".
+type CertificationWorkflowMap map[string]*CertificationWorkflow
+
 type ReleasePromoteJobParameters struct {
 	// Parameters for promotion job described at
 	// https://github.com/openshift/aos-cd-jobs/blob/master/jobs/build/release/Jenkinsfile#L20-L81
@@ -186,6 +859,95 @@ type ReleaseCandidate struct {
 	ReleasePromoteJobParameters
 	CreationTime string                `json:"creationTime,omitempty"`
 	Tag          *imagev1.TagReference `json:"tag,omitempty"`
+	// ExcludedUpgradesFrom lists otherwise-successful upgrade sources that were
+	// withheld from UpgradeFrom because of a configured UpgradeExclusion.
+	ExcludedUpgradesFrom []ExcludedUpgrade `json:"excludedUpgradesFrom,omitempty"`
+	// Score is the weighted ranking score used to order candidates within a
+	// stream, broken out into its components so the promote pipeline can justify
+	// why one candidate was preferred over another.
+	Score *CandidateScore `json:"score,omitempty"`
+	// KnownIssues lists caveats already recorded against Tag, if any. See KnownIssue.
+	KnownIssues []KnownIssue `json:"knownIssues,omitempty"`
+}
+
+// CandidateScore breaks down the weighted score used to rank release candidates.
+// Total is the weighted sum of the other fields and is what candidates are sorted
+// by; the components are included so consumers don't have to recompute them to
+// understand the ranking.
+type CandidateScore struct {
+	VerifyPassRatio     float64 `json:"verifyPassRatio"`
+	UpgradeSuccessRatio float64 `json:"upgradeSuccessRatio"`
+	AgeHours            float64 `json:"ageHours"`
+	// Risk is this candidate's RiskScore, if it could be computed. It is
+	// best-effort: a candidate whose risk signals couldn't be gathered (e.g. a
+	// transient "oc adm release info" failure) still gets a Total, just
+	// without a risk penalty applied.
+	Risk  *RiskScore `json:"risk,omitempty"`
+	Total float64    `json:"total"`
+}
+
+// CandidateScoreWeights controls how much each CandidateScore component
+// contributes to the total. Weights need not sum to 1; they are only meaningful
+// relative to each other. Risk is subtracted from, rather than added to, the
+// total, since a higher RiskScore makes a candidate less attractive.
+type CandidateScoreWeights struct {
+	Verify  float64
+	Upgrade float64
+	Age     float64
+	Risk    float64
+}
+
+// defaultCandidateScoreWeights favors verification results and upgrade-edge
+// success over raw age, since a candidate that is merely older isn't necessarily
+// better tested. Risk is weighted modestly: it's a useful tie-breaker among
+// otherwise similar candidates, but shouldn't override a clear lead in
+// verification or upgrade success.
+var defaultCandidateScoreWeights = CandidateScoreWeights{Verify: 0.5, Upgrade: 0.4, Age: 0.1, Risk: 0.1}
+
+// RiskScore breaks down a machine-readable estimate of how risky it is to
+// promote a given release tag, combining signals that don't show up in
+// CandidateScore: verification results there measure whether steps passed,
+// not whether the payload moving is itself unusually large or fragile. Higher
+// is riskier. The components are included alongside Total so consumers (e.g.
+// an automated promotion gate) can explain or override the score.
+type RiskScore struct {
+	// OptionalFailures is the number of Optional verification steps that
+	// failed. Optional failures don't block acceptance, but repeated ones are
+	// a signal the payload is less safe than a clean run would suggest.
+	OptionalFailures int `json:"optionalFailures"`
+	// ChangelogLines is the line count of the changelog between this tag and
+	// the previous release, a cheap proxy for how much changed.
+	ChangelogLines int `json:"changelogLines"`
+	// NewComponents is the number of payload components whose image digest
+	// changed from the previous release.
+	NewComponents int `json:"newComponents"`
+	// InfraRetries is the total number of infrastructure-failure retries (see
+	// releaseAnnotationInfraRetries) recorded across all verification steps.
+	InfraRetries int     `json:"infraRetries"`
+	Total        float64 `json:"total"`
+}
+
+// RiskScoreWeights controls how much each RiskScore component contributes to
+// the total. Weights need not sum to 1; they are only meaningful relative to
+// each other.
+type RiskScoreWeights struct {
+	OptionalFailures float64 `json:"optionalFailures"`
+	ChangelogSize    float64 `json:"changelogSize"`
+	NewComponents    float64 `json:"newComponents"`
+	InfraRetries     float64 `json:"infraRetries"`
+}
+
+// defaultRiskScoreWeights weighs optional-job failures and infra retries
+// heaviest, since both are direct evidence something went wrong, while
+// changelog size is the weakest signal, since a large changelog is often just
+// a routine batch of component bumps.
+var defaultRiskScoreWeights = RiskScoreWeights{OptionalFailures: 5, ChangelogSize: 0.01, NewComponents: 0.5, InfraRetries: 3}
+
+// ExcludedUpgrade records a source version that was deliberately left out of
+// ReleasePromoteJobParameters.UpgradeFrom along with the configured reason.
+type ExcludedUpgrade struct {
+	From   string `json:"from"`
+	Reason string `json:"reason"`
 }
 
 type ReleaseCandidateList struct {
@@ -215,6 +977,26 @@ func (m VerificationStatusMap) Incomplete(required map[string]ReleaseVerificatio
 	return names, len(names) > 0
 }
 
+// excludedUpgradeReason returns the configured reason if version matches one of the
+// stream's UpgradeExclusions, and false if the version is not excluded or does not
+// parse as a semantic version.
+func (cfg *ReleaseConfig) excludedUpgradeReason(version string) (string, bool) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return "", false
+	}
+	for _, exclusion := range cfg.UpgradeExclusions {
+		r, err := semver.ParseRange(exclusion.Range)
+		if err != nil {
+			continue
+		}
+		if r(v) {
+			return exclusion.Reason, true
+		}
+	}
+	return "", false
+}
+
 func allOptional(all map[string]ReleaseVerification, names ...string) bool {
 	for _, name := range names {
 		if v, ok := all[name]; ok && !v.Optional {
@@ -253,16 +1035,78 @@ const (
 	// phase back to Ready to retry and the controller will attempt verification again.
 	releasePhaseRejected = "Rejected"
 
+	// RejectReason buckets a Rejected tag's releaseAnnotationRejectReason
+	// annotation into a fixed set of categories, alongside the free-form
+	// releaseAnnotationReason/releaseAnnotationMessage, so analytics can
+	// report on why streams reject payloads over time without parsing
+	// message text. See classifyRejectReason.
+	RejectReasonBlockingJobFailed   = "BlockingJobFailed"
+	RejectReasonVerificationTimeout = "VerificationTimeout"
+	RejectReasonManualReject        = "ManualReject"
+	RejectReasonPolicyGate          = "PolicyGate"
+	RejectReasonUpgradeRegression   = "UpgradeRegression"
+
 	releaseVerificationStateSucceeded = "Succeeded"
 	releaseVerificationStateFailed    = "Failed"
 	releaseVerificationStatePending   = "Pending"
+	// releaseVerificationStateBlocked means the step's job has not been started
+	// because another job sharing its SerializeGroup is still running, or that
+	// a built-in MinComponentAge or IssueTracker check is not yet met (see the
+	// status's Message). It is treated the same as Pending for acceptance
+	// purposes; it gives the dashboard a more precise label than "Pending".
+	releaseVerificationStateBlocked = "Blocked"
+	// releaseVerificationStateErrored means the step's ProwJob ended in prow's
+	// ErrorState, an infrastructure failure (e.g. the job could not be
+	// scheduled) rather than a genuine test failure. Like Aborted, it is
+	// retried a bounded number of times by ensureVerificationJobs without
+	// counting against the step as a real failure; see releaseAnnotationInfraRetries.
+	releaseVerificationStateErrored = "Errored"
+	// releaseVerificationStateAborted means the step's ProwJob ended in prow's
+	// AbortedState. This covers an infrastructure-initiated abort, a timeout
+	// abort by abortTimedOutVerificationJob, and this controller's own
+	// cancelVerificationJobs (superseded releases). None is evidence the
+	// release itself is broken; the first two are retried the same as Errored,
+	// while a superseded job is not retried and is recorded as Failed.
+	releaseVerificationStateAborted = "Aborted"
+	// releaseVerificationStateUnknown means prowJobWatchdog found that a step's
+	// ProwJob was deleted out from under it (e.g. by prow's own GC, or an
+	// admin) while the step was still Pending, so no terminal state will ever
+	// arrive for it. It is not one of the states ensureVerificationJobs
+	// switches on explicitly, so the same fall-through that already handles
+	// an unrecognized status causes the step to be treated as needing work and
+	// recreated on the next sync, the same as if it had never run.
+	releaseVerificationStateUnknown = "Unknown"
 
 	releaseConfigModeStable = "Stable"
+	// releaseConfigModeHotfix marks a release as a targeted hotfix of another stream
+	// rather than a regular Integration stream. See ReleaseConfig.As.
+	releaseConfigModeHotfix = "Hotfix"
+
+	// hotfixNameSuffix is the required suffix for Hotfix release names, of the form
+	// {base}-hotfix-{id}, e.g. "4.10.5-hotfix-1".
+	hotfixNameSuffix = `-hotfix-[A-Za-z0-9]+$`
+
+	// hotfixMinExpires is the minimum Expires duration a Hotfix release config may
+	// set, reflecting how long a hotfix needs to remain available for the incident
+	// it addresses.
+	hotfixMinExpires = 30 * 24 * time.Hour
+	// hotfixDefaultExpires is applied to a Hotfix release config that does not set
+	// Expires itself.
+	hotfixDefaultExpires = 90 * 24 * time.Hour
+
+	// defaultUpgradeMatrixMaxPerDay is applied to an UpgradeMatrixConfig that does
+	// not set MaxPerDay itself.
+	defaultUpgradeMatrixMaxPerDay = 3
 
 	releaseUpgradeFromPreviousMinor = "PreviousMinor"
 	releaseUpgradeFromPreviousPatch = "PreviousPatch"
 	releaseUpgradeFromPrevious      = "Previous"
 
+	// releaseUpgradeAggregationAll and releaseUpgradeAggregationAny are the
+	// supported values of ReleaseVerification.UpgradeAggregation.
+	releaseUpgradeAggregationAll = "All"
+	releaseUpgradeAggregationAny = "Any"
+
 	// releaseAnnotationConfig is the JSON serialized representation of the ReleaseConfig
 	// struct. It is only accepted on image streams. An image stream with this annotation
 	// is considered an input image stream for creating releases.
@@ -278,24 +1122,84 @@ const (
 	releaseAnnotationPhase             = "release.openshift.io/phase"
 	releaseAnnotationCreationTimestamp = "release.openshift.io/creationTimestamp"
 	releaseAnnotationVerify            = "release.openshift.io/verify"
+	// releaseAnnotationVerificationProfile records the VerificationProfiles key a
+	// tag's verification steps were selected from, resolved once at Ready time from
+	// ReleaseConfig.ProfileSchedule and the tag's creation time. Empty means the full
+	// (unrestricted) set of enabled Verify steps was used. See VerificationProfile.
+	releaseAnnotationVerificationProfile = "release.openshift.io/verificationProfile"
+	// releaseAnnotationKnownIssues is the JSON serialized []KnownIssue attached to an
+	// accepted tag, combining notes added by hand (oc annotate) with ones recorded
+	// automatically for failed optional verification steps. See KnownIssue.
+	releaseAnnotationKnownIssues = "release.openshift.io/knownIssues"
+	// releaseAnnotationDeprecated is the JSON serialized DeprecationNotice attached to
+	// an accepted (even published) tag that has since been found harmful. See
+	// DeprecationNotice.
+	releaseAnnotationDeprecated = "release.openshift.io/deprecated"
+	// releaseAnnotationVerificationOnly marks a tag registered by registerExternalPayload:
+	// an externally hosted payload run through the normal verify/gate machinery for
+	// reporting purposes only. It is excluded from "latest"/promotion consideration
+	// wherever that matters, since it is not part of the release's real lineage.
+	releaseAnnotationVerificationOnly = "release.openshift.io/verificationOnly"
 	// if true, the release controller should rewrite this release
 	releaseAnnotationRewrite = "release.openshift.io/rewrite"
 	// an image stream with this annotation holds release tags
 	releaseAnnotationHasReleases = "release.openshift.io/hasReleases"
 	// if set, when rewriting a stable tag use the images locally
 	releaseAnnotationMirrorImages = "release.openshift.io/mirrorImages"
+	// releaseAnnotationCertifications is the JSON serialized CertificationWorkflowMap
+	// tracking external certification processes (e.g. FIPS, conformance) for an
+	// accepted tag. See CertificationWorkflow.
+	releaseAnnotationCertifications = "release.openshift.io/certifications"
 	// when set on a job, controls which queue the job is notified on
 	releaseAnnotationJobPurpose = "release.openshift.io/purpose"
 
 	releaseAnnotationReason  = "release.openshift.io/reason"
 	releaseAnnotationMessage = "release.openshift.io/message"
 	releaseAnnotationLog     = "release.openshift.io/log"
+	// releaseAnnotationRejectReason is set to one of the RejectReason*
+	// constants whenever a tag transitions to Rejected. See
+	// classifyRejectReason.
+	releaseAnnotationRejectReason = "release.openshift.io/rejectReason"
 
 	releaseAnnotationFromTag = "release.openshift.io/from-tag"
 	releaseAnnotationToTag   = "release.openshift.io/tag"
+
+	// releaseAnnotationVerifyInputs is the JSON serialized VerificationJobInputs
+	// snapshot of the resolved image, args, and env a verification ProwJob was
+	// created with. It is written once at creation time so a result can still
+	// be interpreted correctly after the periodic job config that produced it
+	// has since changed. See VerificationJobInputs.
+	releaseAnnotationVerifyInputs = "release.openshift.io/verify-inputs"
 	// releaseAnnotationFromImageStream specifies the imagestream
 	// a release was promoted from. It has the format <namespace>/<imagestream name>
 	releaseAnnotationFromImageStream = "release.openshift.io/from-image-stream"
+
+	// releaseAnnotationStuckRetries counts how many times stuckReleaseLoop has
+	// deleted and retried the create job for this tag because its pod could not
+	// be scheduled. Once the count exceeds stuckReleaseMaxRetries the tag is
+	// instead marked Failed.
+	releaseAnnotationStuckRetries = "release.openshift.io/stuckRetries"
+
+	// releaseAnnotationComponentHealth, if set on the source image stream by
+	// component CI, is a JSON object mapping component name to boolean healthy
+	// state. It is consulted by unhealthyComponents as an alternative to
+	// querying ReleaseConfig.ComponentHealthGate.Endpoint.
+	releaseAnnotationComponentHealth = "release.openshift.io/componentHealth"
+
+	// releaseAnnotationInfraRetries is a JSON object mapping verification step
+	// name to an infraRetryState recording how many times retryInfraFailure has
+	// retried it after an Errored or Aborted ProwJob result, bounded by
+	// maxVerificationInfraRetries, and when the last attempt was made, used to
+	// pace retries according to ReleaseConfig.RetryBackoff.
+	releaseAnnotationInfraRetries = "release.openshift.io/infraRetries"
+
+	// releaseAnnotationVerificationPropagatedFrom records, as "<stream>/<tag>",
+	// the parent tag a mirror stream's accept/reject decision and verification
+	// summary were copied from by propagatedVerificationDecision, per
+	// ReleaseConfig.VerificationPropagation. Its presence is the provenance
+	// marker distinguishing a propagated decision from one this controller
+	// actually verified.
+	releaseAnnotationVerificationPropagatedFrom = "release.openshift.io/verificationPropagatedFrom"
 )
 
 type Duration time.Duration
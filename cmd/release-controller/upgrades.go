@@ -2,314 +2,234 @@ package main
 
 import (
 	"bytes"
-	"compress/gzip"
-	"encoding/json"
-	"io"
-	"sort"
-	"sync"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 
-	"k8s.io/apimachinery/pkg/util/sets"
 	kv1core "k8s.io/client-go/kubernetes/typed/core/v1"
-)
-
-type UpgradeResult struct {
-	State string `json:"state"`
-	URL   string `json:"url"`
-}
 
-type UpgradeRecord struct {
-	From    string          `json:"from"`
-	To      string          `json:"to"`
-	Results []UpgradeResult `json:"results"`
-}
+	"github.com/openshift/release-controller/pkg/releasecontroller"
+)
 
-type UpgradeGraph struct {
-	lock sync.Mutex
-	to   map[string]map[string]*UpgradeHistory
-	from map[string]sets.String
-}
+// The upgrade graph itself lives in pkg/releasecontroller so that other tools can
+// depend on it without pulling in this package's Kubernetes clients. These aliases
+// keep every existing call site in this package unchanged.
+type UpgradeResult = releasecontroller.UpgradeResult
+type UpgradeRecord = releasecontroller.UpgradeRecord
+type UpgradeGraph = releasecontroller.UpgradeGraph
+type UpgradeHistory = releasecontroller.UpgradeHistory
 
 func NewUpgradeGraph() *UpgradeGraph {
-	return &UpgradeGraph{
-		to:   make(map[string]map[string]*UpgradeHistory),
-		from: make(map[string]sets.String),
-	}
+	return releasecontroller.NewUpgradeGraph()
 }
 
-type upgradeEdge struct {
-	From string
-	To   string
+// graphStorage abstracts over the Kubernetes object(s) an UpgradeGraph
+// snapshot is persisted into, so syncGraphToStorage can stay agnostic of
+// whether that's a Secret or a set of chunked ConfigMaps. The graph
+// implementation itself has no knowledge of either.
+type graphStorage interface {
+	// Load returns the most recently saved snapshot, or nil if none exists yet.
+	Load() ([]byte, error)
+	// Save replaces the persisted snapshot with data.
+	Save(data []byte) error
 }
 
-type UpgradeHistory struct {
-	From string
-	To   string
-
-	Success int
-	Failure int
-	Total   int
-
-	History map[string]UpgradeResult
-}
+// graphStorageKind selects a graphStorage implementation for
+// --upgrade-graph-storage. The default, "secret", is the original backend;
+// "configmap" exists for clusters whose Secret admission policy (e.g. size
+// or encryption-at-rest requirements) makes ConfigMaps the better fit, and
+// splits the snapshot across multiple objects because a gzipped graph can
+// outgrow the ~1MiB etcd object size both kinds of objects share.
+type graphStorageKind string
+
+const (
+	graphStorageSecret    graphStorageKind = "secret"
+	graphStorageConfigMap graphStorageKind = "configmap"
+)
 
-func (g *UpgradeGraph) SummarizeUpgradesTo(toNames ...string) []UpgradeHistory {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-	summaries := make([]UpgradeHistory, 0, len(toNames)*2)
-	for _, to := range toNames {
-		for _, h := range g.to[to] {
-			summaries = append(summaries, UpgradeHistory{
-				From:    h.From,
-				To:      to,
-				Success: h.Success,
-				Failure: h.Failure,
-				Total:   len(h.History),
-			})
-		}
+// newGraphStorage builds the graphStorage backend selected by kind. An empty
+// kind defaults to graphStorageSecret to preserve prior behavior.
+func newGraphStorage(kind graphStorageKind, secretClient kv1core.SecretsGetter, configMapClient kv1core.ConfigMapsGetter, ns, name string) (graphStorage, error) {
+	switch kind {
+	case "", graphStorageSecret:
+		return &secretGraphStorage{client: secretClient.Secrets(ns), ns: ns, name: name}, nil
+	case graphStorageConfigMap:
+		return &configMapGraphStorage{client: configMapClient.ConfigMaps(ns), ns: ns, name: name}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized upgrade graph storage %q, must be %q or %q", kind, graphStorageSecret, graphStorageConfigMap)
 	}
-	return summaries
 }
 
-func (g *UpgradeGraph) SummarizeUpgradesFrom(fromNames ...string) []UpgradeHistory {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-	summaries := make([]UpgradeHistory, 0, len(fromNames)*2)
-	for _, from := range fromNames {
-		for to := range g.from[from] {
-			for _, h := range g.to[to] {
-				summaries = append(summaries, UpgradeHistory{
-					From:    from,
-					To:      to,
-					Success: h.Success,
-					Failure: h.Failure,
-					Total:   len(h.History),
-				})
-			}
-		}
-	}
-	return summaries
+// secretGraphStorage is the original backend: the whole snapshot under the
+// "latest" key of a single, pre-existing Secret.
+type secretGraphStorage struct {
+	client kv1core.SecretInterface
+	ns     string
+	name   string
 }
 
-func (g *UpgradeGraph) UpgradesTo(toNames ...string) []UpgradeHistory {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-	summaries := make([]UpgradeHistory, 0, len(toNames)*2)
-	for _, to := range toNames {
-		for _, h := range g.to[to] {
-			summaries = append(summaries, UpgradeHistory{
-				From:    h.From,
-				To:      to,
-				Success: h.Success,
-				Failure: h.Failure,
-				Total:   len(h.History),
-				History: copyHistory(h.History),
-			})
-		}
+func (s *secretGraphStorage) Load() ([]byte, error) {
+	secret, err := s.client.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
 	}
-	return summaries
-}
-
-type historyEdgeReference struct {
-	from string
-	to   string
+	return secret.Data["latest"], nil
 }
 
-func (g *UpgradeGraph) UpgradesFrom(fromNames ...string) []UpgradeHistory {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-	summaries := make([]UpgradeHistory, 0, len(fromNames)*2)
-	refs := make(map[historyEdgeReference]*UpgradeHistory)
-	for _, from := range fromNames {
-		for to := range g.from[from] {
-			history := g.to[to][from]
-			if history == nil {
-				continue
-			}
-			key := historyEdgeReference{from, to}
-			ref, ok := refs[key]
-			if !ok {
-				summaries = append(summaries, UpgradeHistory{
-					From:    from,
-					To:      to,
-					History: make(map[string]UpgradeResult),
-				})
-				ref = &summaries[len(summaries)-1]
-				refs[key] = ref
-			}
-
-			ref.Success += history.Success
-			ref.Failure += history.Failure
-			ref.Total += len(history.History)
-			for k, v := range history.History {
-				ref.History[k] = v
-			}
-		}
+func (s *secretGraphStorage) Save(data []byte) error {
+	secret, err := s.client.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return err
 	}
-	return summaries
-}
-
-func copyHistory(h map[string]UpgradeResult) map[string]UpgradeResult {
-	copied := make(map[string]UpgradeResult, len(h))
-	for k, v := range h {
-		copied[k] = v
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
 	}
-	return copied
+	secret.Data["latest"] = data
+	_, err = s.client.Update(secret)
+	return err
 }
 
-func (g *UpgradeGraph) Add(fromTag, toTag string, results ...UpgradeResult) {
-	if len(results) == 0 || len(fromTag) == 0 || len(toTag) == 0 {
-		return
-	}
+// graphConfigMapChunkSize bounds each chunk ConfigMap well under the ~1MiB
+// etcd object size limit, leaving room for ObjectMeta and the BinaryData
+// base64 encoding overhead.
+const graphConfigMapChunkSize = 900 * 1024
+
+// configMapGraphStorage splits a snapshot across name-manifest (a "chunks"
+// count) and name-0, name-1, ... chunk ConfigMaps, unlike secretGraphStorage
+// which assumes one object is always big enough. Unlike Secrets, ConfigMaps
+// are not pre-provisioned here: all objects are created on first Save.
+type configMapGraphStorage struct {
+	client kv1core.ConfigMapInterface
+	ns     string
+	name   string
+}
 
-	g.lock.Lock()
-	defer g.lock.Unlock()
-	g.addWithLock(fromTag, toTag, results...)
+func (s *configMapGraphStorage) manifestName() string { return s.name + "-manifest" }
+func (s *configMapGraphStorage) chunkName(i int) string {
+	return fmt.Sprintf("%s-%d", s.name, i)
 }
 
-func (g *UpgradeGraph) addWithLock(fromTag, toTag string, results ...UpgradeResult) {
-	to, ok := g.to[toTag]
-	if !ok {
-		to = make(map[string]*UpgradeHistory)
-		g.to[toTag] = to
+func (s *configMapGraphStorage) Load() ([]byte, error) {
+	manifest, err := s.client.Get(s.manifestName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
 	}
-	from, ok := to[fromTag]
-	if !ok {
-		from = &UpgradeHistory{
-			From: fromTag,
-			To:   toTag,
-		}
-		to[fromTag] = from
-		set, ok := g.from[fromTag]
-		if !ok {
-			set = sets.NewString()
-			g.from[fromTag] = set
-		}
-		set.Insert(toTag)
+	if err != nil {
+		return nil, err
 	}
-	if from.History == nil {
-		from.History = make(map[string]UpgradeResult)
+	count, err := strconv.Atoi(manifest.Data["chunks"])
+	if err != nil {
+		return nil, fmt.Errorf("manifest configmap %s/%s has an invalid chunk count: %v", s.ns, s.manifestName(), err)
 	}
-	for _, result := range results {
-		if len(result.URL) == 0 {
-			continue
-		}
-		existing, ok := from.History[result.URL]
-		if !ok || existing.State == releaseVerificationStatePending && result.State != releaseVerificationStatePending {
-			from.History[result.URL] = result
-			switch result.State {
-			case releaseVerificationStateFailed:
-				from.Failure++
-			case releaseVerificationStateSucceeded:
-				from.Success++
-			}
+	var data []byte
+	for i := 0; i < count; i++ {
+		chunk, err := s.client.Get(s.chunkName(i), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("missing chunk %d of %d for upgrade graph: %v", i, count, err)
 		}
+		data = append(data, chunk.BinaryData["data"]...)
 	}
+	return data, nil
 }
 
-func (g *UpgradeGraph) Histories() []UpgradeHistory {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-
-	results := make([]UpgradeHistory, 0, len(g.to)*5)
-	for _, targets := range g.to {
-		for _, history := range targets {
-			copied := *history
-			copied.History = nil
-			results = append(results, copied)
+func (s *configMapGraphStorage) Save(data []byte) error {
+	var chunks [][]byte
+	for len(data) > 0 {
+		n := graphConfigMapChunkSize
+		if n > len(data) {
+			n = len(data)
 		}
+		chunks = append(chunks, data[:n])
+		data = data[n:]
 	}
-	return results
-}
-
-func (g *UpgradeGraph) Records() []UpgradeRecord {
-	g.lock.Lock()
-	defer g.lock.Unlock()
-
-	records := make([]UpgradeRecord, 0, len(g.to)*5)
-	for to, targets := range g.to {
-		for from, history := range targets {
-			record := UpgradeRecord{From: from, To: to, Results: make([]UpgradeResult, 0, len(history.History))}
-			for _, result := range history.History {
-				record.Results = append(record.Results, result)
+	if len(chunks) == 0 {
+		chunks = [][]byte{{}}
+	}
+	for i, chunk := range chunks {
+		if err := s.applyChunk(i, chunk); err != nil {
+			return err
+		}
+	}
+	// remove any stale chunks a previous, larger snapshot left behind
+	for i := len(chunks); ; i++ {
+		if err := s.client.Delete(s.chunkName(i), &metav1.DeleteOptions{}); err != nil {
+			if errors.IsNotFound(err) {
+				break
 			}
-			records = append(records, record)
+			return err
 		}
 	}
-	return records
+	return s.applyManifest(len(chunks))
 }
 
-func (g *UpgradeGraph) Save(w io.Writer) error {
-	records := g.Records()
-
-	// put the records into a stable order
-	sort.Slice(records, func(i, j int) bool {
-		a, b := records[i], records[j]
-		if a.To == b.To {
-			return a.From < b.From
-		}
-		return a.To < b.To
-	})
-	for _, record := range records {
-		sort.Slice(record.Results, func(i, j int) bool {
-			return record.Results[i].URL < record.Results[j].URL
+func (s *configMapGraphStorage) applyChunk(i int, data []byte) error {
+	name := s.chunkName(i)
+	cm, err := s.client.Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err := s.client.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.ns},
+			BinaryData: map[string][]byte{"data": data},
 		})
-	}
-
-	data, err := json.Marshal(records)
-	if err != nil {
 		return err
 	}
-	gw := gzip.NewWriter(w)
-	if _, err := gw.Write(data); err != nil {
+	if err != nil {
 		return err
 	}
-	return gw.Close()
+	cm.BinaryData = map[string][]byte{"data": data}
+	_, err = s.client.Update(cm)
+	return err
 }
 
-func (g *UpgradeGraph) Load(r io.Reader) error {
-	gr, err := gzip.NewReader(r)
-	if err != nil {
+func (s *configMapGraphStorage) applyManifest(chunks int) error {
+	name := s.manifestName()
+	cm, err := s.client.Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err := s.client.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.ns},
+			Data:       map[string]string{"chunks": strconv.Itoa(chunks)},
+		})
 		return err
 	}
-	var records []UpgradeRecord
-	if err := json.NewDecoder(gr).Decode(&records); err != nil {
+	if err != nil {
 		return err
 	}
-
-	g.lock.Lock()
-	defer g.lock.Unlock()
-
-	for _, record := range records {
-		g.addWithLock(record.From, record.To, record.Results...)
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
 	}
+	cm.Data["chunks"] = strconv.Itoa(chunks)
+	_, err = s.client.Update(cm)
 	return err
 }
 
-func syncGraphToSecret(graph *UpgradeGraph, update bool, secretClient kv1core.SecretInterface, ns, name string, stopCh <-chan struct{}) {
+// syncGraphToStorage is the Kubernetes-specific wiring that loads an
+// UpgradeGraph from storage on start and, if update is set, periodically
+// snapshots it back. The backend (Secret or chunked ConfigMaps) is selected
+// by the caller via newGraphStorage.
+func syncGraphToStorage(graph *UpgradeGraph, update bool, storage graphStorage, stopCh <-chan struct{}) {
 	// read initial state
 	wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
-		secret, err := secretClient.Get(name, metav1.GetOptions{})
+		data, err := storage.Load()
 		if err != nil {
 			if errors.IsNotFound(err) {
-				glog.Errorf("No secret %s/%s exists to store upgrade state into", ns, name)
+				glog.Errorf("No existing upgrade graph storage found to load from")
 				return false, nil
 			}
 			if errors.IsForbidden(err) {
-				glog.Errorf("Release controller doesn't have permission to get secret %s/%s to store upgrade state into", ns, name)
+				glog.Errorf("Release controller doesn't have permission to read upgrade graph storage")
 				return false, nil
 			}
-			glog.Errorf("Can't load initial state from secret %s/%s: %v", ns, name, err)
+			glog.Errorf("Can't load initial upgrade graph state: %v", err)
 			return false, nil
 		}
-		if data := secret.Data["latest"]; len(data) > 0 {
+		if len(data) > 0 {
 			if err := graph.Load(bytes.NewReader(data)); err != nil {
-				glog.Errorf("Can't load initial state from secret %s/%s: %v", ns, name, err)
+				glog.Errorf("Can't load initial upgrade graph state: %v", err)
 			}
 		}
 		return true, nil
@@ -322,7 +242,7 @@ func syncGraphToSecret(graph *UpgradeGraph, update bool, secretClient kv1core.Se
 	// wait a bit of time to let any other loops load what they can
 	time.Sleep(15 * time.Second)
 
-	// keep the secret up to date
+	// keep storage up to date
 	buf := &bytes.Buffer{}
 	wait.Until(func() {
 		buf.Reset()
@@ -330,18 +250,10 @@ func syncGraphToSecret(graph *UpgradeGraph, update bool, secretClient kv1core.Se
 			glog.Errorf("Unable to calculate graph state: %v", err)
 			return
 		}
-		secret, err := secretClient.Get(name, metav1.GetOptions{})
-		if err != nil {
-			glog.Errorf("Can't read latest secret %s/%s: %v", ns, name, err)
+		if err := storage.Save(buf.Bytes()); err != nil {
+			glog.Errorf("Can't save upgrade graph state: %v", err)
 			return
 		}
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
-		secret.Data["latest"] = buf.Bytes()
-		if _, err := secretClient.Update(secret); err != nil {
-			glog.Errorf("Can't save state to secret %s/%s: %v", ns, name, err)
-		}
-		glog.V(2).Infof("Saved upgrade graph state to %s/%s", ns, name)
+		glog.V(2).Infof("Saved upgrade graph state")
 	}, 5*time.Minute, stopCh)
 }
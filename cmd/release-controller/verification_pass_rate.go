@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// verificationPassRateWindow is how far back verificationPassRate looks for
+// prior runs of a verification step on this stream.
+const verificationPassRateWindow = 14 * 24 * time.Hour
+
+// verificationPassRate counts how many of olderTags created within window of
+// now recorded a final (Succeeded or Failed) result for verifyName, and how
+// many of those succeeded, so a failure on a step that almost always passes
+// reads very differently from one that is already flaky.
+func verificationPassRate(olderTags []*imagev1.TagReference, verifyName string, now time.Time, window time.Duration) (succeeded, total int) {
+	cutoff := now.Add(-window)
+	for _, t := range olderTags {
+		created, err := time.Parse(time.RFC3339, t.Annotations[releaseAnnotationCreationTimestamp])
+		if err != nil {
+			// can't place this tag in time; skip it rather than assuming
+			// it (and everything older behind it) is out of the window
+			continue
+		}
+		if created.Before(cutoff) {
+			break
+		}
+		status, err := decodeVerificationStatus(t)
+		if err != nil {
+			continue
+		}
+		s, ok := status[verifyName]
+		if !ok {
+			continue
+		}
+		switch s.State {
+		case releaseVerificationStateSucceeded:
+			total++
+			succeeded++
+		case releaseVerificationStateFailed:
+			total++
+		}
+	}
+	return succeeded, total
+}
+
+// renderVerificationPassRate writes the 14-day pass rate for verifyName,
+// computed from olderTags, next to its current state, so a failure on a
+// step that almost always passes reads very differently from one that is
+// already flaky. It writes nothing if there is no history to compute from.
+func renderVerificationPassRate(w io.Writer, olderTags []*imagev1.TagReference, verifyName string) {
+	succeeded, total := verificationPassRate(olderTags, verifyName, time.Now(), verificationPassRateWindow)
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(w, ` <span class="text-muted" title="%d of %d runs succeeded over the last 14 days">(%d%% over 14d, n=%d)</span>`,
+		succeeded, total, succeeded*100/total, total)
+}
+
+// VerificationPassRate is the response shape for httpVerificationPassRate,
+// and is also rendered inline next to a verification step's current state on
+// the release tag page.
+type VerificationPassRate struct {
+	Verification string `json:"verification"`
+	// Succeeded and Total count final (Succeeded or Failed) runs of
+	// Verification on this stream within the last 14 days, not including the
+	// tag the rate was requested for.
+	Succeeded int `json:"succeeded"`
+	Total     int `json:"total"`
+}
+
+// httpVerificationPassRate serves how often verification has succeeded on
+// this stream over the last 14 days, for triage context next to a tag's
+// current verification state.
+func (c *Controller) httpVerificationPassRate(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	release := vars["release"]
+	tag := vars["tag"]
+	verification := vars["verification"]
+
+	tags, ok := c.findReleaseStreamTags(true, tag)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unable to find release tag %s, it may have been deleted", tag), http.StatusNotFound)
+		return
+	}
+	info := tags[tag]
+	if len(release) > 0 && info.Release.Config.Name != release {
+		http.Error(w, fmt.Sprintf("Release tag %s does not belong to release %s", tag, release), http.StatusNotFound)
+		return
+	}
+
+	succeeded, total := verificationPassRate(info.Older, verification, time.Now(), verificationPassRateWindow)
+	result := VerificationPassRate{Verification: verification, Succeeded: succeeded, Total: total}
+
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
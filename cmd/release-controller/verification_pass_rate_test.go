@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+func tagWithVerification(t *testing.T, created string, state string) *imagev1.TagReference {
+	t.Helper()
+	annotations := map[string]string{}
+	if len(created) > 0 {
+		annotations[releaseAnnotationCreationTimestamp] = created
+	}
+	if len(state) > 0 {
+		annotations[releaseAnnotationVerify] = `{"e2e":{"state":"` + state + `"}}`
+	}
+	return &imagev1.TagReference{Annotations: annotations}
+}
+
+func TestVerificationPassRate(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2026-08-09T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to parse fixed now: %v", err)
+	}
+	window := 14 * 24 * time.Hour
+
+	t.Run("malformed timestamp is skipped, not treated as out-of-window", func(t *testing.T) {
+		olderTags := []*imagev1.TagReference{
+			tagWithVerification(t, "2026-08-08T00:00:00Z", releaseVerificationStateSucceeded),
+			// a tag with no parseable creation timestamp -- e.g. imported from
+			// outside this controller -- must not truncate the scan
+			tagWithVerification(t, "", releaseVerificationStateFailed),
+			tagWithVerification(t, "2026-08-01T00:00:00Z", releaseVerificationStateSucceeded),
+		}
+		succeeded, total := verificationPassRate(olderTags, "e2e", now, window)
+		if succeeded != 2 || total != 2 {
+			t.Errorf("verificationPassRate() = (%d, %d), want (2, 2)", succeeded, total)
+		}
+	})
+
+	t.Run("tag older than window stops the scan", func(t *testing.T) {
+		olderTags := []*imagev1.TagReference{
+			tagWithVerification(t, "2026-08-08T00:00:00Z", releaseVerificationStateSucceeded),
+			tagWithVerification(t, "2026-07-01T00:00:00Z", releaseVerificationStateSucceeded),
+			tagWithVerification(t, "2026-06-01T00:00:00Z", releaseVerificationStateFailed),
+		}
+		succeeded, total := verificationPassRate(olderTags, "e2e", now, window)
+		if succeeded != 1 || total != 1 {
+			t.Errorf("verificationPassRate() = (%d, %d), want (1, 1)", succeeded, total)
+		}
+	})
+}
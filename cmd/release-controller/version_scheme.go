@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blang/semver"
+)
+
+const (
+	// versionSchemeSemver is the default version scheme: tag names are parsed as
+	// semantic versions.
+	versionSchemeSemver = "semver"
+	// versionSchemeDate treats tag names as dates, so that streams built once a day
+	// (or less often) can still be sorted and compared like versions.
+	versionSchemeDate = "date"
+	// versionSchemeNumeric treats tag names as a plain, monotonically increasing
+	// build number.
+	versionSchemeNumeric = "numeric"
+)
+
+// dateVersionLayouts are the accepted tag name layouts for versionSchemeDate,
+// tried in order.
+var dateVersionLayouts = []string{
+	"2006-01-02T15-04-05",
+	"2006-01-02-150405",
+	time.RFC3339,
+	"20060102150405",
+	"2006-01-02",
+	"20060102",
+}
+
+// parseStreamVersion parses tagName into a semver.Version according to scheme, so
+// that the rest of the controller can keep sorting, selecting the latest tag, and
+// picking upgrade sources by comparing semver.Versions regardless of what version
+// scheme a given stream actually uses. An empty scheme means versionSchemeSemver.
+//
+// Tags that don't fit their stream's scheme return an error exactly as a
+// malformed semver tag would, rather than being treated as some other scheme.
+func parseStreamVersion(scheme, tagName string) (semver.Version, error) {
+	switch scheme {
+	case "", versionSchemeSemver:
+		return semver.Parse(tagName)
+	case versionSchemeDate:
+		for _, layout := range dateVersionLayouts {
+			if t, err := time.Parse(layout, tagName); err == nil {
+				return dateToVersion(t), nil
+			}
+		}
+		return semver.Version{}, fmt.Errorf("%q is not a recognized date for versionScheme %q", tagName, scheme)
+	case versionSchemeNumeric:
+		n, err := strconv.ParseUint(tagName, 10, 64)
+		if err != nil {
+			return semver.Version{}, fmt.Errorf("%q is not a numeric build number for versionScheme %q: %v", tagName, scheme, err)
+		}
+		return semver.Version{Patch: n}, nil
+	default:
+		return semver.Version{}, fmt.Errorf("unrecognized versionScheme %q", scheme)
+	}
+}
+
+// dateToVersion encodes t as a semver.Version that sorts identically to t, by
+// placing the Unix timestamp in Patch. Major and Minor are left zero so that
+// date-scheme versions never accidentally compare equal to a real semver
+// major.minor.
+func dateToVersion(t time.Time) semver.Version {
+	return semver.Version{Patch: uint64(t.Unix())}
+}
+
+// validVersionScheme reports whether scheme is empty or one of the recognized
+// versionScheme values.
+func validVersionScheme(scheme string) bool {
+	switch scheme {
+	case "", versionSchemeSemver, versionSchemeDate, versionSchemeNumeric:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,304 @@
+// Package releasecontroller holds core release-controller logic that has no
+// dependency on Kubernetes clients or informers, so it can be imported by other
+// tools (analyzers, bots) without pulling in the full controller binary.
+package releasecontroller
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+)
+
+// VerificationStateSucceeded and friends mirror the verification state strings used
+// throughout the release-controller annotations. They are duplicated here (rather
+// than imported) because this package must not depend on the cmd/release-controller
+// binary; the values are part of the stable on-disk/annotation format and are not
+// expected to change independently.
+const (
+	VerificationStateSucceeded = "Succeeded"
+	VerificationStateFailed    = "Failed"
+	VerificationStatePending   = "Pending"
+)
+
+// UpgradeResult is a single recorded outcome of an upgrade verification job.
+type UpgradeResult struct {
+	State string `json:"state"`
+	URL   string `json:"url"`
+}
+
+// UpgradeRecord is the serializable form of one edge's history, used by Save/Load.
+type UpgradeRecord struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Results []UpgradeResult `json:"results"`
+}
+
+// UpgradeGraph tracks the observed success and failure of upgrades between release
+// tags. It is safe for concurrent use.
+type UpgradeGraph struct {
+	lock sync.Mutex
+	to   map[string]map[string]*UpgradeHistory
+	from map[string]map[string]struct{}
+}
+
+// NewUpgradeGraph creates an empty graph.
+func NewUpgradeGraph() *UpgradeGraph {
+	return &UpgradeGraph{
+		to:   make(map[string]map[string]*UpgradeHistory),
+		from: make(map[string]map[string]struct{}),
+	}
+}
+
+// UpgradeHistory summarizes the results recorded for a single from/to edge.
+type UpgradeHistory struct {
+	From string
+	To   string
+
+	Success int
+	Failure int
+	Total   int
+
+	History map[string]UpgradeResult
+}
+
+func (g *UpgradeGraph) SummarizeUpgradesTo(toNames ...string) []UpgradeHistory {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	summaries := make([]UpgradeHistory, 0, len(toNames)*2)
+	for _, to := range toNames {
+		for _, h := range g.to[to] {
+			summaries = append(summaries, UpgradeHistory{
+				From:    h.From,
+				To:      to,
+				Success: h.Success,
+				Failure: h.Failure,
+				Total:   len(h.History),
+			})
+		}
+	}
+	return summaries
+}
+
+func (g *UpgradeGraph) SummarizeUpgradesFrom(fromNames ...string) []UpgradeHistory {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	summaries := make([]UpgradeHistory, 0, len(fromNames)*2)
+	for _, from := range fromNames {
+		for to := range g.from[from] {
+			for _, h := range g.to[to] {
+				summaries = append(summaries, UpgradeHistory{
+					From:    from,
+					To:      to,
+					Success: h.Success,
+					Failure: h.Failure,
+					Total:   len(h.History),
+				})
+			}
+		}
+	}
+	return summaries
+}
+
+func (g *UpgradeGraph) UpgradesTo(toNames ...string) []UpgradeHistory {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	summaries := make([]UpgradeHistory, 0, len(toNames)*2)
+	for _, to := range toNames {
+		for _, h := range g.to[to] {
+			summaries = append(summaries, UpgradeHistory{
+				From:    h.From,
+				To:      to,
+				Success: h.Success,
+				Failure: h.Failure,
+				Total:   len(h.History),
+				History: copyHistory(h.History),
+			})
+		}
+	}
+	return summaries
+}
+
+type historyEdgeReference struct {
+	from string
+	to   string
+}
+
+func (g *UpgradeGraph) UpgradesFrom(fromNames ...string) []UpgradeHistory {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	summaries := make([]UpgradeHistory, 0, len(fromNames)*2)
+	refs := make(map[historyEdgeReference]*UpgradeHistory)
+	for _, from := range fromNames {
+		for to := range g.from[from] {
+			history := g.to[to][from]
+			if history == nil {
+				continue
+			}
+			key := historyEdgeReference{from, to}
+			ref, ok := refs[key]
+			if !ok {
+				summaries = append(summaries, UpgradeHistory{
+					From:    from,
+					To:      to,
+					History: make(map[string]UpgradeResult),
+				})
+				ref = &summaries[len(summaries)-1]
+				refs[key] = ref
+			}
+
+			ref.Success += history.Success
+			ref.Failure += history.Failure
+			ref.Total += len(history.History)
+			for k, v := range history.History {
+				ref.History[k] = v
+			}
+		}
+	}
+	return summaries
+}
+
+func copyHistory(h map[string]UpgradeResult) map[string]UpgradeResult {
+	copied := make(map[string]UpgradeResult, len(h))
+	for k, v := range h {
+		copied[k] = v
+	}
+	return copied
+}
+
+// Add records one or more results for the from->to edge.
+func (g *UpgradeGraph) Add(fromTag, toTag string, results ...UpgradeResult) {
+	if len(results) == 0 || len(fromTag) == 0 || len(toTag) == 0 {
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.addWithLock(fromTag, toTag, results...)
+}
+
+func (g *UpgradeGraph) addWithLock(fromTag, toTag string, results ...UpgradeResult) {
+	to, ok := g.to[toTag]
+	if !ok {
+		to = make(map[string]*UpgradeHistory)
+		g.to[toTag] = to
+	}
+	from, ok := to[fromTag]
+	if !ok {
+		from = &UpgradeHistory{
+			From: fromTag,
+			To:   toTag,
+		}
+		to[fromTag] = from
+		set, ok := g.from[fromTag]
+		if !ok {
+			set = make(map[string]struct{})
+			g.from[fromTag] = set
+		}
+		set[toTag] = struct{}{}
+	}
+	if from.History == nil {
+		from.History = make(map[string]UpgradeResult)
+	}
+	for _, result := range results {
+		if len(result.URL) == 0 {
+			continue
+		}
+		existing, ok := from.History[result.URL]
+		if !ok || existing.State == VerificationStatePending && result.State != VerificationStatePending {
+			from.History[result.URL] = result
+			switch result.State {
+			case VerificationStateFailed:
+				from.Failure++
+			case VerificationStateSucceeded:
+				from.Success++
+			}
+		}
+	}
+}
+
+// Histories returns a flattened, order-unstable snapshot of every recorded edge's
+// summary (without per-result history, to keep callers that don't need it fast).
+func (g *UpgradeGraph) Histories() []UpgradeHistory {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	results := make([]UpgradeHistory, 0, len(g.to)*5)
+	for _, targets := range g.to {
+		for _, history := range targets {
+			copied := *history
+			copied.History = nil
+			results = append(results, copied)
+		}
+	}
+	return results
+}
+
+// Records returns every edge with its full per-result history, suitable for
+// persistence via Save.
+func (g *UpgradeGraph) Records() []UpgradeRecord {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	records := make([]UpgradeRecord, 0, len(g.to)*5)
+	for to, targets := range g.to {
+		for from, history := range targets {
+			record := UpgradeRecord{From: from, To: to, Results: make([]UpgradeResult, 0, len(history.History))}
+			for _, result := range history.History {
+				record.Results = append(record.Results, result)
+			}
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Save serializes the graph as gzip-compressed JSON.
+func (g *UpgradeGraph) Save(w io.Writer) error {
+	records := g.Records()
+
+	// put the records into a stable order
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.To == b.To {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	for _, record := range records {
+		sort.Slice(record.Results, func(i, j int) bool {
+			return record.Results[i].URL < record.Results[j].URL
+		})
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(w)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Load merges gzip-compressed JSON records (as produced by Save) into the graph.
+func (g *UpgradeGraph) Load(r io.Reader) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	var records []UpgradeRecord
+	if err := json.NewDecoder(gr).Decode(&records); err != nil {
+		return err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, record := range records {
+		g.addWithLock(record.From, record.To, record.Results...)
+	}
+	return err
+}
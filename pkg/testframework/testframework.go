@@ -0,0 +1,106 @@
+// Package testframework provides fixture builders for exercising the
+// release controller's reconciliation logic in tests, so config authors can
+// assert "this ReleaseConfig accepts/rejects under these verification
+// results" before rolling a config change out.
+//
+// The controller itself (cmd/release-controller, package main) cannot be
+// imported from here: Go does not allow importing a main package. This
+// package therefore supplies the half of the harness that is reusable from
+// anywhere - synthetic ImageStreams and ProwJobs using the exact annotation
+// contract the real controller reads and writes - and leaves constructing a
+// *Controller around fake clientsets and driving its sync loop to a test
+// living alongside it in cmd/release-controller, which can see the
+// unexported Controller type.
+//
+// This package does not itself vendor fake clientset constructors
+// (k8s.io/client-go/testing, and transitively the image and dynamic fake
+// clientsets) because this tree's vendor snapshot is missing
+// github.com/evanphx/json-patch, which those fake clientsets require to
+// build. Callers with a complete vendor tree can pass the objects built here
+// to fake.NewSimpleClientset and dynamicfake.NewSimpleDynamicClient
+// themselves.
+package testframework
+
+import (
+	"bytes"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	prowapiv1 "github.com/openshift/release-controller/pkg/prow/apiv1"
+)
+
+// ProwJobResource is the GroupVersionResource the release controller watches
+// ProwJobs through, matching cmd/release-controller/main.go.
+var ProwJobResource = schema.GroupVersionResource{Group: "prow.k8s.io", Version: "v1", Resource: "prowjobs"}
+
+// releaseAnnotationConfig mirrors cmd/release-controller's
+// release.openshift.io/config annotation key: the JSON-serialized
+// ReleaseConfig attached to a source ImageStream. Duplicated here (rather
+// than imported) because ReleaseConfig and its annotation constants live in
+// package main.
+const releaseAnnotationConfig = "release.openshift.io/config"
+
+// SourceImageStream builds a source ImageStream annotated with configJSON
+// (a JSON-serialized ReleaseConfig body) under release.openshift.io/config,
+// the same as an ImageStream a real cluster admin would opt into release
+// creation with.
+func SourceImageStream(namespace, name, configJSON string) *imagev1.ImageStream {
+	return &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{releaseAnnotationConfig: configJSON},
+		},
+	}
+}
+
+// TargetImageStream builds an empty target ImageStream for a release stream
+// to promote tags into.
+func TargetImageStream(namespace, name string) *imagev1.ImageStream {
+	return &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
+
+// ProwJobResult builds a completed ProwJob object as the release controller
+// would observe it in its dynamic informer, identified the same way
+// ensureProwJobForReleaseTag names jobs: "<releaseTag>-<verifyName>".
+func ProwJobResult(namespace, releaseTag, verifyName string, state prowapiv1.ProwJobState) *unstructured.Unstructured {
+	job := &prowapiv1.ProwJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "prow.k8s.io/v1", Kind: "ProwJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s-%s", releaseTag, verifyName),
+		},
+		Status: prowapiv1.ProwJobStatus{
+			State:          state,
+			CompletionTime: func() *metav1.Time { t := metav1.Now(); return &t }(),
+			URL:            fmt.Sprintf("https://prow.example.com/view/%s-%s", releaseTag, verifyName),
+		},
+	}
+	return objectToUnstructured(job)
+}
+
+// objectToUnstructured round-trips obj through JSON to produce the
+// *unstructured.Unstructured shape a dynamic client's informer would deliver,
+// mirroring cmd/release-controller's own helper of the same name.
+func objectToUnstructured(obj runtime.Object) *unstructured.Unstructured {
+	buf := &bytes.Buffer{}
+	if err := unstructured.UnstructuredJSONScheme.Encode(obj, buf); err != nil {
+		panic(err)
+	}
+	u := &unstructured.Unstructured{}
+	if _, _, err := unstructured.UnstructuredJSONScheme.Decode(buf.Bytes(), nil, u); err != nil {
+		panic(err)
+	}
+	return u
+}